@@ -15,7 +15,9 @@ import (
 	"strconv"
 	"time"
 
+	"goconverter/internal/converter"
 	"goconverter/internal/server"
+	"goconverter/internal/worker"
 )
 
 func main() {
@@ -26,6 +28,65 @@ func main() {
 	maxRequestBodyBytes := readEnvInt64("GO_CONVERTER_MAX_REQUEST_BODY_BYTES", int64(defaultMaxRequestBodyBytes))
 	maxConcurrentConversions := readEnvInt("GO_CONVERTER_MAX_CONCURRENT_CONVERSIONS", 4)
 	server.ConfigureRuntimeLimits(maxDecodedFileSizeBytes, maxRequestBodyBytes, maxConcurrentConversions)
+	server.ConfigureConversionTimeout(time.Duration(readEnvInt("GO_CONVERTER_MAX_CONVERSION_TIMEOUT_MS", 50000)) * time.Millisecond)
+	server.ConfigureJobs(
+		time.Duration(readEnvInt("GO_CONVERTER_MAX_JOB_TIMEOUT_MS", 600000))*time.Millisecond,
+		time.Duration(readEnvInt("GO_CONVERTER_JOB_RETENTION_SECONDS", 3600))*time.Second,
+		readEnvInt("GO_CONVERTER_MAX_ACTIVE_JOBS", 16),
+		readEnvInt64("GO_CONVERTER_MAX_JOB_RESULT_BYTES", 512*1024*1024),
+	)
+	converter.SetInputLimits(converter.InputLimits{
+		MaxPixels:      readEnvInt64("GO_CONVERTER_MAX_INPUT_PIXELS", converter.DefaultInputLimits.MaxPixels),
+		MaxTotalPixels: readEnvInt64("GO_CONVERTER_MAX_INPUT_TOTAL_PIXELS", converter.DefaultInputLimits.MaxTotalPixels),
+		MaxPages:       readEnvInt("GO_CONVERTER_MAX_INPUT_PAGES", converter.DefaultInputLimits.MaxPages),
+		MaxFrames:      readEnvInt("GO_CONVERTER_MAX_INPUT_FRAMES", converter.DefaultInputLimits.MaxFrames),
+	})
+
+	if path := readEnvString("GO_CONVERTER_EXTERNAL_TOOLS_CONFIG", ""); path != "" {
+		tools, err := converter.LoadExternalTools(path)
+		if err != nil {
+			log.Fatalf("external tools: %v", err)
+		}
+		converter.RegisterExternalTools(tools)
+		log.Printf("registered %d external tool(s) from %s", len(tools), path)
+	}
+
+	if dir := readEnvString("GO_CONVERTER_WASM_PLUGINS_DIR", ""); dir != "" {
+		plugins, err := converter.LoadWasmPlugins(dir, converter.WasmLimits{
+			MemoryBytes: readEnvInt64("GO_CONVERTER_WASM_MEMORY_BYTES", 256*1024*1024),
+			Timeout:     time.Duration(readEnvInt("GO_CONVERTER_WASM_TIMEOUT_MS", 30000)) * time.Millisecond,
+		})
+		if err != nil {
+			log.Fatalf("wasm plugins: %v", err)
+		}
+		converter.RegisterWasmPlugins(plugins)
+		log.Printf("registered %d wasm plugin(s) from %s", len(plugins), dir)
+	}
+
+	// Workers re-execute this binary and share the converter setup above.
+	if len(os.Args) > 1 && os.Args[1] == worker.Command {
+		if err := worker.Main(os.Args[2:]); err != nil {
+			log.Fatalf("worker: %v", err)
+		}
+		return
+	}
+
+	if workers := readEnvInt("GO_CONVERTER_WORKERS", 0); workers > 0 {
+		pool, err := worker.NewPool(worker.Config{
+			Workers: workers,
+			MaxJobs: readEnvInt("GO_CONVERTER_WORKER_MAX_JOBS", 1000),
+			Limits: worker.Limits{
+				MemoryBytes: readEnvInt64("GO_CONVERTER_WORKER_MEMORY_BYTES", 4*1024*1024*1024),
+				CPUSeconds:  readEnvInt("GO_CONVERTER_WORKER_CPU_SECONDS", 300),
+				Seccomp:     readEnvBool("GO_CONVERTER_WORKER_SECCOMP", false),
+			},
+		})
+		if err != nil {
+			log.Fatalf("worker pool: %v", err)
+		}
+		server.ConfigureWorkers(pool)
+		log.Printf("started %d conversion worker(s)", workers)
+	}
 
 	router := server.NewRouter()
 
@@ -82,3 +143,18 @@ func readEnvInt64(name string, fallback int64) int64 {
 
 	return parsedValue
 }
+
+func readEnvBool(name string, fallback bool) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	parsedValue, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("invalid %s=%q, using fallback %t", name, value, fallback)
+		return fallback
+	}
+
+	return parsedValue
+}
@@ -53,6 +53,88 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/combine": {
+            "post": {
+                "description": "Decodes the inputs in order, expanding multi-page ones, and writes every page to one PDF or multi-page TIFF.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "conversions"
+                ],
+                "summary": "Combine images into one document",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Conversion deadline in milliseconds; the shorter of this and timeoutMs applies",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Combine request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.CombineRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.CombineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/conversions": {
             "get": {
                 "produces": [
@@ -72,6 +154,47 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/conversions/{from}/{to}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "conversions"
+                ],
+                "summary": "Describe the options of a conversion pair",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source format",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target format",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.ConversionSchemaResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/convert": {
             "post": {
                 "consumes": [
@@ -85,6 +208,12 @@ const docTemplate = `{
                 ],
                 "summary": "Convert file",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Conversion deadline in milliseconds; the shorter of this and timeoutMs applies",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    },
                     {
                         "description": "Conversion request",
                         "name": "request",
@@ -97,7 +226,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "converted file, or ConvertExplainResponse when explain is set",
                         "schema": {
                             "$ref": "#/definitions/server.ConvertResponse"
                         }
@@ -117,17 +246,380 @@ const docTemplate = `{
                     "415": {
                         "description": "Unsupported Media Type",
                         "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/convert/{from}/{to}": {
+            "post": {
+                "description": "Takes the file as multipart/form-data, in the file field, or as the raw request body, and responds with the converted bytes.\nOptions are ConvertUploadOptions as JSON, the fields of ConvertRequest other than from, to, fileName, contentBase64, pageOutput and explain, which are rejected. They go in the options field of multipart uploads and in the X-Convert-Options header of raw ones.\nSelecting several pages responds with a zip archive of them.",
+                "consumes": [
+                    "multipart/form-data",
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "conversions"
+                ],
+                "summary": "Convert uploaded file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source format, or auto to detect it",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target format",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to convert, for multipart uploads",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conversion options as JSON, for multipart uploads",
+                        "name": "options",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Conversion options as JSON, for raw uploads",
+                        "name": "X-Convert-Options",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the uploaded file, for raw uploads",
+                        "name": "X-File-Name",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Conversion deadline in milliseconds; the shorter of this and timeoutMs applies",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "converted file",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "Content-Disposition": {
+                                "type": "string",
+                                "description": "attachment with the converted file name"
+                            },
+                            "X-Conversion-Backends": {
+                                "type": "string",
+                                "description": "comma separated backends of each hop"
+                            },
+                            "X-Conversion-Route": {
+                                "type": "string",
+                                "description": "comma separated formats the file went through"
+                            },
+                            "X-Detected-Format": {
+                                "type": "string",
+                                "description": "format detected from the file content"
+                            },
+                            "X-Frames": {
+                                "type": "integer",
+                                "description": "animation frames kept in the output"
+                            },
+                            "X-Image-Height": {
+                                "type": "integer",
+                                "description": "output height in pixels, when known"
+                            },
+                            "X-Image-Width": {
+                                "type": "integer",
+                                "description": "output width in pixels, when known"
+                            },
+                            "X-Metadata-Removed": {
+                                "type": "string",
+                                "description": "comma separated metadata blocks dropped"
+                            },
+                            "X-Page-Count": {
+                                "type": "integer",
+                                "description": "pages in the zip archive"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs": {
+            "post": {
+                "description": "Takes the request of /v1/convert and converts the file in the background, in the conversion slots /v1/convert uses. Poll the job for its status and progress, then download its result.\nSeveral pages are converted into a zip archive; pageOutput and explain do not apply.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Start conversion job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Conversion deadline in milliseconds, counted from when the job starts; the shorter of this and timeoutMs applies",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Conversion request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.ConvertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/server.JobResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the job"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get conversion job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.JobResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancels a queued or running job, which is kept as cancelled until it expires, or deletes a finished job and its result.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Cancel conversion job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "cancellation requested",
+                        "schema": {
+                            "$ref": "#/definitions/server.JobResponse"
+                        }
+                    },
+                    "204": {
+                        "description": "finished job deleted"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs/{id}/result": {
+            "get": {
+                "description": "Responds with the converted file and the headers of /v1/convert/{from}/{to}, until the job expires.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Download conversion job result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "converted file",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "Content-Disposition": {
+                                "type": "string",
+                                "description": "attachment with the converted file name"
+                            },
+                            "X-Conversion-Backends": {
+                                "type": "string",
+                                "description": "comma separated backends of each hop"
+                            },
+                            "X-Conversion-Route": {
+                                "type": "string",
+                                "description": "comma separated formats the file went through"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -137,9 +629,231 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "server.CombineInput": {
+            "type": "object",
+            "properties": {
+                "contentBase64": {
+                    "type": "string"
+                },
+                "fileName": {
+                    "type": "string",
+                    "example": "page-1.jpg"
+                }
+            }
+        },
+        "server.CombineRequest": {
+            "type": "object",
+            "properties": {
+                "dpi": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "fileName": {
+                    "type": "string",
+                    "example": "scans.pdf"
+                },
+                "fit": {
+                    "type": "string",
+                    "enum": [
+                        "shrink",
+                        "contain",
+                        "cover",
+                        "fill"
+                    ],
+                    "example": "contain"
+                },
+                "inputs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.CombineInput"
+                    }
+                },
+                "jpegQuality": {
+                    "type": "integer",
+                    "example": 85
+                },
+                "marginMm": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "orientation": {
+                    "type": "string",
+                    "enum": [
+                        "auto",
+                        "portrait",
+                        "landscape"
+                    ],
+                    "example": "auto"
+                },
+                "pageSize": {
+                    "type": "string",
+                    "enum": [
+                        "auto",
+                        "a3",
+                        "a4",
+                        "a5",
+                        "letter",
+                        "legal"
+                    ],
+                    "example": "a4"
+                },
+                "timeoutMs": {
+                    "type": "integer",
+                    "example": 30000
+                },
+                "to": {
+                    "type": "string",
+                    "enum": [
+                        "pdf",
+                        "tiff"
+                    ],
+                    "example": "pdf"
+                }
+            }
+        },
+        "server.CombineResponse": {
+            "type": "object",
+            "properties": {
+                "contentBase64": {
+                    "type": "string"
+                },
+                "fileName": {
+                    "type": "string",
+                    "example": "scans.pdf"
+                },
+                "mimeType": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "pages": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.CombineSource"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "pdf"
+                }
+            }
+        },
+        "server.CombineSource": {
+            "type": "object",
+            "properties": {
+                "detectedFormat": {
+                    "type": "string",
+                    "example": "jpeg"
+                },
+                "fileName": {
+                    "type": "string",
+                    "example": "page-1.jpg"
+                },
+                "pages": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "server.ConversionOptionSchema": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "type": "string",
+                    "example": "75"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "maximum": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "minimum": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "options.quality"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "integer",
+                        "number",
+                        "boolean",
+                        "string"
+                    ],
+                    "example": "integer"
+                }
+            }
+        },
+        "server.ConversionSchemaResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "png"
+                },
+                "lossy": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ConversionOptionSchema"
+                    }
+                },
+                "route": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "png",
+                        "jpeg"
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "jpeg"
+                }
+            }
+        },
         "server.ConversionsResponse": {
             "type": "object",
             "properties": {
+                "backends": {
+                    "description": "Backends lists the backends registered for each direct pair, in the\norder they are tried.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "deprecatedFormats": {
+                    "description": "DeprecatedFormats are sources still converted that have better named\nformats, e.g. magick for bmp or psd.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "magick"
+                    ]
+                },
                 "formats": {
                     "type": "object",
                     "additionalProperties": {
@@ -148,53 +862,381 @@ const docTemplate = `{
                             "type": "string"
                         }
                     }
+                },
+                "routes": {
+                    "description": "Routes maps canonical source and target formats to the planned route.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "server.ConvertGeometry": {
+            "type": "object",
+            "properties": {
+                "fit": {
+                    "type": "string",
+                    "example": "cover"
+                },
+                "gravity": {
+                    "type": "string",
+                    "example": "attention"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "scaledHeight": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "scaledWidth": {
+                    "type": "integer",
+                    "example": 427
+                },
+                "sourceHeight": {
+                    "type": "integer",
+                    "example": 1080
+                },
+                "sourceWidth": {
+                    "type": "integer",
+                    "example": 1920
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 320
+                }
+            }
+        },
+        "server.ConvertOptions": {
+            "type": "object",
+            "properties": {
+                "chromaSubsampling": {
+                    "type": "string",
+                    "enum": [
+                        "auto",
+                        "4:2:0",
+                        "4:4:4"
+                    ],
+                    "example": "4:2:0"
+                },
+                "compressionLevel": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "dpi": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "effort": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "lossless": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "pageSize": {
+                    "type": "string",
+                    "enum": [
+                        "auto",
+                        "a3",
+                        "a4",
+                        "a5",
+                        "letter",
+                        "legal"
+                    ],
+                    "example": "a4"
+                },
+                "progressive": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "quality": {
+                    "type": "integer",
+                    "example": 80
+                }
+            }
+        },
+        "server.ConvertPage": {
+            "type": "object",
+            "properties": {
+                "backends": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "libvips",
+                        "libvips"
+                    ]
+                },
+                "contentBase64": {
+                    "type": "string"
+                },
+                "fileName": {
+                    "type": "string",
+                    "example": "input-page-2.png"
+                },
+                "geometry": {
+                    "$ref": "#/definitions/server.ConvertGeometry"
+                },
+                "mimeType": {
+                    "type": "string",
+                    "example": "image/png"
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "server.ConvertRasterize": {
+            "type": "object",
+            "properties": {
+                "background": {
+                    "type": "string",
+                    "example": "#ffffff"
+                },
+                "dpi": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 768
+                },
+                "pdfBox": {
+                    "type": "string",
+                    "enum": [
+                        "media",
+                        "crop",
+                        "trim"
+                    ],
+                    "example": "crop"
+                },
+                "scale": {
+                    "type": "number",
+                    "example": 2
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 1024
                 }
             }
         },
         "server.ConvertRequest": {
             "type": "object",
             "properties": {
+                "autoRotate": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "colorSpace": {
+                    "type": "string",
+                    "enum": [
+                        "srgb",
+                        "p3",
+                        "keep"
+                    ],
+                    "example": "srgb"
+                },
                 "contentBase64": {
                     "type": "string"
                 },
+                "explain": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "fileName": {
                     "type": "string",
                     "example": "input.png"
                 },
+                "frame": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "from": {
                     "type": "string",
-                    "example": "png"
+                    "example": "auto"
+                },
+                "iccProfile": {
+                    "type": "string",
+                    "enum": [
+                        "embed",
+                        "omit"
+                    ],
+                    "example": "embed"
+                },
+                "metadata": {
+                    "type": "string",
+                    "enum": [
+                        "keep",
+                        "strip",
+                        "strip-private",
+                        "keep-icc"
+                    ],
+                    "example": "strip-private"
+                },
+                "options": {
+                    "$ref": "#/definitions/server.ConvertOptions"
+                },
+                "pageOutput": {
+                    "type": "string",
+                    "enum": [
+                        "array",
+                        "zip"
+                    ],
+                    "example": "array"
+                },
+                "pages": {
+                    "type": "string",
+                    "example": "1,3-5"
+                },
+                "rasterize": {
+                    "$ref": "#/definitions/server.ConvertRasterize"
+                },
+                "timeoutMs": {
+                    "type": "integer",
+                    "example": 30000
                 },
                 "to": {
                     "type": "string",
                     "example": "jpeg"
+                },
+                "transform": {
+                    "$ref": "#/definitions/server.ConvertTransform"
                 }
             }
         },
         "server.ConvertResponse": {
             "type": "object",
             "properties": {
+                "backends": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "libvips",
+                        "libvips"
+                    ]
+                },
                 "contentBase64": {
                     "type": "string"
                 },
+                "detectedFormat": {
+                    "type": "string",
+                    "example": "png"
+                },
                 "fileName": {
                     "type": "string",
                     "example": "input.jpeg"
                 },
+                "frames": {
+                    "type": "integer",
+                    "example": 12
+                },
                 "from": {
                     "type": "string",
                     "example": "png"
                 },
+                "geometry": {
+                    "$ref": "#/definitions/server.ConvertGeometry"
+                },
+                "metadataRemoved": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "exif-gps",
+                        "xmp"
+                    ]
+                },
                 "mimeType": {
                     "type": "string",
                     "example": "image/jpeg"
                 },
+                "pages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.ConvertPage"
+                    }
+                },
+                "route": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pdf",
+                        "png",
+                        "jpeg"
+                    ]
+                },
                 "to": {
                     "type": "string",
                     "example": "jpeg"
                 }
             }
         },
+        "server.ConvertTransform": {
+            "type": "object",
+            "properties": {
+                "background": {
+                    "type": "string",
+                    "example": "#ffffff"
+                },
+                "fit": {
+                    "type": "string",
+                    "enum": [
+                        "contain",
+                        "cover",
+                        "fill",
+                        "inside",
+                        "outside"
+                    ],
+                    "example": "cover"
+                },
+                "gravity": {
+                    "type": "string",
+                    "enum": [
+                        "centre",
+                        "north",
+                        "northeast",
+                        "east",
+                        "southeast",
+                        "south",
+                        "southwest",
+                        "west",
+                        "northwest",
+                        "attention",
+                        "entropy"
+                    ],
+                    "example": "attention"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 320
+                },
+                "withoutEnlargement": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
         "server.ErrorDetail": {
             "type": "object",
             "properties": {
@@ -204,7 +1246,7 @@ const docTemplate = `{
                 },
                 "message": {
                     "type": "string",
-                    "example": "conversion from png to pdf is not supported"
+                    "example": "conversion from png to svg is not supported"
                 },
                 "requestId": {
                     "type": "string",
@@ -228,6 +1270,62 @@ const docTemplate = `{
                     "example": "ok"
                 }
             }
+        },
+        "server.JobResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "$ref": "#/definitions/server.ErrorDetail"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is when a finished job and its result are deleted.",
+                    "type": "string"
+                },
+                "fileName": {
+                    "type": "string",
+                    "example": "input.png"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "pdf"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "4f1c2a9e8b7d4c3e9a6b5d4c3b2a1f0e"
+                },
+                "progress": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "resultUrl": {
+                    "type": "string",
+                    "example": "/v1/jobs/4f1c2a9e8b7d4c3e9a6b5d4c3b2a1f0e/result"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "queued",
+                        "running",
+                        "succeeded",
+                        "failed",
+                        "cancelled"
+                    ],
+                    "example": "running"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "png"
+                }
+            }
         }
     }
 }`
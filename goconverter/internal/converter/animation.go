@@ -0,0 +1,125 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+const animationLoadOptions = "[n=-1]"
+
+func SupportsFrameSelection(sourceFormat string) bool {
+	format, ok := LookupFormat(sourceFormat)
+	return ok && format.Animated
+}
+
+func PreservesAnimation(sourceFormat string, targetFormat string) bool {
+	if !SupportsFrameSelection(sourceFormat) {
+		return false
+	}
+	format, ok := LookupFormat(targetFormat)
+	return ok && format.AnimatedSave
+}
+
+func validateFrame(sourceFormat string, frame *int) error {
+	if frame == nil {
+		return nil
+	}
+	if !SupportsFrameSelection(sourceFormat) {
+		return invalidOptionsError("frame is not supported for %s input", sourceFormat)
+	}
+	if *frame < 1 {
+		return invalidOptionsError("frame must be 1 or greater")
+	}
+
+	return nil
+}
+
+func convertFrame(ctx context.Context, input []byte, options Options, saveOptions string, w io.Writer) (Result, error) {
+	frameCount, err := vipsPageCount(input)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := checkFrameCount(frameCount); err != nil {
+		return Result{}, err
+	}
+	if *options.Frame > frameCount {
+		return Result{}, invalidOptionsError("frame %d is out of range: the animation has %d frame(s)", *options.Frame, frameCount)
+	}
+
+	output, report, err := convertVipsImage(ctx, input, []string{pageLoadParameter(*options.Frame)}, wholePage, options, saveOptions, w)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return report.result(output), nil
+}
+
+// convertAnimation transforms every frame on its own so crops and padding
+// stay inside frame boundaries, then joins the frames back into a strip.
+func convertAnimation(ctx context.Context, input []byte, options Options, saveOptions string, w io.Writer) (Result, error) {
+	image, err := vipsLoadBuffer(input, animationLoadOptions)
+	if err != nil {
+		return Result{}, err
+	}
+	defer image.close()
+
+	frameHeight := image.frameHeight()
+	frameCount := image.height() / frameHeight
+	if err := checkFrameCount(frameCount); err != nil {
+		return Result{}, err
+	}
+	if err := checkImageSize(image.width(), frameHeight); err != nil {
+		return Result{}, err
+	}
+	if frameCount <= 1 {
+		report, err := processVipsImage(image, options)
+		if err != nil {
+			return Result{}, err
+		}
+		output, err := image.save(ctx, saveOptions, w)
+		if err != nil {
+			return Result{}, err
+		}
+		return report.result(output), nil
+	}
+
+	frames := make([]*vipsImage, 0, frameCount)
+	defer func() {
+		for _, frame := range frames {
+			frame.close()
+		}
+	}()
+
+	var report imageReport
+	for index := 0; index < frameCount; index++ {
+		frame, err := image.extractFrame(index, frameHeight)
+		if err != nil {
+			return Result{}, fmt.Errorf("frame %d: %w", index+1, err)
+		}
+		frames = append(frames, frame)
+
+		frameReport, err := processVipsImage(frame, options)
+		if err != nil {
+			return Result{}, fmt.Errorf("frame %d: %w", index+1, err)
+		}
+		if index == 0 {
+			report = frameReport
+		}
+	}
+
+	joined, err := vipsJoinFrames(frames)
+	if err != nil {
+		return Result{}, err
+	}
+	defer joined.close()
+
+	output, err := joined.save(ctx, saveOptions, w)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := report.result(output)
+	result.Frames = frameCount
+	return result, nil
+}
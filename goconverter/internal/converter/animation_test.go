@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidateOptionsFrame(t *testing.T) {
+	if err := ValidateOptions("gif", "png", Options{Frame: intPointer(2)}); err != nil {
+		t.Fatalf("expected frame to be accepted for gif input, got error: %v", err)
+	}
+
+	rejected := []struct {
+		source string
+		frame  int
+	}{
+		{source: "png", frame: 1},
+		{source: "gif", frame: 0},
+	}
+	for _, tc := range rejected {
+		err := ValidateOptions(tc.source, "png", Options{Frame: intPointer(tc.frame)})
+		if !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("expected frame %d for %s input to be rejected, got: %v", tc.frame, tc.source, err)
+		}
+	}
+}
+
+func TestPreservesAnimation(t *testing.T) {
+	cases := []struct {
+		source   string
+		target   string
+		expected bool
+	}{
+		{source: "gif", target: "webp", expected: true},
+		{source: "webp", target: "gif", expected: true},
+		{source: "avif", target: "webp", expected: true},
+		{source: "gif", target: "png", expected: false},
+		{source: "png", target: "gif", expected: false},
+	}
+
+	for _, tc := range cases {
+		if got := PreservesAnimation(tc.source, tc.target); got != tc.expected {
+			t.Fatalf("expected PreservesAnimation(%q, %q) to be %t, got %t", tc.source, tc.target, tc.expected, got)
+		}
+	}
+}
+
+func TestConvertWithOptionsKeepsGIFAnimationInWEBP(t *testing.T) {
+	requireFormatPairSupport(t, "gif", "webp")
+
+	result, err := newPairConverter("gif", "webp").ConvertWithOptions(context.Background(), mustEncodeAnimatedGIF(t, 3), Options{})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	assertOutputFormat(t, result.Output, "webp")
+	if result.Frames != 3 {
+		t.Fatalf("expected 3 frames to be reported, got %d", result.Frames)
+	}
+
+	image, err := vipsLoadBuffer(result.Output, animationLoadOptions)
+	if err != nil {
+		t.Fatalf("failed to load converted animation: %v", err)
+	}
+	defer image.close()
+	if frames := image.height() / image.frameHeight(); frames != 3 {
+		t.Fatalf("expected 3 frames in output, got %d", frames)
+	}
+}
+
+func TestConvertWithOptionsResizesEveryAnimationFrame(t *testing.T) {
+	requireFormatPairSupport(t, "gif", "webp")
+
+	options := Options{Transform: Transform{Width: 2, Height: 2}}
+	result, err := newPairConverter("gif", "webp").ConvertWithOptions(context.Background(), mustEncodeAnimatedGIF(t, 2), options)
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+
+	image, err := vipsLoadBuffer(result.Output, animationLoadOptions)
+	if err != nil {
+		t.Fatalf("failed to load converted animation: %v", err)
+	}
+	defer image.close()
+	if image.width() != 2 || image.frameHeight() != 2 || image.height() != 4 {
+		t.Fatalf("expected two 2x2 frames, got %dx%d with frame height %d", image.width(), image.height(), image.frameHeight())
+	}
+}
+
+func TestConvertWithOptionsExtractsFrame(t *testing.T) {
+	requireFormatPairSupport(t, "gif", "webp")
+
+	c := newPairConverter("gif", "webp")
+	input := mustEncodeAnimatedGIF(t, 3)
+
+	result, err := c.ConvertWithOptions(context.Background(), input, Options{Frame: intPointer(2)})
+	if err != nil {
+		t.Fatalf("expected frame extraction to succeed, got error: %v", err)
+	}
+	assertOutputFormat(t, result.Output, "webp")
+	if result.Frames != 0 {
+		t.Fatalf("expected a still image, got %d frames", result.Frames)
+	}
+
+	_, err = c.ConvertWithOptions(context.Background(), input, Options{Frame: intPointer(4)})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected out of range frame to be rejected, got: %v", err)
+	}
+}
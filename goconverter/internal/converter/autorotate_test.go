@@ -0,0 +1,40 @@
+package converter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConvertWithOptionsAutoRotatesByDefault(t *testing.T) {
+	requireFormatPairSupport(t, "jpeg", "webp")
+
+	input := mustReadBIMGTestdataFile(t, "exif/Landscape_6.jpg")
+	result, err := newPairConverter("jpeg", "webp").ConvertWithOptions(context.Background(), input, Options{})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+
+	if result.Geometry.Width <= result.Geometry.Height {
+		t.Fatalf("expected upright landscape output, got %dx%d", result.Geometry.Width, result.Geometry.Height)
+	}
+	if orientation := mustReadOrientation(t, result.Output); orientation != 1 {
+		t.Fatalf("expected orientation to be reset to 1, got %d", orientation)
+	}
+}
+
+func TestConvertWithOptionsCanDisableAutoRotate(t *testing.T) {
+	requireFormatPairSupport(t, "jpeg", "webp")
+
+	input := mustReadBIMGTestdataFile(t, "exif/Landscape_6.jpg")
+	result, err := newPairConverter("jpeg", "webp").ConvertWithOptions(context.Background(), input, Options{DisableAutoRotate: true})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+
+	if result.Geometry.Width >= result.Geometry.Height {
+		t.Fatalf("expected stored portrait pixels to be kept, got %dx%d", result.Geometry.Width, result.Geometry.Height)
+	}
+	if orientation := mustReadOrientation(t, result.Output); orientation != 6 {
+		t.Fatalf("expected orientation 6 to be kept, got %d", orientation)
+	}
+}
@@ -0,0 +1,192 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+const (
+	BackendVips = "libvips"
+	BackendGo   = "go"
+)
+
+const (
+	vipsBackendPriority = 100
+	goBackendPriority   = 10
+)
+
+// ErrRetryable marks failures another backend may not hit, such as an input
+// the engine has no codec for or a crashed external tool.
+var ErrRetryable = errors.New("retryable backend error")
+
+// Backend is a conversion engine. Several backends may register the same
+// pair; higher priorities are tried first.
+type Backend struct {
+	Name       string
+	Priority   int
+	Converters func() []Converter
+}
+
+var backends = []Backend{
+	{Name: BackendVips, Priority: vipsBackendPriority, Converters: vipsConverters},
+	{Name: BackendGo, Priority: goBackendPriority, Converters: goConverters},
+}
+
+// RegisterBackend adds a backend and rebuilds the registry. It is meant for
+// startup and must not run concurrently with conversions.
+func RegisterBackend(backend Backend) {
+	backends = append(backends, backend)
+	converters = buildConverters()
+}
+
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e retryableError) Unwrap() []error {
+	return []error{e.err, ErrRetryable}
+}
+
+// RetryableError marks err so the next backend registered for the pair runs.
+func RetryableError(err error) error {
+	return retryableError{err: err}
+}
+
+type backendCandidate struct {
+	backend   string
+	converter Converter
+}
+
+// optionsChecker is implemented by converters that accept only some options.
+// A chain skips them for requests they would reject.
+type optionsChecker interface {
+	checkOptions(options Options) error
+}
+
+// backendChain runs a pair on its backends in priority order, skipping those
+// that reject the options and moving on when a backend fails with a retryable
+// error. When every backend fails, the first error is returned; once the
+// context is done no further backend is tried.
+type backendChain struct {
+	source     string
+	target     string
+	candidates []backendCandidate
+}
+
+var _ streamConverter = (*backendChain)(nil)
+
+func (c *backendChain) SourceFormat() string {
+	return c.source
+}
+
+func (c *backendChain) TargetFormat() string {
+	return c.target
+}
+
+// Backend names the backend tried first.
+func (c *backendChain) Backend() string {
+	return c.candidates[0].backend
+}
+
+func (c *backendChain) backends() []string {
+	output := make([]string, 0, len(c.candidates))
+	for _, candidate := range c.candidates {
+		output = append(output, candidate.backend)
+	}
+
+	return output
+}
+
+func (c *backendChain) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := c.ConvertWithOptions(ctx, input, Options{})
+	return result.Output, err
+}
+
+func (c *backendChain) ConvertWithOptions(ctx context.Context, input []byte, options Options) (Result, error) {
+	return c.run(ctx, options, func(converter Converter) (Result, bool, error) {
+		result, err := converter.ConvertWithOptions(ctx, input, options)
+		return result, true, err
+	})
+}
+
+// convertTo streams the output of the backend that succeeds. A backend that
+// fails after writing part of its output is not retried, since the output
+// cannot be taken back.
+func (c *backendChain) convertTo(ctx context.Context, input []byte, options Options, w io.Writer) (Result, error) {
+	output := &countingWriter{w: w}
+	return c.run(ctx, options, func(converter Converter) (Result, bool, error) {
+		result, err := convertTo(ctx, converter, input, options, output)
+		return result, output.written == 0, err
+	})
+}
+
+// run calls convert with each candidate until one succeeds. convert also
+// reports whether a retryable failure may move on to the next candidate.
+func (c *backendChain) run(ctx context.Context, options Options, convert func(Converter) (Result, bool, error)) (Result, error) {
+	var firstErr error
+	for _, candidate := range c.candidates {
+		if err := ctx.Err(); err != nil {
+			return Result{}, fmt.Errorf("convert %s to %s: %w", c.source, c.target, err)
+		}
+		if checker, ok := candidate.converter.(optionsChecker); ok {
+			if err := checker.checkOptions(options); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("convert %s to %s: %w", c.source, c.target, err)
+				}
+				continue
+			}
+		}
+
+		result, canRetry, err := convert(candidate.converter)
+		if err == nil {
+			result.Backends = []string{candidate.backend}
+			for index := range result.Pages {
+				result.Pages[index].Backends = result.Backends
+			}
+			return result, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if !canRetry || !errors.Is(err, ErrRetryable) {
+			break
+		}
+	}
+
+	return Result{}, firstErr
+}
+
+type countingWriter struct {
+	w       io.Writer
+	written int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// converterBackend names the backend a converter runs on first, or "" for
+// converters outside the registry.
+func converterBackend(c Converter) string {
+	if chain, ok := c.(*backendChain); ok {
+		return chain.Backend()
+	}
+
+	return ""
+}
+
+// runsOnBackend reports whether a registered converter has the backend among
+// its candidates.
+func runsOnBackend(c Converter, backend string) bool {
+	chain, ok := c.(*backendChain)
+	return ok && slices.Contains(chain.backends(), backend)
+}
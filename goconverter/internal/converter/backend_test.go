@@ -0,0 +1,199 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type scriptedConverter struct {
+	source string
+	target string
+	output string
+	err    error
+	calls  *int
+}
+
+func (c scriptedConverter) SourceFormat() string {
+	return c.source
+}
+
+func (c scriptedConverter) TargetFormat() string {
+	return c.target
+}
+
+func (c scriptedConverter) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := c.ConvertWithOptions(ctx, input, Options{})
+	return result.Output, err
+}
+
+func (c scriptedConverter) ConvertWithOptions(_ context.Context, _ []byte, _ Options) (Result, error) {
+	if c.calls != nil {
+		*c.calls++
+	}
+	if c.err != nil {
+		return Result{}, c.err
+	}
+
+	return Result{Output: []byte(c.output)}, nil
+}
+
+// cancellingConverter cancels the request while it runs and fails as a
+// backend would when its work is cut short.
+type cancellingConverter struct {
+	scriptedConverter
+	cancel context.CancelFunc
+}
+
+func (c cancellingConverter) ConvertWithOptions(_ context.Context, _ []byte, _ Options) (Result, error) {
+	c.cancel()
+	return Result{}, RetryableError(errors.New("interrupted"))
+}
+
+// partialConverter streams the start of its output and then fails as if the
+// encoder broke down.
+type partialConverter struct {
+	scriptedConverter
+}
+
+func (c partialConverter) convertTo(_ context.Context, _ []byte, _ Options, w io.Writer) (Result, error) {
+	w.Write([]byte(c.output))
+	return Result{}, RetryableError(errors.New("encoder failed"))
+}
+
+func TestBackendChainFallsBackOnRetryableErrors(t *testing.T) {
+	chain := &backendChain{source: "png", target: "jpeg", candidates: []backendCandidate{
+		{backend: "primary", converter: scriptedConverter{err: RetryableError(errors.New("no loader"))}},
+		{backend: "secondary", converter: scriptedConverter{output: "secondary"}},
+	}}
+
+	result, err := chain.ConvertWithOptions(context.Background(), []byte("input"), Options{})
+	if err != nil {
+		t.Fatalf("expected fallback to succeed, got error: %v", err)
+	}
+	if string(result.Output) != "secondary" {
+		t.Fatalf("expected secondary output, got %q", result.Output)
+	}
+	if !reflect.DeepEqual(result.Backends, []string{"secondary"}) {
+		t.Fatalf("expected secondary backend to be reported, got %v", result.Backends)
+	}
+}
+
+func TestBackendChainStopsOnPermanentErrors(t *testing.T) {
+	calls := 0
+	permanent := invalidOptionsError("quality must be between 1 and 100")
+	chain := &backendChain{source: "png", target: "jpeg", candidates: []backendCandidate{
+		{backend: "primary", converter: scriptedConverter{err: permanent}},
+		{backend: "secondary", converter: scriptedConverter{output: "secondary", calls: &calls}},
+	}}
+
+	_, err := chain.ConvertWithOptions(context.Background(), []byte("input"), Options{})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected the primary error, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected the secondary backend not to run, ran %d time(s)", calls)
+	}
+}
+
+func TestBackendChainReturnsFirstErrorWhenAllFail(t *testing.T) {
+	first := RetryableError(errors.New("no loader"))
+	chain := &backendChain{source: "png", target: "jpeg", candidates: []backendCandidate{
+		{backend: "primary", converter: scriptedConverter{err: first}},
+		{backend: "secondary", converter: scriptedConverter{err: RetryableError(errors.New("decode failed"))}},
+	}}
+
+	_, err := chain.ConvertWithOptions(context.Background(), []byte("input"), Options{})
+	if !errors.Is(err, ErrRetryable) || err.Error() != "no loader" {
+		t.Fatalf("expected the primary error, got %v", err)
+	}
+}
+
+func TestBackendChainStopsWhenContextIsDone(t *testing.T) {
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	chain := &backendChain{source: "png", target: "jpeg", candidates: []backendCandidate{
+		{backend: "primary", converter: cancellingConverter{cancel: cancel}},
+		{backend: "secondary", converter: scriptedConverter{output: "secondary", calls: &calls}},
+	}}
+
+	_, err := chain.ConvertWithOptions(ctx, []byte("input"), Options{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected the secondary backend not to run, ran %d time(s)", calls)
+	}
+}
+
+func TestBackendChainWritesOutputToWriter(t *testing.T) {
+	chain := &backendChain{source: "png", target: "jpeg", candidates: []backendCandidate{
+		{backend: "primary", converter: scriptedConverter{err: RetryableError(errors.New("no loader"))}},
+		{backend: "secondary", converter: scriptedConverter{output: "secondary"}},
+	}}
+
+	var output bytes.Buffer
+	result, err := chain.convertTo(context.Background(), []byte("input"), Options{}, &output)
+	if err != nil {
+		t.Fatalf("expected fallback to succeed, got error: %v", err)
+	}
+	if output.String() != "secondary" || result.Output != nil {
+		t.Fatalf("expected secondary output to be written only, got %q and %q", output.String(), result.Output)
+	}
+	if !reflect.DeepEqual(result.Backends, []string{"secondary"}) {
+		t.Fatalf("expected secondary backend to be reported, got %v", result.Backends)
+	}
+}
+
+func TestBackendChainStopsAfterPartialOutput(t *testing.T) {
+	calls := 0
+	chain := &backendChain{source: "png", target: "jpeg", candidates: []backendCandidate{
+		{backend: "primary", converter: partialConverter{scriptedConverter{output: "part"}}},
+		{backend: "secondary", converter: scriptedConverter{output: "secondary", calls: &calls}},
+	}}
+
+	var output bytes.Buffer
+	_, err := chain.convertTo(context.Background(), []byte("input"), Options{}, &output)
+	if err == nil || err.Error() != "encoder failed" {
+		t.Fatalf("expected the primary error, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected the secondary backend not to run after output was written, ran %d time(s)", calls)
+	}
+}
+
+func TestRegisterBackendOrdersPairsByPriority(t *testing.T) {
+	savedBackends := backends
+	t.Cleanup(func() {
+		backends = savedBackends
+		converters = buildConverters()
+	})
+
+	RegisterBackend(Backend{
+		Name:     "preferred",
+		Priority: vipsBackendPriority + 1,
+		Converters: func() []Converter {
+			return []Converter{scriptedConverter{source: "png", target: "jpeg", output: "preferred"}}
+		},
+	})
+
+	order := ConversionBackends()["png"]["jpeg"]
+	if len(order) == 0 || order[0] != "preferred" {
+		t.Fatalf("expected preferred backend first, got %v", order)
+	}
+
+	c, ok := FindConverter("png", "jpeg")
+	if !ok {
+		t.Fatal("expected png to jpeg converter")
+	}
+	result, err := c.ConvertWithOptions(context.Background(), []byte("input"), Options{})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if string(result.Output) != "preferred" || !reflect.DeepEqual(result.Backends, []string{"preferred"}) {
+		t.Fatalf("expected preferred backend output, got %q from %v", result.Output, result.Backends)
+	}
+}
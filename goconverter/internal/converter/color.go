@@ -0,0 +1,68 @@
+package converter
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	ColorSpaceSRGB = "srgb"
+	ColorSpaceP3   = "p3"
+	ColorSpaceKeep = "keep"
+
+	ICCProfileEmbed = "embed"
+	ICCProfileOmit  = "omit"
+)
+
+var colorSpaces = []string{ColorSpaceSRGB, ColorSpaceP3, ColorSpaceKeep}
+
+var iccProfiles = []string{ICCProfileEmbed, ICCProfileOmit}
+
+// outputProfileByColorSpace maps color spaces to libvips built-in profiles.
+var outputProfileByColorSpace = map[string]string{
+	ColorSpaceSRGB: "srgb",
+	ColorSpaceP3:   "p3",
+}
+
+func validateColorOptions(colorSpace string, iccProfile string) error {
+	if colorSpace != "" && !slices.Contains(colorSpaces, colorSpace) {
+		return invalidOptionsError("colorSpace must be one of %s", strings.Join(colorSpaces, ", "))
+	}
+	if iccProfile != "" && !slices.Contains(iccProfiles, iccProfile) {
+		return invalidOptionsError("iccProfile must be one of %s", strings.Join(iccProfiles, ", "))
+	}
+
+	return nil
+}
+
+// applyColorManagement imports the embedded profile, or a default CMYK or
+// sRGB one, and converts to the requested space. Untagged RGB going to sRGB
+// is already in the output space and is left alone. The output profile
+// attached by the transform is removed when the caller asks to omit it.
+func applyColorManagement(image *vipsImage, colorSpace string, iccProfile string) ([]string, error) {
+	if colorSpace == "" {
+		colorSpace = ColorSpaceSRGB
+	}
+
+	if colorSpace != ColorSpaceKeep && !image.isGrey() {
+		hasProfile := image.hasField(iccProfileField)
+		if image.isCMYK() || hasProfile || colorSpace != ColorSpaceSRGB {
+			inputProfile := "srgb"
+			if image.isCMYK() {
+				inputProfile = "cmyk"
+			}
+			if err := image.iccTransform(outputProfileByColorSpace[colorSpace], inputProfile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if iccProfile == ICCProfileOmit && image.hasField(iccProfileField) {
+		if err := image.removeFields([]string{iccProfileField}); err != nil {
+			return nil, err
+		}
+		return []string{MetadataBlockICC}, nil
+	}
+
+	return nil, nil
+}
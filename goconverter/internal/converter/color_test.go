@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/color"
+	"image/png"
+	"slices"
+	"testing"
+)
+
+func TestValidateOptionsRejectsUnknownColorOptions(t *testing.T) {
+	rejected := []Options{
+		{ColorSpace: "adobe-rgb"},
+		{ICCProfile: "keep"},
+	}
+
+	for _, options := range rejected {
+		err := ValidateOptions("jpeg", "png", options)
+		if !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("expected %+v to be rejected, got: %v", options, err)
+		}
+	}
+}
+
+func TestConvertWithOptionsConvertsCMYKToSRGB(t *testing.T) {
+	requireFormatPairSupport(t, "tiff", "png")
+
+	input := mustEncodeCMYKTIFF(t, 0, 255, 255, 0)
+	result, err := newPairConverter("tiff", "png").ConvertWithOptions(context.Background(), input, Options{})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+
+	decoded, err := png.Decode(bytes.NewReader(result.Output))
+	if err != nil {
+		t.Fatalf("failed to decode output: %v", err)
+	}
+	r, g, b, _ := decoded.At(0, 0).RGBA()
+	if r>>8 < 200 || g>>8 > 80 || b>>8 > 80 {
+		t.Fatalf("expected full magenta and yellow ink to render red, got rgb(%d, %d, %d)", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestConvertWithOptionsConvertsP3ToSRGB(t *testing.T) {
+	requireFormatPairSupport(t, "png", "jpeg")
+
+	source := color.RGBA{R: 200, G: 100, B: 50, A: 255}
+	input := mustEncodeP3PNG(t, source)
+
+	result, err := newPairConverter("png", "jpeg").ConvertWithOptions(context.Background(), input, Options{Quality: intPointer(100)})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	assertColorNear(t, mustDecodeJPEGPixel(t, result.Output), source, 6)
+
+	kept, err := newPairConverter("png", "jpeg").ConvertWithOptions(context.Background(), input, Options{ColorSpace: ColorSpaceKeep, Quality: intPointer(100)})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if pixel := mustDecodeJPEGPixel(t, kept.Output); colorDistance(pixel, source) <= 6 {
+		t.Fatalf("expected keep to leave P3 values untouched, got %+v", pixel)
+	}
+}
+
+func TestConvertWithOptionsEmbedsOrOmitsProfile(t *testing.T) {
+	requireFormatPairSupport(t, "png", "jpeg")
+
+	input := mustEncodeP3PNG(t, color.RGBA{R: 20, G: 180, B: 90, A: 255})
+	c := newPairConverter("png", "jpeg")
+
+	embedded, err := c.ConvertWithOptions(context.Background(), input, Options{ColorSpace: ColorSpaceP3, ICCProfile: ICCProfileEmbed})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if !slices.Contains(mustReadFields(t, embedded.Output), iccProfileField) {
+		t.Fatal("expected the output profile to be embedded")
+	}
+
+	omitted, err := c.ConvertWithOptions(context.Background(), input, Options{ICCProfile: ICCProfileOmit})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if slices.Contains(mustReadFields(t, omitted.Output), iccProfileField) {
+		t.Fatal("expected the profile to be omitted")
+	}
+	if !slices.Contains(omitted.MetadataRemoved, MetadataBlockICC) {
+		t.Fatalf("expected icc to be reported as removed, got %v", omitted.MetadataRemoved)
+	}
+}
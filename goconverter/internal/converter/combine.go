@@ -0,0 +1,318 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"slices"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// Orientations turn named page sizes; OrientationAuto matches each page to
+// its image.
+const (
+	OrientationAuto      = "auto"
+	OrientationPortrait  = "portrait"
+	OrientationLandscape = "landscape"
+)
+
+// PageFitShrink keeps images at their size on the page and only scales
+// down those that do not fit. The other page fits reuse the transform names.
+const PageFitShrink = "shrink"
+
+var orientations = []string{OrientationAuto, OrientationPortrait, OrientationLandscape}
+var pageFitModes = []string{PageFitShrink, FitContain, FitCover, FitFill}
+var combineTargets = []string{"pdf", "tiff"}
+var marginRange = intRange{min: 0, max: 50}
+
+const pointsPerMillimetre = pointsPerInch / 25.4
+
+// ErrUnsupportedInput is returned by Combine for inputs whose format is not
+// detected or cannot be decoded to pixels.
+var ErrUnsupportedInput = errors.New("unsupported input format")
+
+// CombineOptions lay out the pages of a combined document. Page size,
+// orientation, margins and fit place every page on a fixed size sheet;
+// JPEGQuality recompresses PDF images instead of storing them losslessly,
+// and JPEG inputs unchanged.
+type CombineOptions struct {
+	PageSize    string
+	Orientation string
+	MarginMm    int
+	Fit         string
+	DPI         *int
+	JPEGQuality *int
+}
+
+// CombineResult is the combined document and what each input contributed.
+type CombineResult struct {
+	Output  []byte
+	Pages   int
+	Sources []CombinedSource
+}
+
+// CombinedSource is the detected format of one input and the number of
+// pages it added.
+type CombinedSource struct {
+	Format string
+	Pages  int
+}
+
+// CanCombineInto reports whether Combine writes the target format.
+func CanCombineInto(target string) bool {
+	return slices.Contains(combineTargets, target)
+}
+
+func ValidateCombineOptions(target string, options CombineOptions) error {
+	if !CanCombineInto(target) {
+		return invalidOptionsError("combining into %s is not supported", target)
+	}
+	if options.PageSize != "" && !slices.Contains(pageSizes, options.PageSize) {
+		return invalidOptionsError("pageSize must be one of %s", strings.Join(pageSizes, ", "))
+	}
+	if options.Orientation != "" && !slices.Contains(orientations, options.Orientation) {
+		return invalidOptionsError("orientation must be one of %s", strings.Join(orientations, ", "))
+	}
+	if options.Fit != "" && !slices.Contains(pageFitModes, options.Fit) {
+		return invalidOptionsError("fit must be one of %s", strings.Join(pageFitModes, ", "))
+	}
+	if err := validateRange("marginMm", options.MarginMm, marginRange); err != nil {
+		return err
+	}
+	if options.DPI != nil {
+		if err := validateRange("dpi", *options.DPI, dpiRange); err != nil {
+			return err
+		}
+	}
+	if options.JPEGQuality != nil {
+		if target != "pdf" {
+			return unsupportedOptionError("jpegQuality", target)
+		}
+		if err := validateRange("jpegQuality", *options.JPEGQuality, qualityRange); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Combine decodes every input, expanding multi-page ones, and writes the
+// pages in order to one PDF or multi-page TIFF. The decoded pages are held
+// to the total pixel limit. The context is checked between inputs and
+// before the document is written.
+func Combine(ctx context.Context, inputs [][]byte, target string, options CombineOptions) (CombineResult, error) {
+	if err := ValidateCombineOptions(target, options); err != nil {
+		return CombineResult{}, err
+	}
+
+	decoder := pageDecoder{keepJPEG: target == "pdf" && options.JPEGQuality == nil}
+	var pages []pdfImage
+	sources := make([]CombinedSource, 0, len(inputs))
+	for index, input := range inputs {
+		format, decoded, err := decoder.decode(ctx, input)
+		if err != nil {
+			return CombineResult{}, fmt.Errorf("input %d: %w", index+1, err)
+		}
+		pages = append(pages, decoded...)
+		if err := checkPageCount(len(pages)); err != nil {
+			return CombineResult{}, err
+		}
+		sources = append(sources, CombinedSource{Format: format, Pages: len(decoded)})
+	}
+
+	if err := ctx.Err(); err != nil {
+		return CombineResult{}, err
+	}
+
+	layout := options.layout()
+	var output bytes.Buffer
+	var err error
+	if target == "pdf" {
+		err = writePDF(&output, pages, layout, options.JPEGQuality)
+	} else {
+		images := make([]image.Image, 0, len(pages))
+		for _, page := range pages {
+			images = append(images, page.pixels)
+		}
+		var rendered []image.Image
+		if rendered, err = layout.render(images); err == nil {
+			err = writeTIFF(&output, rendered, layout.dpi)
+		}
+	}
+	if errors.Is(err, ErrInputTooComplex) {
+		return CombineResult{}, err
+	}
+	if err != nil {
+		return CombineResult{}, fmt.Errorf("combine into %s: %w", target, err)
+	}
+
+	return CombineResult{Output: output.Bytes(), Pages: len(pages), Sources: sources}, nil
+}
+
+func (o CombineOptions) layout() pageLayout {
+	layout := newPageLayout(o.PageSize, o.DPI)
+	layout.margin = float64(o.MarginMm) * pointsPerMillimetre
+	if o.Orientation != "" {
+		layout.orientation = o.Orientation
+	}
+	if o.Fit != "" {
+		layout.fit = o.Fit
+	}
+
+	return layout
+}
+
+// pageDecoder decodes the inputs of one Combine call, counting the pixels
+// of the pages decoded so far.
+type pageDecoder struct {
+	keepJPEG bool
+	pixels   int64
+}
+
+// decode runs the input through the registered PNG route, selecting all
+// pages of multi-page sources the first hop can split. JPEG inputs PDF can
+// show as they are skip decoding when keepJPEG is set.
+func (d *pageDecoder) decode(ctx context.Context, input []byte) (string, []pdfImage, error) {
+	detection, ok := DetectFormat(input)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: could not detect the format", ErrUnsupportedInput)
+	}
+	format := detection.Format
+	if format == "jpeg" && d.keepJPEG {
+		if page, ok := embeddableJPEG(input); ok {
+			if err := checkImageSize(page.size.X, page.size.Y); err != nil {
+				return "", nil, err
+			}
+			return format, []pdfImage{page}, nil
+		}
+	}
+	if format == "png" {
+		page, err := d.decodePNG(input)
+		if err != nil {
+			return "", nil, fmt.Errorf("decode png: %w", err)
+		}
+		return format, []pdfImage{page}, nil
+	}
+
+	plan, ok := PlanConversion(format, "png")
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s cannot be decoded", ErrUnsupportedInput, format)
+	}
+	var options Options
+	if SupportsPageSelection(format) && runsOnBackend(plan.steps[0], BackendVips) {
+		options.Pages = PagesAll
+	}
+
+	result, err := plan.ConvertWithOptions(ctx, input, options)
+	if err != nil {
+		return "", nil, err
+	}
+	outputs := [][]byte{result.Output}
+	if len(result.Pages) > 0 {
+		outputs = outputs[:0]
+		for _, page := range result.Pages {
+			outputs = append(outputs, page.Output)
+		}
+	}
+
+	pages := make([]pdfImage, 0, len(outputs))
+	for _, output := range outputs {
+		page, err := d.decodePNG(output)
+		if err != nil {
+			return "", nil, fmt.Errorf("decode %s page: %w", format, err)
+		}
+		pages = append(pages, page)
+	}
+
+	return format, pages, nil
+}
+
+// decodePNG checks the size in the header against the page and total pixel
+// limits before decoding.
+func (d *pageDecoder) decodePNG(input []byte) (pdfImage, error) {
+	config, err := png.DecodeConfig(bytes.NewReader(input))
+	if err != nil {
+		return pdfImage{}, err
+	}
+	if err := checkImageSize(config.Width, config.Height); err != nil {
+		return pdfImage{}, err
+	}
+	d.pixels += int64(config.Width) * int64(config.Height)
+	if err := checkTotalPixels(d.pixels); err != nil {
+		return pdfImage{}, err
+	}
+
+	img, err := png.Decode(bytes.NewReader(input))
+	if err != nil {
+		return pdfImage{}, err
+	}
+
+	return pdfImage{pixels: img}, nil
+}
+
+// embeddableJPEG reports whether PDF viewers show the JPEG file as decoding
+// it would: gray or YCbCr samples of 8 bits, stored the right way up. CMYK
+// files and files with an EXIF orientation are decoded instead.
+func embeddableJPEG(input []byte) (pdfImage, bool) {
+	config, err := jpeg.DecodeConfig(bytes.NewReader(input))
+	if err != nil || goOrientation("jpeg", input) != 1 {
+		return pdfImage{}, false
+	}
+
+	page := pdfImage{jpeg: input, size: image.Pt(config.Width, config.Height)}
+	switch config.ColorModel {
+	case color.GrayModel:
+		page.colorSpace = "/DeviceGray"
+	case color.YCbCrModel:
+		page.colorSpace = "/DeviceRGB"
+	default:
+		return pdfImage{}, false
+	}
+
+	return page, true
+}
+
+// render rasterizes images onto white pages at the layout DPI for formats
+// without a page model. Images on auto pages without margins pass through;
+// pages are held to the same pixel limit as decoded inputs.
+func (l pageLayout) render(images []image.Image) ([]image.Image, error) {
+	if _, ok := pageDimensions[l.pageSize]; !ok && l.margin == 0 {
+		return images, nil
+	}
+
+	scale := float64(l.dpi) / pointsPerInch
+	pixels := func(points float64) int {
+		return int(math.Round(points * scale))
+	}
+
+	output := make([]image.Image, 0, len(images))
+	for _, img := range images {
+		placement := l.place(img.Bounds().Size())
+		if err := checkImageSize(pixels(placement.pageWidth), pixels(placement.pageHeight)); err != nil {
+			return nil, err
+		}
+		canvas := image.NewRGBA(image.Rect(0, 0, pixels(placement.pageWidth), pixels(placement.pageHeight)))
+		draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+
+		// Placements count from the bottom of the page; pixels from the top.
+		x, y, width, height := placement.image[0], placement.image[1], placement.image[2], placement.image[3]
+		target := image.Rect(pixels(x), pixels(placement.pageHeight-y-height), pixels(x+width), pixels(placement.pageHeight-y))
+		destination := draw.Image(canvas)
+		if placement.clip {
+			area := placement.area
+			destination = canvas.SubImage(image.Rect(pixels(area[0]), pixels(placement.pageHeight-area[1]-area[3]), pixels(area[0]+area[2]), pixels(placement.pageHeight-area[1]))).(*image.RGBA)
+		}
+		draw.CatmullRom.Scale(destination, target, img, img.Bounds(), draw.Over, nil)
+		output = append(output, canvas)
+	}
+
+	return output, nil
+}
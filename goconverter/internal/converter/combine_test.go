@@ -0,0 +1,187 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/tiff"
+)
+
+func TestCombineWritesOnePDFPagePerInput(t *testing.T) {
+	result, err := Combine(context.Background(), [][]byte{mustEncodePNG(t), mustEncodeJPEG(t)}, "pdf", CombineOptions{})
+	if err != nil {
+		t.Fatalf("expected inputs to combine, got error: %v", err)
+	}
+
+	assertPDFCrossReferences(t, result.Output)
+	if result.Pages != 2 || !bytes.Contains(result.Output, []byte("/Count 2")) {
+		t.Fatalf("expected two pages, got %d in %q", result.Pages, result.Output)
+	}
+	expected := []CombinedSource{{Format: "png", Pages: 1}, {Format: "jpeg", Pages: 1}}
+	if len(result.Sources) != len(expected) || result.Sources[0] != expected[0] || result.Sources[1] != expected[1] {
+		t.Fatalf("expected sources %v, got %v", expected, result.Sources)
+	}
+}
+
+func TestCombineExpandsMultiPageInputs(t *testing.T) {
+	requireFormatPairSupport(t, "tiff", "png")
+
+	result, err := Combine(context.Background(), [][]byte{mustEncodeMultiPageTIFF(t, 3), mustEncodePNG(t)}, "pdf", CombineOptions{})
+	if err != nil {
+		t.Fatalf("expected inputs to combine, got error: %v", err)
+	}
+	if result.Pages != 4 || result.Sources[0].Pages != 3 {
+		t.Fatalf("expected three tiff pages and one png page, got %d pages from %v", result.Pages, result.Sources)
+	}
+}
+
+func TestCombineRecompressesPDFImagesAsJPEG(t *testing.T) {
+	result, err := Combine(context.Background(), [][]byte{mustEncodePNG(t)}, "pdf", CombineOptions{JPEGQuality: intPointer(60)})
+	if err != nil {
+		t.Fatalf("expected input to combine, got error: %v", err)
+	}
+
+	assertPDFCrossReferences(t, result.Output)
+	if !bytes.Contains(result.Output, []byte("/Filter /DCTDecode")) {
+		t.Fatalf("expected a JPEG image stream, got %q", result.Output)
+	}
+}
+
+func TestCombineEmbedsJPEGInputsUnchanged(t *testing.T) {
+	input := mustEncodeJPEG(t)
+	result, err := Combine(context.Background(), [][]byte{input}, "pdf", CombineOptions{})
+	if err != nil {
+		t.Fatalf("expected input to combine, got error: %v", err)
+	}
+
+	assertPDFCrossReferences(t, result.Output)
+	if !bytes.Contains(result.Output, []byte("/ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode")) || !bytes.Contains(result.Output, input) {
+		t.Fatalf("expected the JPEG input as the image stream, got %q", result.Output)
+	}
+}
+
+func TestCombineLimitsTheTotalPixelsOfDecodedPages(t *testing.T) {
+	withInputLimits(t, InputLimits{MaxTotalPixels: 6})
+
+	if _, err := Combine(context.Background(), [][]byte{mustEncodePNG(t)}, "pdf", CombineOptions{}); err != nil {
+		t.Fatalf("expected one page to fit the limit, got error: %v", err)
+	}
+	_, err := Combine(context.Background(), [][]byte{mustEncodePNG(t), mustEncodePNG(t)}, "pdf", CombineOptions{})
+	if !errors.Is(err, ErrInputTooComplex) {
+		t.Fatalf("expected input too complex error, got %v", err)
+	}
+}
+
+func TestCombineWritesMultiPageTIFF(t *testing.T) {
+	result, err := Combine(context.Background(), [][]byte{mustEncodePNG(t), mustEncodeJPEG(t), mustEncodePNG(t)}, "tiff", CombineOptions{})
+	if err != nil {
+		t.Fatalf("expected inputs to combine, got error: %v", err)
+	}
+
+	if pages := countTIFFPages(t, result.Output); pages != 3 {
+		t.Fatalf("expected three tiff pages, got %d", pages)
+	}
+	img, err := tiff.Decode(bytes.NewReader(result.Output))
+	if err != nil {
+		t.Fatalf("expected first page to decode, got error: %v", err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(2, 2) {
+		t.Fatalf("expected the first page to keep its size, got %v", size)
+	}
+}
+
+func TestCombineRendersTIFFPagesAtDPI(t *testing.T) {
+	result, err := Combine(context.Background(), [][]byte{mustEncodePNG(t)}, "tiff", CombineOptions{PageSize: PageSizeA4, MarginMm: 10, Fit: FitContain, DPI: intPointer(36)})
+	if err != nil {
+		t.Fatalf("expected input to combine, got error: %v", err)
+	}
+
+	img, err := tiff.Decode(bytes.NewReader(result.Output))
+	if err != nil {
+		t.Fatalf("expected page to decode, got error: %v", err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(298, 421) {
+		t.Fatalf("expected an a4 page at 36 dpi, got %v", size)
+	}
+	if r, g, b, _ := img.At(0, 0).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+		t.Fatalf("expected a white margin, got %v", img.At(0, 0))
+	}
+}
+
+func TestPageLayoutPlacesImages(t *testing.T) {
+	tests := []struct {
+		name    string
+		options CombineOptions
+		size    image.Point
+		placed  string
+	}{
+		{name: "auto margin", options: CombineOptions{MarginMm: 10}, size: image.Pt(100, 50), placed: "q 100 0 0 50 28.346457 28.346457 cm"},
+		{name: "portrait", options: CombineOptions{PageSize: PageSizeLetter, Orientation: OrientationPortrait}, size: image.Pt(200, 100), placed: "q 200 0 0 100 206 346 cm"},
+		{name: "landscape", options: CombineOptions{PageSize: PageSizeLetter, Orientation: OrientationLandscape}, size: image.Pt(100, 200), placed: "q 100 0 0 200 346 206 cm"},
+		{name: "contain", options: CombineOptions{PageSize: PageSizeLetter, Fit: FitContain}, size: image.Pt(100, 200), placed: "q 396 0 0 792 108 0 cm"},
+		{name: "cover", options: CombineOptions{PageSize: PageSizeLetter, Fit: FitCover}, size: image.Pt(100, 200), placed: "q 0 0 612 792 re W n 612 0 0 1224 0 -216 cm"},
+		{name: "fill", options: CombineOptions{PageSize: PageSizeLetter, Fit: FitFill}, size: image.Pt(100, 200), placed: "q 612 0 0 792 0 0 cm"},
+	}
+
+	for _, tt := range tests {
+		contents := tt.options.layout().place(tt.size).contents()
+		if !strings.HasPrefix(contents, tt.placed) {
+			t.Fatalf("%s: expected placement %q, got %q", tt.name, tt.placed, contents)
+		}
+	}
+}
+
+func TestValidateCombineOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		options CombineOptions
+	}{
+		{name: "target", target: "png", options: CombineOptions{}},
+		{name: "page size", target: "pdf", options: CombineOptions{PageSize: "b5"}},
+		{name: "orientation", target: "pdf", options: CombineOptions{Orientation: "sideways"}},
+		{name: "fit", target: "pdf", options: CombineOptions{Fit: FitInside}},
+		{name: "margin", target: "pdf", options: CombineOptions{MarginMm: 51}},
+		{name: "dpi", target: "tiff", options: CombineOptions{DPI: intPointer(0)}},
+		{name: "jpeg quality for tiff", target: "tiff", options: CombineOptions{JPEGQuality: intPointer(80)}},
+		{name: "jpeg quality range", target: "pdf", options: CombineOptions{JPEGQuality: intPointer(101)}},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateCombineOptions(tt.target, tt.options); !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("%s: expected invalid options error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestCombineRejectsUndetectedInput(t *testing.T) {
+	_, err := Combine(context.Background(), [][]byte{mustEncodePNG(t), []byte("not an image")}, "pdf", CombineOptions{})
+	if !errors.Is(err, ErrUnsupportedInput) {
+		t.Fatalf("expected unsupported input error, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "input 2: ") {
+		t.Fatalf("expected the error to name the input, got %q", err)
+	}
+}
+
+// countTIFFPages follows the chain of image file directories in a
+// little-endian TIFF.
+func countTIFFPages(t *testing.T, input []byte) int {
+	t.Helper()
+
+	pages := 0
+	for offset := binary.LittleEndian.Uint32(input[4:]); offset != 0; pages++ {
+		if int(offset)+2 > len(input) {
+			t.Fatalf("image file directory offset %d is out of range", offset)
+		}
+		entries := int(binary.LittleEndian.Uint16(input[offset:]))
+		offset = binary.LittleEndian.Uint32(input[int(offset)+2+entries*12:])
+	}
+
+	return pages
+}
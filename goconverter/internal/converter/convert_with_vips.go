@@ -0,0 +1,163 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// convertWithVips writes the output to w as it is encoded, or returns it in
+// Result.Output when w is nil. Several selected pages are always returned in
+// Result.Pages.
+func convertWithVips(ctx context.Context, input []byte, sourceFormat string, targetFormat string, options Options, w io.Writer) (Result, error) {
+	result, err := runVipsConversion(ctx, input, sourceFormat, targetFormat, options, w)
+	if err != nil {
+		return Result{}, fmt.Errorf("convert %s to %s: %w", sourceFormat, targetFormat, err)
+	}
+
+	return result, nil
+}
+
+func runVipsConversion(ctx context.Context, input []byte, sourceFormat string, targetFormat string, options Options, w io.Writer) (Result, error) {
+	if err := ValidateOptions(sourceFormat, targetFormat, options); err != nil {
+		return Result{}, err
+	}
+
+	saveOptions, err := saveOptionString(targetFormat, options)
+	if err != nil {
+		return Result{}, err
+	}
+
+	defer vipsThreadShutdown()
+
+	var regions []pdfRegion
+	if options.Rasterize.PDFBox != "" {
+		if input, regions, err = selectPDFBox(input, options.Rasterize.PDFBox); err != nil {
+			return Result{}, err
+		}
+	}
+
+	switch {
+	case options.Pages != "":
+		return convertPages(ctx, input, regions, options, saveOptions, w)
+	case options.Frame != nil:
+		return convertFrame(ctx, input, options, saveOptions, w)
+	case PreservesAnimation(sourceFormat, targetFormat):
+		return convertAnimation(ctx, input, options, saveOptions, w)
+	}
+
+	output, report, err := convertVipsImage(ctx, input, nil, pageRegion(regions, 1), options, saveOptions, w)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return report.result(output), nil
+}
+
+func convertPages(ctx context.Context, input []byte, regions []pdfRegion, options Options, saveOptions string, w io.Writer) (Result, error) {
+	pageCount, err := vipsPageCount(input)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := checkPageCount(pageCount); err != nil {
+		return Result{}, err
+	}
+	pages, err := resolvePages(options.Pages, pageCount)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Only a single page is written to w; several are kept apart in Pages.
+	pageWriter := w
+	if len(pages) > 1 {
+		pageWriter = nil
+	}
+	results := make([]PageResult, 0, len(pages))
+	var metadataRemoved []string
+	for index, page := range pages {
+		pageCtx := progressPart(ctx, index, len(pages))
+		output, report, err := convertVipsImage(pageCtx, input, []string{pageLoadParameter(page)}, pageRegion(regions, page), options, saveOptions, pageWriter)
+		if err != nil {
+			return Result{}, fmt.Errorf("page %d: %w", page, err)
+		}
+		results = append(results, PageResult{Page: page, Output: output, Geometry: report.geometry})
+		metadataRemoved = mergeMetadataBlocks(metadataRemoved, report.metadataRemoved)
+	}
+
+	if len(results) == 1 {
+		return Result{Output: results[0].Output, Geometry: results[0].Geometry, MetadataRemoved: metadataRemoved}, nil
+	}
+
+	return Result{Pages: results, MetadataRemoved: metadataRemoved}, nil
+}
+
+// imageReport describes what processing did to one decoded image.
+type imageReport struct {
+	geometry        Geometry
+	metadataRemoved []string
+}
+
+func (r imageReport) result(output []byte) Result {
+	return Result{Output: output, Geometry: r.geometry, MetadataRemoved: r.metadataRemoved}
+}
+
+func convertVipsImage(ctx context.Context, input []byte, loadParameters []string, region pdfRegion, options Options, saveOptions string, w io.Writer) ([]byte, imageReport, error) {
+	image, err := loadVipsImage(input, loadParameters, options.Rasterize, region)
+	if err != nil {
+		return nil, imageReport{}, err
+	}
+	defer image.close()
+
+	report, err := processVipsImage(image, options)
+	if err != nil {
+		return nil, imageReport{}, err
+	}
+
+	output, err := image.save(ctx, saveOptions, w)
+	if err != nil {
+		return nil, imageReport{}, err
+	}
+
+	return output, report, nil
+}
+
+func processVipsImage(image *vipsImage, options Options) (imageReport, error) {
+	if !options.DisableAutoRotate {
+		if err := image.autoRotate(); err != nil {
+			return imageReport{}, fmt.Errorf("auto-rotate: %w", err)
+		}
+	}
+
+	profileRemoved, err := applyColorManagement(image, options.ColorSpace, options.ICCProfile)
+	if err != nil {
+		return imageReport{}, fmt.Errorf("color management: %w", err)
+	}
+
+	geometry, err := applyTransform(image, options.Transform)
+	if err != nil {
+		return imageReport{}, err
+	}
+
+	metadataRemoved, err := applyMetadataPolicy(image, options.Metadata)
+	if err != nil {
+		return imageReport{}, fmt.Errorf("metadata: %w", err)
+	}
+
+	return imageReport{geometry: geometry, metadataRemoved: mergeMetadataBlocks(profileRemoved, metadataRemoved)}, nil
+}
+
+func vipsPageCount(input []byte) (int, error) {
+	image, err := vipsLoadBuffer(input, "")
+	if err != nil {
+		return 0, err
+	}
+	defer image.close()
+
+	return image.pageCount(), nil
+}
+
+// pageLoadParameter selects a 1-based page; libvips loaders count from 0.
+func pageLoadParameter(page int) string {
+	return "page=" + strconv.Itoa(page-1)
+}
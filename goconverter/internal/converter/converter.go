@@ -1,7 +1,64 @@
 package converter
 
+import (
+	"context"
+	"io"
+)
+
+// Converter turns input of its source format into its target format. Work
+// stops when ctx is done; the returned error then wraps ctx.Err().
 type Converter interface {
 	SourceFormat() string
 	TargetFormat() string
-	Convert(input []byte) ([]byte, error)
+	Convert(ctx context.Context, input []byte) ([]byte, error)
+	ConvertWithOptions(ctx context.Context, input []byte, options Options) (Result, error)
+}
+
+// streamConverter is implemented by converters that can write their output
+// to w while it is encoded instead of holding all of it in Result.Output.
+// Input stays a byte slice: detection reads the end of TGA files, the
+// backend chain retries failed loads on the same bytes, multi-page sources
+// are loaded once for the page count and once per page, and workers receive
+// the input in one message.
+type streamConverter interface {
+	Converter
+	convertTo(ctx context.Context, input []byte, options Options, w io.Writer) (Result, error)
+}
+
+// convertTo writes the output of c to w and leaves Result.Output empty.
+// Several selected pages are returned in Result.Pages and nothing is
+// written.
+func convertTo(ctx context.Context, c Converter, input []byte, options Options, w io.Writer) (Result, error) {
+	if streaming, ok := c.(streamConverter); ok {
+		return streaming.convertTo(ctx, input, options, w)
+	}
+
+	result, err := c.ConvertWithOptions(ctx, input, options)
+	if err != nil || len(result.Pages) > 0 {
+		return result, err
+	}
+	if _, err := w.Write(result.Output); err != nil {
+		return Result{}, err
+	}
+	result.Output = nil
+
+	return result, nil
+}
+
+type Result struct {
+	Output   []byte
+	Geometry Geometry
+	// Pages is set instead of Output and Geometry when a page selector
+	// picked more than one page.
+	Pages []PageResult
+	// Frames is the number of animation frames kept in Output, or 0 for
+	// still images.
+	Frames int
+	// MetadataRemoved lists the metadata blocks dropped by the metadata
+	// policy, e.g. "exif" or "exif-gps".
+	MetadataRemoved []string
+	// Backends names the backend that produced the output of each hop, in
+	// route order. A hop whose pages went through different backends, after
+	// falling back for some of them, names them all joined by "+".
+	Backends []string
 }
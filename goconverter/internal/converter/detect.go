@@ -0,0 +1,183 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Detection is the result of sniffing an input. Format is the registry source
+// format that decodes it.
+type Detection struct {
+	Format string
+}
+
+const sniffLimit = 4096
+
+var heifBrands = map[string]string{
+	"avif": "avif",
+	"avis": "avif",
+	"heic": "heif",
+	"heix": "heif",
+	"heim": "heif",
+	"heis": "heif",
+	"hevc": "heif",
+	"hevx": "heif",
+}
+
+// DetectFormat identifies the input from its leading bytes. Formats without a
+// signature, such as headerless TGA files, are not detected.
+func DetectFormat(input []byte) (Detection, bool) {
+	switch {
+	case bytes.HasPrefix(input, []byte("\x89PNG\r\n\x1a\n")):
+		return detected("png"), true
+	case bytes.HasPrefix(input, []byte{0xff, 0xd8, 0xff}):
+		return detected("jpeg"), true
+	case bytes.HasPrefix(input, []byte("GIF87a")), bytes.HasPrefix(input, []byte("GIF89a")):
+		return detected("gif"), true
+	case len(input) >= 12 && bytes.Equal(input[:4], []byte("RIFF")) && bytes.Equal(input[8:12], []byte("WEBP")):
+		return detected("webp"), true
+	case bytes.HasPrefix(input, []byte{0xff, 0x0a}), bytes.HasPrefix(input, []byte("\x00\x00\x00\x0cJXL \r\n\x87\n")):
+		return detected("jxl"), true
+	case bytes.HasPrefix(input, []byte("\x00\x00\x00\x0cjP  \r\n\x87\n")), bytes.HasPrefix(input, []byte{0xff, 0x4f, 0xff, 0x51}):
+		return detected("jp2k"), true
+	case len(input) >= 12 && bytes.Equal(input[4:8], []byte("ftyp")):
+		return detectISOBMFF(input)
+	case isCameraRaw(input):
+		return detected("raw"), true
+	case bytes.HasPrefix(input, []byte("II*\x00")), bytes.HasPrefix(input, []byte("MM\x00*")),
+		bytes.HasPrefix(input, []byte("II+\x00")), bytes.HasPrefix(input, []byte("MM\x00+")):
+		return detected("tiff"), true
+	case bytes.Contains(sniffWindow(input), []byte("%PDF-")):
+		return detected("pdf"), true
+	case bytes.HasPrefix(input, []byte("8BPS")):
+		return detected("psd"), true
+	case bytes.HasPrefix(input, []byte("gimp xcf")):
+		return detected("xcf"), true
+	case isBMP(input):
+		return detected("bmp"), true
+	case isICO(input):
+		return detected("ico"), true
+	case isPCX(input):
+		return detected("pcx"), true
+	case bytes.HasSuffix(input, []byte("TRUEVISION-XFILE.\x00")):
+		return detected("tga"), true
+	case isSVG(input):
+		return detected("svg"), true
+	}
+
+	return Detection{}, false
+}
+
+// Matches reports whether a declared source format can decode the input.
+// Camera raw formats such as NEF and ARW are TIFF containers, so TIFF input
+// declared as raw is accepted. magick accepts everything ImageMagick loads.
+func (d Detection) Matches(declaredFormat string) bool {
+	if d.Format == declaredFormat {
+		return true
+	}
+	switch declaredFormat {
+	case "raw":
+		return d.Format == "tiff"
+	case magickSourceFormat:
+		format, ok := LookupFormat(d.Format)
+		return d.Format == "tiff" || ok && format.vipsType == magickVipsType
+	default:
+		return false
+	}
+}
+
+func detected(format string) Detection {
+	return Detection{Format: format}
+}
+
+// detectISOBMFF tells AVIF from other HEIF files by the ftyp major brand,
+// falling back to the compatible brands for generic mif1/msf1 files.
+func detectISOBMFF(input []byte) (Detection, bool) {
+	if format, ok := heifBrands[string(input[8:12])]; ok {
+		return detected(format), true
+	}
+
+	boxSize := int(binary.BigEndian.Uint32(input[:4]))
+	boxSize = min(boxSize, len(input))
+	format := ""
+	for offset := 16; offset+4 <= boxSize; offset += 4 {
+		brandFormat, ok := heifBrands[string(input[offset:offset+4])]
+		if !ok {
+			continue
+		}
+		if brandFormat == "avif" {
+			return detected("avif"), true
+		}
+		format = brandFormat
+	}
+	if format == "" {
+		switch string(input[8:12]) {
+		case "mif1", "msf1":
+			format = "heif"
+		default:
+			return Detection{}, false
+		}
+	}
+
+	return detected(format), true
+}
+
+func isCameraRaw(input []byte) bool {
+	switch {
+	case len(input) >= 11 && bytes.HasPrefix(input, []byte("II*\x00")) && bytes.Equal(input[8:11], []byte("CR\x02")):
+		return true
+	case bytes.HasPrefix(input, []byte("IIRO")), bytes.HasPrefix(input, []byte("IIRS")), bytes.HasPrefix(input, []byte("IIU\x00")):
+		return true
+	case bytes.HasPrefix(input, []byte("FUJIFILMCCD-RAW")):
+		return true
+	case len(input) >= 14 && bytes.HasPrefix(input, []byte("II\x1a\x00\x00\x00")) && bytes.Equal(input[6:14], []byte("HEAPCCDR")):
+		return true
+	default:
+		return false
+	}
+}
+
+func isBMP(input []byte) bool {
+	return len(input) >= 26 && bytes.HasPrefix(input, []byte("BM")) && bytes.Equal(input[6:10], []byte{0, 0, 0, 0})
+}
+
+// isICO matches icon and cursor directories with at least one entry.
+func isICO(input []byte) bool {
+	if len(input) < 6 || input[0] != 0 || input[1] != 0 || input[3] != 0 {
+		return false
+	}
+
+	return (input[2] == 1 || input[2] == 2) && binary.LittleEndian.Uint16(input[4:6]) > 0
+}
+
+func isPCX(input []byte) bool {
+	if len(input) < 128 || input[0] != 0x0a || input[2] != 1 {
+		return false
+	}
+
+	switch input[1] {
+	case 0, 2, 3, 4, 5:
+	default:
+		return false
+	}
+	switch input[3] {
+	case 1, 2, 4, 8:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSVG(input []byte) bool {
+	window := bytes.TrimPrefix(sniffWindow(input), []byte("\xef\xbb\xbf"))
+	window = bytes.TrimSpace(window)
+	if !bytes.HasPrefix(window, []byte("<")) {
+		return false
+	}
+
+	return bytes.Contains(window, []byte("<svg"))
+}
+
+func sniffWindow(input []byte) []byte {
+	return input[:min(len(input), sniffLimit)]
+}
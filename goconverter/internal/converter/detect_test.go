@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    []byte
+		expected Detection
+	}{
+		{name: "png", input: mustEncodePNG(t), expected: Detection{Format: "png"}},
+		{name: "jpeg", input: mustEncodeJPEG(t), expected: Detection{Format: "jpeg"}},
+		{name: "gif", input: mustEncodeAnimatedGIF(t, 1), expected: Detection{Format: "gif"}},
+		{name: "webp", input: []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), expected: Detection{Format: "webp"}},
+		{name: "tiff", input: mustEncodeMultiPageTIFF(t, 1), expected: Detection{Format: "tiff"}},
+		{name: "avif", input: isoBMFFHeader("avif", "mif1", "miaf"), expected: Detection{Format: "avif"}},
+		{name: "generic avif", input: isoBMFFHeader("mif1", "avif", "miaf"), expected: Detection{Format: "avif"}},
+		{name: "heic", input: isoBMFFHeader("heic", "mif1", "heic"), expected: Detection{Format: "heif"}},
+		{name: "jxl", input: []byte{0xff, 0x0a, 0xfa, 0x1f}, expected: Detection{Format: "jxl"}},
+		{name: "jxl container", input: []byte("\x00\x00\x00\x0cJXL \r\n\x87\n"), expected: Detection{Format: "jxl"}},
+		{name: "jp2", input: []byte("\x00\x00\x00\x0cjP  \r\n\x87\n"), expected: Detection{Format: "jp2k"}},
+		{name: "j2k", input: []byte{0xff, 0x4f, 0xff, 0x51, 0x00, 0x2f}, expected: Detection{Format: "jp2k"}},
+		{name: "pdf", input: []byte("%PDF-1.7\n"), expected: Detection{Format: "pdf"}},
+		{name: "svg", input: mustEncodeSVG(), expected: Detection{Format: "svg"}},
+		{name: "svg with prolog", input: []byte("\xef\xbb\xbf<?xml version=\"1.0\"?>\n<svg xmlns=\"http://www.w3.org/2000/svg\"/>"), expected: Detection{Format: "svg"}},
+		{name: "bmp", input: append([]byte("BM\x46\x00\x00\x00\x00\x00\x00\x00"), make([]byte, 16)...), expected: Detection{Format: "bmp"}},
+		{name: "ico", input: []byte{0, 0, 1, 0, 1, 0, 16, 16}, expected: Detection{Format: "ico"}},
+		{name: "psd", input: []byte("8BPS\x00\x01"), expected: Detection{Format: "psd"}},
+		{name: "xcf", input: []byte("gimp xcf v011\x00"), expected: Detection{Format: "xcf"}},
+		{name: "pcx", input: append([]byte{0x0a, 5, 1, 8}, make([]byte, 124)...), expected: Detection{Format: "pcx"}},
+		{name: "tga", input: append(make([]byte, 32), []byte("TRUEVISION-XFILE.\x00")...), expected: Detection{Format: "tga"}},
+		{name: "cr2", input: []byte("II*\x00\x10\x00\x00\x00CR\x02\x00"), expected: Detection{Format: "raw"}},
+		{name: "raf", input: []byte("FUJIFILMCCD-RAW 0201"), expected: Detection{Format: "raw"}},
+	}
+
+	for _, tc := range cases {
+		detection, ok := DetectFormat(tc.input)
+		if !ok {
+			t.Fatalf("%s: expected input to be detected", tc.name)
+		}
+		if detection != tc.expected {
+			t.Fatalf("%s: expected %+v, got %+v", tc.name, tc.expected, detection)
+		}
+	}
+}
+
+func TestDetectFormatRejectsUnknownInput(t *testing.T) {
+	inputs := [][]byte{
+		nil,
+		[]byte("hello world"),
+		[]byte("<html><body></body></html>"),
+		bytes.Repeat([]byte{0x42}, 64),
+	}
+
+	for _, input := range inputs {
+		if detection, ok := DetectFormat(input); ok {
+			t.Fatalf("expected %q not to be detected, got %+v", input, detection)
+		}
+	}
+}
+
+func TestDetectionMatches(t *testing.T) {
+	if !(Detection{Format: "png"}).Matches("png") {
+		t.Fatal("expected png to match png")
+	}
+	if (Detection{Format: "png"}).Matches("jpeg") {
+		t.Fatal("expected png not to match jpeg")
+	}
+	if !(Detection{Format: "tiff"}).Matches("raw") {
+		t.Fatal("expected TIFF-based raw input to match raw")
+	}
+	if !(Detection{Format: "psd"}).Matches("magick") || !(Detection{Format: "tiff"}).Matches("magick") {
+		t.Fatal("expected ImageMagick-loaded input to match magick")
+	}
+	if (Detection{Format: "png"}).Matches("magick") {
+		t.Fatal("expected png not to match magick")
+	}
+}
+
+func isoBMFFHeader(majorBrand string, compatibleBrands ...string) []byte {
+	size := 16 + 4*len(compatibleBrands)
+	header := []byte{0, 0, 0, byte(size)}
+	header = append(header, "ftyp"...)
+	header = append(header, majorBrand...)
+	header = append(header, 0, 0, 0, 0)
+	for _, brand := range compatibleBrands {
+		header = append(header, brand...)
+	}
+
+	return header
+}
@@ -0,0 +1,371 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	ToolProtocolStdio = "stdio"
+	ToolProtocolFile  = "file"
+)
+
+// Placeholders replaced in the command of file protocol tools.
+const (
+	toolInputPlaceholder  = "{input}"
+	toolOutputPlaceholder = "{output}"
+)
+
+const (
+	defaultExternalToolPriority = 200
+	defaultToolTimeout          = 30 * time.Second
+	defaultToolCPUSeconds       = 30
+	defaultToolMemoryBytes      = 1 << 30
+	defaultToolFileSizeBytes    = 256 << 20
+	defaultToolOpenFiles        = 64
+	toolStderrLimit             = 4096
+)
+
+// toolPath is the only PATH a tool sees.
+const toolPath = "/usr/local/bin:/usr/bin:/bin"
+
+// ExternalToolsConfig is the operator config file listing extra converters.
+type ExternalToolsConfig struct {
+	Tools []ExternalTool `json:"tools"`
+}
+
+// ExternalTool declares a command line converter for one pair. Stdio tools
+// read the input from stdin and write the output to stdout; file tools get
+// {input} and {output} paths inside their scratch directory. Priority
+// defaults to above libvips, so declared tools win for their pair.
+type ExternalTool struct {
+	Name      string     `json:"name"`
+	Source    string     `json:"source"`
+	Target    string     `json:"target"`
+	Command   []string   `json:"command"`
+	Protocol  string     `json:"protocol"`
+	TimeoutMs int        `json:"timeoutMs"`
+	Priority  *int       `json:"priority"`
+	Limits    ToolLimits `json:"limits"`
+}
+
+// ToolLimits are the rlimits a tool process runs under. Zero values use the
+// defaults. FileSizeBytes also caps the output read back from the tool.
+type ToolLimits struct {
+	CPUSeconds    int   `json:"cpuSeconds"`
+	MemoryBytes   int64 `json:"memoryBytes"`
+	FileSizeBytes int64 `json:"fileSizeBytes"`
+	OpenFiles     int   `json:"openFiles"`
+}
+
+// LoadExternalTools reads and validates a tools config file.
+func LoadExternalTools(path string) ([]ExternalTool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read external tools config: %w", err)
+	}
+
+	var config ExternalToolsConfig
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("parse external tools config %s: %w", path, err)
+	}
+
+	names := map[string]bool{BackendVips: true, BackendGo: true}
+	for index, tool := range config.Tools {
+		if err := tool.validate(); err != nil {
+			return nil, fmt.Errorf("external tool %d: %w", index+1, err)
+		}
+		if names[tool.Name] {
+			return nil, fmt.Errorf("external tool %d: name %q is already in use", index+1, tool.Name)
+		}
+		names[tool.Name] = true
+	}
+
+	return config.Tools, nil
+}
+
+// RegisterExternalTools registers every tool as its own backend.
+func RegisterExternalTools(tools []ExternalTool) {
+	for _, tool := range tools {
+		tool := tool.withDefaults()
+		RegisterBackend(Backend{
+			Name:     tool.Name,
+			Priority: *tool.Priority,
+			Converters: func() []Converter {
+				return []Converter{&toolConverter{tool: tool}}
+			},
+		})
+	}
+}
+
+func (t ExternalTool) validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("name is required")
+	}
+	if _, ok := LookupFormat(t.Source); !ok {
+		return fmt.Errorf("unknown source format %q", t.Source)
+	}
+	if _, ok := LookupFormat(t.Target); !ok {
+		return fmt.Errorf("unknown target format %q", t.Target)
+	}
+	if t.Source == t.Target {
+		return errors.New("source and target must differ")
+	}
+	if len(t.Command) == 0 || t.Command[0] == "" {
+		return errors.New("command is required")
+	}
+
+	switch t.Protocol {
+	case ToolProtocolStdio:
+	case ToolProtocolFile:
+		joined := strings.Join(t.Command, " ")
+		if !strings.Contains(joined, toolInputPlaceholder) || !strings.Contains(joined, toolOutputPlaceholder) {
+			return fmt.Errorf("file protocol commands must use %s and %s", toolInputPlaceholder, toolOutputPlaceholder)
+		}
+	default:
+		return fmt.Errorf("protocol must be one of %s, %s", ToolProtocolStdio, ToolProtocolFile)
+	}
+
+	if t.TimeoutMs < 0 || t.Limits.CPUSeconds < 0 || t.Limits.MemoryBytes < 0 || t.Limits.FileSizeBytes < 0 || t.Limits.OpenFiles < 0 {
+		return errors.New("timeout and limits must not be negative")
+	}
+
+	return nil
+}
+
+func (t ExternalTool) withDefaults() ExternalTool {
+	if t.Priority == nil {
+		priority := defaultExternalToolPriority
+		t.Priority = &priority
+	}
+	if t.TimeoutMs == 0 {
+		t.TimeoutMs = int(defaultToolTimeout / time.Millisecond)
+	}
+	if t.Limits.CPUSeconds == 0 {
+		t.Limits.CPUSeconds = defaultToolCPUSeconds
+	}
+	if t.Limits.MemoryBytes == 0 {
+		t.Limits.MemoryBytes = defaultToolMemoryBytes
+	}
+	if t.Limits.FileSizeBytes == 0 {
+		t.Limits.FileSizeBytes = defaultToolFileSizeBytes
+	}
+	if t.Limits.OpenFiles == 0 {
+		t.Limits.OpenFiles = defaultToolOpenFiles
+	}
+
+	return t
+}
+
+// toolConverter runs an external tool in a sandbox: a fresh scratch
+// directory as working directory and HOME, an environment with only PATH,
+// HOME, TMPDIR and LANG, rlimits set before the tool starts, and its own
+// process group so a timeout kills every child.
+type toolConverter struct {
+	tool ExternalTool
+}
+
+var _ Converter = (*toolConverter)(nil)
+
+func (c *toolConverter) SourceFormat() string {
+	return c.tool.Source
+}
+
+func (c *toolConverter) TargetFormat() string {
+	return c.tool.Target
+}
+
+func (c *toolConverter) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := c.ConvertWithOptions(ctx, input, Options{})
+	return result.Output, err
+}
+
+func (c *toolConverter) ConvertWithOptions(ctx context.Context, input []byte, options Options) (Result, error) {
+	output, err := c.convert(ctx, input, options)
+	if err != nil {
+		return Result{}, fmt.Errorf("convert %s to %s: %w", c.tool.Source, c.tool.Target, err)
+	}
+
+	return Result{Output: output}, nil
+}
+
+// checkOptions accepts only what the planner passes between hops; tools take
+// their settings from the command line.
+func (c *toolConverter) checkOptions(options Options) error {
+	return checkPassThroughOptions(options, c.tool.Name)
+}
+
+// checkPassThroughOptions accepts only the options the planner sets on
+// intermediate hops, for backends that take no options of their own.
+func checkPassThroughOptions(options Options, backend string) error {
+	rest := options
+	rest.DisableAutoRotate = false
+	if rest.ColorSpace == ColorSpaceKeep {
+		rest.ColorSpace = ""
+	}
+	if !rest.IsZero() {
+		return invalidOptionsError("options are not supported by the %s backend", backend)
+	}
+
+	return nil
+}
+
+// convert runs the tool under its own timeout. A done request context stops
+// the tool too, but is reported as is rather than as a retryable failure.
+func (c *toolConverter) convert(parent context.Context, input []byte, options Options) ([]byte, error) {
+	if err := c.checkOptions(options); err != nil {
+		return nil, err
+	}
+
+	scratch, err := os.MkdirTemp("", "goconverter-tool-")
+	if err != nil {
+		return nil, fmt.Errorf("create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	inputPath := filepath.Join(scratch, "input"+formatExtension(c.tool.Source))
+	outputPath := filepath.Join(scratch, "output"+formatExtension(c.tool.Target))
+	arguments := make([]string, 0, len(c.tool.Command))
+	for _, argument := range c.tool.Command {
+		argument = strings.ReplaceAll(argument, toolInputPlaceholder, inputPath)
+		argument = strings.ReplaceAll(argument, toolOutputPlaceholder, outputPath)
+		arguments = append(arguments, argument)
+	}
+
+	timeout := time.Duration(c.tool.TimeoutMs) * time.Millisecond
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", append([]string{"-c", c.rlimitScript()}, arguments...)...)
+	cmd.Dir = scratch
+	cmd.Env = []string{"PATH=" + toolPath, "HOME=" + scratch, "TMPDIR=" + scratch, "LANG=C"}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+
+	stdout := &limitedBuffer{limit: c.tool.Limits.FileSizeBytes}
+	stderr := &limitedBuffer{limit: toolStderrLimit}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if c.tool.Protocol == ToolProtocolFile {
+		if err := os.WriteFile(inputPath, input, 0o600); err != nil {
+			return nil, fmt.Errorf("write tool input: %w", err)
+		}
+	} else {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	err = cmd.Run()
+	switch {
+	case parent.Err() != nil:
+		return nil, parent.Err()
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, RetryableError(fmt.Errorf("%s timed out after %s", c.tool.Name, timeout))
+	case err != nil:
+		return nil, RetryableError(fmt.Errorf("%s failed: %w: %s", c.tool.Name, err, strings.TrimSpace(stderr.String())))
+	case stdout.truncated:
+		return nil, RetryableError(fmt.Errorf("%s output exceeds %d bytes", c.tool.Name, c.tool.Limits.FileSizeBytes))
+	}
+
+	if c.tool.Protocol == ToolProtocolStdio {
+		if len(stdout.Bytes()) == 0 {
+			return nil, RetryableError(fmt.Errorf("%s wrote no output", c.tool.Name))
+		}
+		return stdout.Bytes(), nil
+	}
+
+	output, err := readLimitedFile(outputPath, c.tool.Limits.FileSizeBytes)
+	if err != nil {
+		return nil, RetryableError(fmt.Errorf("%s output: %w", c.tool.Name, err))
+	}
+
+	return output, nil
+}
+
+// rlimitScript applies the limits in the shell that then execs the tool, so
+// they are in place before the tool runs its first instruction. The tool and
+// its arguments are passed as positional parameters and never interpolated.
+func (c *toolConverter) rlimitScript() string {
+	limits := c.tool.Limits
+	return strings.Join([]string{
+		"ulimit -t " + strconv.Itoa(limits.CPUSeconds),
+		"ulimit -v " + strconv.FormatInt(limits.MemoryBytes/1024, 10),
+		"ulimit -f " + strconv.FormatInt((limits.FileSizeBytes+511)/512, 10),
+		"ulimit -n " + strconv.Itoa(limits.OpenFiles),
+		`exec "$0" "$@"`,
+	}, " && ")
+}
+
+func formatExtension(name string) string {
+	format, ok := LookupFormat(name)
+	if !ok || len(format.Extensions) == 0 {
+		return ""
+	}
+
+	return "." + format.Extensions[0]
+}
+
+func readLimitedFile(path string, limit int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	output, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(output)) > limit {
+		return nil, fmt.Errorf("exceeds %d bytes", limit)
+	}
+	if len(output) == 0 {
+		return nil, errors.New("output file is empty")
+	}
+
+	return output, nil
+}
+
+// limitedBuffer keeps at most limit bytes and drops the rest, so a runaway
+// tool cannot grow the server's memory. It deliberately does not embed
+// bytes.Buffer, whose ReadFrom would bypass the limit.
+type limitedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	written := len(p)
+	remaining := b.limit - int64(b.buffer.Len())
+	if int64(len(p)) > remaining {
+		b.truncated = true
+		p = p[:max(remaining, 0)]
+	}
+	b.buffer.Write(p)
+
+	return written, nil
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buffer.Bytes()
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buffer.String()
+}
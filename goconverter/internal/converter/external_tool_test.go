@@ -0,0 +1,226 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolConverterStdioProtocol(t *testing.T) {
+	tool := ExternalTool{
+		Name:     "upper",
+		Source:   "svg",
+		Target:   "pdf",
+		Command:  []string{writeToolScript(t, "tr a-z A-Z")},
+		Protocol: ToolProtocolStdio,
+	}
+
+	output, err := (&toolConverter{tool: tool.withDefaults()}).Convert(context.Background(), []byte("<svg/>"))
+	if err != nil {
+		t.Fatalf("expected tool to succeed, got error: %v", err)
+	}
+	if string(output) != "<SVG/>" {
+		t.Fatalf("expected tool output, got %q", output)
+	}
+}
+
+func TestToolConverterFileProtocol(t *testing.T) {
+	tool := ExternalTool{
+		Name:     "copy",
+		Source:   "pdf",
+		Target:   "svg",
+		Command:  []string{writeToolScript(t, `case "$1" in *.pdf) ;; *) exit 2 ;; esac; cat "$1" "$1" > "$2"`), "{input}", "{output}"},
+		Protocol: ToolProtocolFile,
+	}
+
+	output, err := (&toolConverter{tool: tool.withDefaults()}).Convert(context.Background(), []byte("ab"))
+	if err != nil {
+		t.Fatalf("expected tool to succeed, got error: %v", err)
+	}
+	if string(output) != "abab" {
+		t.Fatalf("expected output file content, got %q", output)
+	}
+}
+
+func TestToolConverterRunsInSandbox(t *testing.T) {
+	t.Setenv("GOCONVERTER_TOOL_SECRET", "leaked")
+	tool := ExternalTool{
+		Name:     "sandbox",
+		Source:   "png",
+		Target:   "svg",
+		Command:  []string{writeToolScript(t, `env; echo "cwd=$(pwd)"; echo "files=$(ulimit -n)"; echo "cpu=$(ulimit -t)"`)},
+		Protocol: ToolProtocolStdio,
+		Limits:   ToolLimits{CPUSeconds: 7, OpenFiles: 32},
+	}
+
+	output, err := (&toolConverter{tool: tool.withDefaults()}).Convert(context.Background(), []byte("input"))
+	if err != nil {
+		t.Fatalf("expected tool to succeed, got error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if strings.Contains(string(output), "GOCONVERTER_TOOL_SECRET") {
+		t.Fatalf("expected a clean environment, got %v", lines)
+	}
+	for _, expected := range []string{"PATH=" + toolPath, "LANG=C", "files=32", "cpu=7"} {
+		if !slices.Contains(lines, expected) {
+			t.Fatalf("expected %q in tool output, got %v", expected, lines)
+		}
+	}
+
+	var home, cwd string
+	for _, line := range lines {
+		if value, ok := strings.CutPrefix(line, "HOME="); ok {
+			home = value
+		}
+		if value, ok := strings.CutPrefix(line, "cwd="); ok {
+			cwd = value
+		}
+	}
+	if home == "" || cwd != home || !strings.HasPrefix(filepath.Base(cwd), "goconverter-tool-") {
+		t.Fatalf("expected the scratch directory as HOME and working directory, got HOME %q and cwd %q", home, cwd)
+	}
+	if _, err := os.Stat(cwd); !os.IsNotExist(err) {
+		t.Fatalf("expected scratch directory to be removed, got %v", err)
+	}
+}
+
+func TestToolConverterFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		limits   ToolLimits
+		timeout  int
+		expected string
+	}{
+		{name: "exit status", script: "echo broken >&2; exit 3", expected: "exit status 3: broken"},
+		{name: "timeout", script: "sleep 5", timeout: 100, expected: "timed out after 100ms"},
+		{name: "output limit", script: "head -c 2048 /dev/zero", limits: ToolLimits{FileSizeBytes: 1024}, expected: "output exceeds 1024 bytes"},
+		{name: "empty output", script: "cat > /dev/null", expected: "wrote no output"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := ExternalTool{
+				Name:      "failing",
+				Source:    "png",
+				Target:    "svg",
+				Command:   []string{writeToolScript(t, tt.script)},
+				Protocol:  ToolProtocolStdio,
+				TimeoutMs: tt.timeout,
+				Limits:    tt.limits,
+			}
+
+			startedAt := time.Now()
+			_, err := (&toolConverter{tool: tool.withDefaults()}).Convert(context.Background(), []byte("input"))
+			if !errors.Is(err, ErrRetryable) {
+				t.Fatalf("expected a retryable error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("expected error containing %q, got %v", tt.expected, err)
+			}
+			if time.Since(startedAt) > 3*time.Second {
+				t.Fatalf("expected the tool to be stopped promptly, took %s", time.Since(startedAt))
+			}
+		})
+	}
+}
+
+func TestToolConverterStopsWhenContextIsDone(t *testing.T) {
+	tool := ExternalTool{Name: "slow", Source: "png", Target: "svg", Command: []string{writeToolScript(t, "sleep 5")}, Protocol: ToolProtocolStdio}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	startedAt := time.Now()
+	_, err := (&toolConverter{tool: tool.withDefaults()}).Convert(ctx, []byte("input"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request deadline error, got %v", err)
+	}
+	if errors.Is(err, ErrRetryable) {
+		t.Fatal("did not expect another backend to run after the deadline")
+	}
+	if time.Since(startedAt) > 3*time.Second {
+		t.Fatalf("expected the tool to be stopped promptly, took %s", time.Since(startedAt))
+	}
+}
+
+func TestToolConverterRejectsOptions(t *testing.T) {
+	tool := ExternalTool{Name: "tool", Source: "png", Target: "svg", Command: []string{"true"}, Protocol: ToolProtocolStdio}
+	c := &toolConverter{tool: tool.withDefaults()}
+
+	if err := c.checkOptions(Options{ColorSpace: ColorSpaceKeep, DisableAutoRotate: true}); err != nil {
+		t.Fatalf("expected planner pass-through options to be accepted, got %v", err)
+	}
+	if err := c.checkOptions(Options{Quality: intPointer(80)}); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected ErrInvalidOptions, got %v", err)
+	}
+}
+
+func TestLoadExternalToolsValidatesConfig(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{config: `{"tools":[{"name":"a","source":"png","target":"nope","command":["x"],"protocol":"stdio"}]}`, expected: `unknown target format "nope"`},
+		{config: `{"tools":[{"name":"a","source":"pdf","target":"svg","command":["x","{input}"],"protocol":"file"}]}`, expected: "must use {input} and {output}"},
+		{config: `{"tools":[{"name":"a","source":"pdf","target":"svg","command":["x"],"protocol":"socket"}]}`, expected: "protocol must be one of stdio, file"},
+		{config: `{"tools":[{"name":"libvips","source":"pdf","target":"svg","command":["x"],"protocol":"stdio"}]}`, expected: `name "libvips" is already in use`},
+		{config: `{"tools":[{"name":"a","source":"pdf","target":"svg","command":["x"],"protocol":"stdio","shell":true}]}`, expected: `unknown field "shell"`},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "tools.json")
+		if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		_, err := LoadExternalTools(path)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Fatalf("expected error containing %q, got %v", tt.expected, err)
+		}
+	}
+}
+
+func TestRegisterExternalToolsExposesPairs(t *testing.T) {
+	savedBackends := backends
+	t.Cleanup(func() {
+		backends = savedBackends
+		converters = buildConverters()
+	})
+
+	path := filepath.Join(t.TempDir(), "tools.json")
+	config := `{"tools":[{"name":"fake-pdftocairo","source":"pdf","target":"svg","command":["` + writeToolScript(t, "cat") + `"],"protocol":"stdio"}]}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	tools, err := LoadExternalTools(path)
+	if err != nil {
+		t.Fatalf("expected config to load, got error: %v", err)
+	}
+	RegisterExternalTools(tools)
+
+	if backends := ConversionBackends()["pdf"]["svg"]; !slices.Equal(backends, []string{"fake-pdftocairo"}) {
+		t.Fatalf("expected pdf to svg to run on the tool, got %v", backends)
+	}
+	if !slices.Contains(ConversionTargetsBySource()["pdf"], "svg") {
+		t.Fatal("expected svg among pdf targets")
+	}
+
+	c, ok := FindConverter("pdf", "svg")
+	if !ok {
+		t.Fatal("expected a pdf to svg converter")
+	}
+	result, err := c.ConvertWithOptions(context.Background(), []byte("%PDF-"), Options{})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if string(result.Output) != "%PDF-" || !slices.Equal(result.Backends, []string{"fake-pdftocairo"}) {
+		t.Fatalf("unexpected result %q from %v", result.Output, result.Backends)
+	}
+}
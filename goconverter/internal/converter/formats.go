@@ -0,0 +1,253 @@
+package converter
+
+// Format describes one image format. Formats with Load set are conversion
+// sources and formats with a save suffix are libvips targets; the registry
+// pairs every source with every other target that the libvips build
+// supports. BMP, ICO and PDF output comes from the Go backend.
+type Format struct {
+	Name       string
+	MimeType   string
+	Extensions []string
+	// Aliases are alternative names accepted in requests, e.g. "jpg".
+	Aliases []string
+	Load    bool
+	// MultiPage sources accept a page selector; Animated sources keep their
+	// frames when the target has AnimatedSave set.
+	MultiPage    bool
+	Animated     bool
+	AnimatedSave bool
+	// Lossy targets discard information on every save, even with the best
+	// encoder settings; the planner avoids them as intermediates.
+	Lossy bool
+	// Deprecated sources are still converted, but have a better named
+	// format for the inputs they are detected for.
+	Deprecated bool
+
+	// vipsType is the bimg type name probed for support, when it differs
+	// from Name. Formats bimg does not know name their libvips operation
+	// prefix instead, e.g. "jxl" for jxlload_buffer and jxlsave_buffer.
+	// loadProbe returns a minimal file that must also load for the format
+	// to be registered.
+	vipsType      string
+	vipsOperation string
+	loadProbe     func() []byte
+	// saveSuffix selects the libvips saver; saveParameters are always added
+	// to its option string.
+	saveSuffix     string
+	saveParameters []string
+	encoder        encoderCapabilities
+}
+
+var formats = []Format{
+	{
+		Name:       "avif",
+		MimeType:   "image/avif",
+		Extensions: []string{"avif"},
+		Load:       true,
+		Animated:   true,
+		// heifsave writes only the first frame, so AVIF output stays still,
+		// and picks HEVC for every suffix unless told otherwise.
+		saveSuffix:     ".avif",
+		saveParameters: []string{"compression=av1"},
+		encoder:        encoderCapabilities{quality: true, defaultQuality: 50, lossless: true, effort: &intRange{min: 0, max: 9}, defaultEffort: 4, chromaSubsampling: true},
+	},
+	{
+		Name:         "gif",
+		MimeType:     "image/gif",
+		Extensions:   []string{"gif"},
+		Load:         true,
+		Animated:     true,
+		AnimatedSave: true,
+		Lossy:        true,
+		saveSuffix:   ".gif",
+	},
+	{
+		Name:       "heif",
+		MimeType:   "image/heif",
+		Extensions: []string{"heif", "heic"},
+		Aliases:    []string{"heic"},
+		Load:       true,
+		MultiPage:  true,
+		saveSuffix: ".heic",
+		encoder:    encoderCapabilities{quality: true, defaultQuality: 50, lossless: true, effort: &intRange{min: 0, max: 9}, defaultEffort: 4, chromaSubsampling: true},
+	},
+	{
+		Name:       "jpeg",
+		MimeType:   "image/jpeg",
+		Extensions: []string{"jpeg", "jpg", "jpe"},
+		Aliases:    []string{"jpg"},
+		Load:       true,
+		Lossy:      true,
+		saveSuffix: ".jpg",
+		encoder:    encoderCapabilities{quality: true, defaultQuality: 75, progressive: true, chromaSubsampling: true},
+	},
+	{
+		Name:       "png",
+		MimeType:   "image/png",
+		Extensions: []string{"png"},
+		Load:       true,
+		saveSuffix: ".png",
+		encoder:    encoderCapabilities{progressive: true, compressionLevel: true},
+	},
+	{
+		Name:       "tiff",
+		MimeType:   "image/tiff",
+		Extensions: []string{"tiff", "tif"},
+		Aliases:    []string{"tif"},
+		Load:       true,
+		MultiPage:  true,
+		saveSuffix: ".tif",
+	},
+	{
+		Name:         "webp",
+		MimeType:     "image/webp",
+		Extensions:   []string{"webp"},
+		Load:         true,
+		Animated:     true,
+		AnimatedSave: true,
+		saveSuffix:   ".webp",
+		encoder:      encoderCapabilities{quality: true, defaultQuality: 75, lossless: true, effort: &intRange{min: 0, max: 6}, defaultEffort: 4},
+	},
+	{
+		Name:          "jxl",
+		MimeType:      "image/jxl",
+		Extensions:    []string{"jxl"},
+		Load:          true,
+		vipsOperation: "jxl",
+		saveSuffix:    ".jxl",
+		encoder:       encoderCapabilities{quality: true, defaultQuality: 75, lossless: true, effort: &intRange{min: 1, max: 9}, defaultEffort: 7},
+	},
+	{
+		Name:          "jp2k",
+		MimeType:      "image/jp2",
+		Extensions:    []string{"jp2", "j2k", "jpf", "jpx"},
+		Aliases:       []string{"jp2", "j2k", "jpeg2000"},
+		Load:          true,
+		vipsOperation: "jp2k",
+		saveSuffix:    ".jp2",
+		encoder:       encoderCapabilities{quality: true, defaultQuality: 48, lossless: true, chromaSubsampling: true},
+	},
+	// BMP to RAW load through ImageMagick. Each one is listed as a source
+	// only when its probe file loads at startup, since the coders compiled
+	// into ImageMagick vary between builds.
+	{
+		Name:       "bmp",
+		MimeType:   "image/bmp",
+		Extensions: []string{"bmp", "dib"},
+		Aliases:    []string{"dib"},
+		Load:       true,
+		vipsType:   magickVipsType,
+		loadProbe:  bmpProbe,
+	},
+	{
+		Name:       "ico",
+		MimeType:   "image/vnd.microsoft.icon",
+		Extensions: []string{"ico", "cur"},
+		Aliases:    []string{"cur"},
+		Load:       true,
+		vipsType:   magickVipsType,
+		loadProbe:  icoProbe,
+	},
+	{
+		Name:       "psd",
+		MimeType:   "image/vnd.adobe.photoshop",
+		Extensions: []string{"psd"},
+		Load:       true,
+		vipsType:   magickVipsType,
+		loadProbe:  psdProbe,
+	},
+	{
+		Name:       "tga",
+		MimeType:   "image/x-tga",
+		Extensions: []string{"tga", "tpic"},
+		Aliases:    []string{"targa"},
+		Load:       true,
+		vipsType:   magickVipsType,
+		loadProbe:  tgaProbe,
+	},
+	{
+		Name:       "pcx",
+		MimeType:   "image/vnd.zbrush.pcx",
+		Extensions: []string{"pcx"},
+		Load:       true,
+		vipsType:   magickVipsType,
+		loadProbe:  pcxProbe,
+	},
+	{
+		Name:       "xcf",
+		MimeType:   "image/x-xcf",
+		Extensions: []string{"xcf"},
+		Load:       true,
+		vipsType:   magickVipsType,
+		loadProbe:  xcfProbe,
+	},
+	{
+		// Camera raw files need a raw delegate in ImageMagick, which cannot
+		// be probed without a sample photo, so only the loader is checked.
+		Name:       "raw",
+		MimeType:   "image/x-dcraw",
+		Extensions: []string{"cr2", "cr3", "crw", "nef", "arw", "dng", "raf", "orf", "rw2"},
+		Aliases:    []string{"cr2", "cr3", "crw", "nef", "arw", "dng", "raf", "orf", "rw2"},
+		Load:       true,
+		vipsType:   magickVipsType,
+	},
+	{
+		// magick loads whatever ImageMagick reads, including formats without
+		// a name of their own such as DICOM, EPS or PPM. It predates the
+		// named formats above and is kept for clients that still send it.
+		Name:       magickSourceFormat,
+		MimeType:   "application/octet-stream",
+		Load:       true,
+		Deprecated: true,
+	},
+	{
+		// PDF output is written by the Go backend, one image per page.
+		Name:       "pdf",
+		MimeType:   "application/pdf",
+		Extensions: []string{"pdf"},
+		Load:       true,
+		MultiPage:  true,
+		encoder:    pdfEncoder,
+	},
+	{
+		Name:       "svg",
+		MimeType:   "image/svg+xml",
+		Extensions: []string{"svg"},
+		Load:       true,
+	},
+}
+
+var formatsByName = buildFormatsByName()
+
+func buildFormatsByName() map[string]Format {
+	output := make(map[string]Format, len(formats))
+	for _, format := range formats {
+		output[format.Name] = format
+	}
+
+	return output
+}
+
+// Formats returns the format table in registry order.
+func Formats() []Format {
+	output := make([]Format, len(formats))
+	copy(output, formats)
+	return output
+}
+
+func LookupFormat(name string) (Format, bool) {
+	format, ok := formatsByName[name]
+	return format, ok
+}
+
+func (f Format) CanSave() bool {
+	return f.saveSuffix != ""
+}
+
+func (f Format) typeName() string {
+	if f.vipsType != "" {
+		return f.vipsType
+	}
+
+	return f.Name
+}
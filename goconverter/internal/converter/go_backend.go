@@ -0,0 +1,277 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/tiff"
+	"golang.org/x/image/webp"
+)
+
+// goCodec reads and, when encode is set, writes one format with the Go image
+// packages. It backs pairs the libvips build cannot load or save.
+type goCodec struct {
+	decode  func(io.Reader) (image.Image, error)
+	encode  func(io.Writer, image.Image, Options) error
+	encoder encoderCapabilities
+}
+
+// goCodecs lists the formats the fallback backend handles. Magick input is
+// only decoded when it is a BMP file.
+var goCodecs = map[string]goCodec{
+	"gif": {
+		decode: gif.Decode,
+		encode: func(w io.Writer, img image.Image, _ Options) error {
+			return gif.Encode(w, img, nil)
+		},
+	},
+	"jpeg": {
+		decode:  jpeg.Decode,
+		encode:  encodeGoJPEG,
+		encoder: encoderCapabilities{quality: true, defaultQuality: jpeg.DefaultQuality},
+	},
+	"png": {
+		decode:  png.Decode,
+		encode:  encodeGoPNG,
+		encoder: encoderCapabilities{compressionLevel: true},
+	},
+	"tiff": {
+		decode: tiff.Decode,
+		encode: func(w io.Writer, img image.Image, _ Options) error {
+			return tiff.Encode(w, img, nil)
+		},
+	},
+	"webp": {decode: webp.Decode},
+	"bmp": {
+		decode: bmp.Decode,
+		encode: func(w io.Writer, img image.Image, _ Options) error {
+			return bmp.Encode(w, img)
+		},
+	},
+	"ico":              {encode: encodeGoICO},
+	"pdf":              {encode: encodeGoPDF, encoder: pdfEncoder},
+	magickSourceFormat: {decode: bmp.Decode},
+}
+
+const (
+	maxICOSize    = 256
+	icoHeaderSize = 6 + 16
+)
+
+// goConverter converts a pair with the Go image packages. It decodes the first
+// image only and writes no metadata; pixels are not color managed. JPEG and
+// TIFF pixels are turned by their EXIF orientation unless auto-rotation is
+// disabled.
+type goConverter struct {
+	source string
+	target string
+}
+
+var _ streamConverter = (*goConverter)(nil)
+
+func newGoConverter(source string, target string) *goConverter {
+	return &goConverter{source: source, target: target}
+}
+
+func goBackendSupports(source string, target string) bool {
+	return goCodecs[source].decode != nil && goCodecs[target].encode != nil
+}
+
+func goConverters() []Converter {
+	var output []Converter
+	for _, source := range formats {
+		for _, target := range formats {
+			if source.Name != target.Name && goBackendSupports(source.Name, target.Name) {
+				output = append(output, newGoConverter(source.Name, target.Name))
+			}
+		}
+	}
+
+	return output
+}
+
+func (c *goConverter) SourceFormat() string {
+	return c.source
+}
+
+func (c *goConverter) TargetFormat() string {
+	return c.target
+}
+
+func (c *goConverter) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := c.ConvertWithOptions(ctx, input, Options{})
+	return result.Output, err
+}
+
+func (c *goConverter) ConvertWithOptions(ctx context.Context, input []byte, options Options) (Result, error) {
+	return c.convertTo(ctx, input, options, nil)
+}
+
+func (c *goConverter) convertTo(ctx context.Context, input []byte, options Options, w io.Writer) (Result, error) {
+	result, err := c.convert(ctx, input, options, w)
+	if err != nil {
+		return Result{}, fmt.Errorf("convert %s to %s: %w", c.source, c.target, err)
+	}
+
+	return result, nil
+}
+
+// convert encodes into w, or into Result.Output when w is nil. It checks the
+// context between decoding and encoding; the Go codecs themselves cannot be
+// interrupted.
+func (c *goConverter) convert(ctx context.Context, input []byte, options Options, w io.Writer) (Result, error) {
+	if err := ValidateOptions(c.source, c.target, options); err != nil {
+		return Result{}, err
+	}
+	if err := c.checkOptions(options); err != nil {
+		return Result{}, err
+	}
+
+	if err := checkGoImageSize(input); err != nil {
+		return Result{}, err
+	}
+	img, err := goCodecs[c.source].decode(bytes.NewReader(input))
+	if err != nil {
+		return Result{}, RetryableError(fmt.Errorf("decode: %w", err))
+	}
+	if !options.DisableAutoRotate {
+		img = orientImage(img, goOrientation(c.source, input))
+	}
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	var output *bytes.Buffer
+	if w == nil {
+		output = &bytes.Buffer{}
+		w = output
+	}
+	if err := goCodecs[c.target].encode(w, img, options); err != nil {
+		return Result{}, fmt.Errorf("encode: %w", err)
+	}
+
+	size := img.Bounds().Size()
+	geometry := Geometry{
+		SourceWidth:  size.X,
+		SourceHeight: size.Y,
+		ScaledWidth:  size.X,
+		ScaledHeight: size.Y,
+		Width:        size.X,
+		Height:       size.Y,
+	}
+	result := Result{Geometry: geometry}
+	if output != nil {
+		result.Output = output.Bytes()
+	}
+	return result, nil
+}
+
+func (c *goConverter) checkOptions(options Options) error {
+	return validateGoOptions(c.target, options)
+}
+
+// validateGoOptions rejects options the Go backend cannot honor. Defaults are
+// accepted even where libvips would do more, e.g. convert to sRGB.
+func validateGoOptions(targetFormat string, options Options) error {
+	switch {
+	case !options.Transform.IsZero():
+		return unsupportedBackendOptionError("transform", BackendGo)
+	case !options.Rasterize.IsZero():
+		return unsupportedBackendOptionError("rasterize", BackendGo)
+	case options.Pages != "":
+		return unsupportedBackendOptionError("pages", BackendGo)
+	case options.Frame != nil:
+		return unsupportedBackendOptionError("frame", BackendGo)
+	case options.Metadata != "" && options.Metadata != MetadataStrip:
+		return invalidOptionsError("metadata must be %s with the %s backend", MetadataStrip, BackendGo)
+	case options.ColorSpace != "" && options.ColorSpace != ColorSpaceKeep:
+		return invalidOptionsError("colorSpace must be %s with the %s backend", ColorSpaceKeep, BackendGo)
+	case options.ICCProfile != "" && options.ICCProfile != ICCProfileOmit:
+		return invalidOptionsError("iccProfile must be %s with the %s backend", ICCProfileOmit, BackendGo)
+	}
+
+	return validateEncoderOptions(targetFormat, goCodecs[targetFormat].encoder, options)
+}
+
+func unsupportedBackendOptionError(name string, backend string) error {
+	return invalidOptionsError("%s is not supported by the %s backend", name, backend)
+}
+
+func encodeGoJPEG(w io.Writer, img image.Image, options Options) error {
+	quality := jpeg.DefaultQuality
+	if options.Quality != nil {
+		quality = *options.Quality
+	}
+
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+// encodeGoPNG maps the libvips 0-9 compression scale onto the four levels
+// image/png offers.
+func encodeGoPNG(w io.Writer, img image.Image, options Options) error {
+	encoder := png.Encoder{}
+	if options.CompressionLevel != nil {
+		switch level := *options.CompressionLevel; {
+		case level == 0:
+			encoder.CompressionLevel = png.NoCompression
+		case level <= 3:
+			encoder.CompressionLevel = png.BestSpeed
+		case level >= 7:
+			encoder.CompressionLevel = png.BestCompression
+		}
+	}
+
+	return encoder.Encode(w, img)
+}
+
+// encodeGoICO writes a single-entry icon holding a PNG, which every
+// Windows version since Vista reads. Icons are at most 256 pixels wide and
+// high, so larger images are scaled down to fit.
+func encodeGoICO(w io.Writer, img image.Image, _ Options) error {
+	img = fitWithin(img, maxICOSize)
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return err
+	}
+
+	size := img.Bounds().Size()
+	header := make([]byte, 0, icoHeaderSize)
+	header = binary.LittleEndian.AppendUint16(header, 0)
+	header = binary.LittleEndian.AppendUint16(header, 1)
+	header = binary.LittleEndian.AppendUint16(header, 1)
+	// A width or height of 0 stands for 256.
+	header = append(header, byte(size.X%maxICOSize), byte(size.Y%maxICOSize), 0, 0)
+	header = binary.LittleEndian.AppendUint16(header, 1)
+	header = binary.LittleEndian.AppendUint16(header, 32)
+	header = binary.LittleEndian.AppendUint32(header, uint32(encoded.Len()))
+	header = binary.LittleEndian.AppendUint32(header, icoHeaderSize)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(encoded.Bytes())
+	return err
+}
+
+func fitWithin(img image.Image, limit int) image.Image {
+	size := img.Bounds().Size()
+	if size.X <= limit && size.Y <= limit {
+		return img
+	}
+
+	scale := float64(limit) / float64(max(size.X, size.Y))
+	width := max(1, int(math.Round(float64(size.X)*scale)))
+	height := max(1, int(math.Round(float64(size.Y)*scale)))
+	output := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(output, output.Bounds(), img, img.Bounds(), draw.Src, nil)
+	return output
+}
@@ -0,0 +1,207 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/bmp"
+)
+
+func TestGoBackendPairs(t *testing.T) {
+	fixtures := map[string][]byte{
+		"gif":  mustEncodeAnimatedGIF(t, 2),
+		"jpeg": mustEncodeJPEG(t),
+		"png":  mustEncodePNG(t),
+		"tiff": mustEncodeGoFixture(t, encodeUncompressedGoTIFF),
+		"bmp":  mustEncodeGoFixture(t, bmp.Encode),
+	}
+	decodedNames := map[string]string{"gif": "gif", "jpeg": "jpeg", "png": "png", "tiff": "tiff"}
+
+	for source, input := range fixtures {
+		for target, name := range decodedNames {
+			if source == target {
+				continue
+			}
+
+			t.Run(source+"_to_"+target, func(t *testing.T) {
+				result, err := newGoConverter(source, target).ConvertWithOptions(context.Background(), input, Options{})
+				if err != nil {
+					t.Fatalf("expected conversion to succeed, got error: %v", err)
+				}
+
+				config, format, err := image.DecodeConfig(bytes.NewReader(result.Output))
+				if err != nil {
+					t.Fatalf("failed to decode output: %v", err)
+				}
+				if format != name {
+					t.Fatalf("expected %s output, got %s", name, format)
+				}
+				if config.Width != result.Geometry.Width || config.Height != result.Geometry.Height {
+					t.Fatalf("expected geometry %dx%d, got %dx%d", config.Width, config.Height, result.Geometry.Width, result.Geometry.Height)
+				}
+			})
+		}
+	}
+}
+
+func TestGoBackendWritesBMPAndICO(t *testing.T) {
+	output, err := newGoConverter("png", "bmp").Convert(context.Background(), mustEncodeGradientPNG(t, 4, 3))
+	if err != nil {
+		t.Fatalf("expected png to bmp to succeed, got error: %v", err)
+	}
+	if config, err := bmp.DecodeConfig(bytes.NewReader(output)); err != nil || config.Width != 4 || config.Height != 3 {
+		t.Fatalf("expected a 4x3 bmp, got %+v, %v", config, err)
+	}
+
+	output, err = newGoConverter("png", "ico").Convert(context.Background(), mustEncodeGradientPNG(t, 512, 128))
+	if err != nil {
+		t.Fatalf("expected png to ico to succeed, got error: %v", err)
+	}
+	if detection, _ := DetectFormat(output); detection.Format != "ico" {
+		t.Fatalf("expected ico output, got %q", detection.Format)
+	}
+	if output[6] != 0 || output[7] != 64 {
+		t.Fatalf("expected a 256x64 icon entry, got %dx%d", output[6], output[7])
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(output[icoHeaderSize:]))
+	if err != nil || config.Width != maxICOSize || config.Height != 64 {
+		t.Fatalf("expected the icon to embed a 256x64 png, got %+v, %v", config, err)
+	}
+}
+
+func TestGoBackendWritesPDF(t *testing.T) {
+	output, err := newGoConverter("jpeg", "pdf").ConvertWithOptions(context.Background(), mustEncodeJPEG(t), Options{DPI: intPointer(300), PageSize: PageSizeA5})
+	if err != nil {
+		t.Fatalf("expected jpeg to pdf to succeed, got error: %v", err)
+	}
+	if detection, _ := DetectFormat(output.Output); detection.Format != "pdf" {
+		t.Fatalf("expected pdf output, got %q", detection.Format)
+	}
+	if !bytes.Contains(output.Output, []byte("/MediaBox [0 0 419.53 595.28]")) {
+		t.Fatalf("expected an A5 page, got %q", output.Output)
+	}
+}
+
+func TestGoBackendAppliesEncoderOptions(t *testing.T) {
+	input := mustEncodeGradientPNG(t, 64, 64)
+	converter := newGoConverter("png", "jpeg")
+
+	low, err := converter.ConvertWithOptions(context.Background(), input, Options{Quality: intPointer(10)})
+	if err != nil {
+		t.Fatalf("expected low quality conversion to succeed, got error: %v", err)
+	}
+	high, err := converter.ConvertWithOptions(context.Background(), input, Options{Quality: intPointer(95)})
+	if err != nil {
+		t.Fatalf("expected high quality conversion to succeed, got error: %v", err)
+	}
+	if len(low.Output) >= len(high.Output) {
+		t.Fatalf("expected quality 10 output to be smaller than quality 95, got %d and %d bytes", len(low.Output), len(high.Output))
+	}
+}
+
+func TestGoBackendEncodesIntoWriter(t *testing.T) {
+	var output bytes.Buffer
+	result, err := newGoConverter("png", "bmp").convertTo(context.Background(), mustEncodePNG(t), Options{}, &output)
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if _, err := bmp.Decode(&output); err != nil {
+		t.Fatalf("expected bmp to be written, got error: %v", err)
+	}
+	if result.Output != nil || result.Geometry.Width == 0 {
+		t.Fatalf("expected geometry without output, got %+v", result)
+	}
+}
+
+func TestGoBackendRejectsUnsupportedOptions(t *testing.T) {
+	tests := []struct {
+		options  Options
+		expected string
+	}{
+		{options: Options{Transform: Transform{Width: 1}}, expected: "transform is not supported by the go backend"},
+		{options: Options{Metadata: MetadataKeep}, expected: "metadata must be strip"},
+		{options: Options{ColorSpace: ColorSpaceP3}, expected: "colorSpace must be keep"},
+		{options: Options{Progressive: true}, expected: "progressive is not supported for jpeg output"},
+	}
+
+	for _, tt := range tests {
+		_, err := newGoConverter("png", "jpeg").ConvertWithOptions(context.Background(), mustEncodePNG(t), tt.options)
+		if !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("expected ErrInvalidOptions for %+v, got %v", tt.options, err)
+		}
+		if !strings.Contains(err.Error(), tt.expected) {
+			t.Fatalf("expected error containing %q, got %v", tt.expected, err)
+		}
+	}
+}
+
+func TestGoBackendRejectsInvalidInput(t *testing.T) {
+	assertInvalidInputError(t, newGoConverter("png", "jpeg"), "png", "jpeg")
+}
+
+func TestGoBackendServesPairsMissingFromLibvips(t *testing.T) {
+	backends := ConversionBackends()
+	for _, source := range []string{"gif", "jpeg", "png", "tiff"} {
+		for _, target := range []string{"gif", "jpeg", "png", "tiff"} {
+			if source == target {
+				continue
+			}
+
+			if !slices.Contains(backends[source][target], BackendGo) {
+				t.Fatalf("expected the go backend for %s to %s, got %v", source, target, backends[source][target])
+			}
+		}
+	}
+}
+
+func TestGoBackendAppliesEXIFOrientation(t *testing.T) {
+	// The left half is red and the right half blue.
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 8))
+	for y := range 8 {
+		for x := range 16 {
+			img.Set(x, y, color.NRGBA{R: uint8(255 * (1 - x/8)), B: uint8(255 * (x / 8)), A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("failed to encode jpeg fixture: %v", err)
+	}
+	// An APP1 segment with a big-endian TIFF structure holding orientation 6,
+	// rotate 90 degrees clockwise.
+	exif := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00\x00\x00")
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(exif) + 2)}, exif...)
+	input := append(append([]byte{0xFF, 0xD8}, segment...), encoded.Bytes()[2:]...)
+
+	tests := []struct {
+		options Options
+		width   int
+		height  int
+	}{
+		{options: Options{}, width: 8, height: 16},
+		{options: Options{DisableAutoRotate: true}, width: 16, height: 8},
+	}
+	for _, tt := range tests {
+		result, err := newGoConverter("jpeg", "png").ConvertWithOptions(context.Background(), input, tt.options)
+		if err != nil {
+			t.Fatalf("expected conversion to succeed, got error: %v", err)
+		}
+		output, err := png.Decode(bytes.NewReader(result.Output))
+		if err != nil {
+			t.Fatalf("expected png output, got error: %v", err)
+		}
+		if size := output.Bounds().Size(); size.X != tt.width || size.Y != tt.height {
+			t.Fatalf("%+v: expected %dx%d, got %v", tt.options, tt.width, tt.height, size)
+		}
+		if r, _, b, _ := output.At(2, 2).RGBA(); r < b {
+			t.Fatalf("%+v: expected red in the top left corner, got %v", tt.options, output.At(2, 2))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+const exifOrientationTag = 0x0112
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// goOrientation reads the EXIF orientation of a JPEG or TIFF input, or
+// returns 1, the stored orientation, when it has none.
+func goOrientation(format string, input []byte) int {
+	switch format {
+	case "jpeg":
+		return tiffOrientation(jpegEXIF(input))
+	case "tiff":
+		return tiffOrientation(input)
+	default:
+		return 1
+	}
+}
+
+// jpegEXIF returns the TIFF structure of the EXIF segment of a JPEG file.
+func jpegEXIF(input []byte) []byte {
+	if len(input) < 2 || input[0] != 0xFF || input[1] != 0xD8 {
+		return nil
+	}
+
+	offset := 2
+	for offset+4 <= len(input) && input[offset] == 0xFF {
+		marker := input[offset+1]
+		// Image data follows the start of scan marker.
+		if marker == 0xDA {
+			return nil
+		}
+		end := offset + 2 + int(binary.BigEndian.Uint16(input[offset+2:]))
+		if end > len(input) {
+			return nil
+		}
+		segment := input[offset+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, exifHeader) {
+			return segment[len(exifHeader):]
+		}
+		offset = end
+	}
+
+	return nil
+}
+
+// tiffOrientation reads the orientation tag of the first IFD of a TIFF
+// structure.
+func tiffOrientation(data []byte) int {
+	if len(data) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(data[4:]))
+	if offset < 8 || offset+2 > len(data) {
+		return 1
+	}
+	entries := int(order.Uint16(data[offset:]))
+	for index := range entries {
+		entry := offset + 2 + index*12
+		if entry+12 > len(data) {
+			return 1
+		}
+		if order.Uint16(data[entry:]) != exifOrientationTag {
+			continue
+		}
+		orientation := int(order.Uint16(data[entry+8:]))
+		if orientation < 1 || orientation > 8 {
+			return 1
+		}
+		return orientation
+	}
+
+	return 1
+}
+
+// orientImage turns img as its EXIF orientation says it is displayed.
+func orientImage(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	// Orientations 5 to 8 swap width and height.
+	outWidth, outHeight := width, height
+	if orientation >= 5 {
+		outWidth, outHeight = height, width
+	}
+
+	output := image.NewNRGBA(image.Rect(0, 0, outWidth, outHeight))
+	for y := range outHeight {
+		for x := range outWidth {
+			var sourceX, sourceY int
+			switch orientation {
+			case 2:
+				sourceX, sourceY = width-1-x, y
+			case 3:
+				sourceX, sourceY = width-1-x, height-1-y
+			case 4:
+				sourceX, sourceY = x, height-1-y
+			case 5:
+				sourceX, sourceY = y, x
+			case 6:
+				sourceX, sourceY = y, height-1-x
+			case 7:
+				sourceX, sourceY = width-1-y, height-1-x
+			case 8:
+				sourceX, sourceY = width-1-y, x
+			}
+			output.Set(x, y, img.At(bounds.Min.X+sourceX, bounds.Min.Y+sourceY))
+		}
+	}
+
+	return output
+}
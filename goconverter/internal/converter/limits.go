@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// ErrInputTooComplex marks inputs whose header declares more pixels, pages
+// or frames than the configured limits. It is not retryable: every backend
+// would have to decode the same image.
+var ErrInputTooComplex = errors.New("input too complex")
+
+// InputLimits bound what one conversion may decode. MaxPixels applies to a
+// single page or frame, after rasterization options; MaxTotalPixels to the
+// pages a combine request holds decoded at once. Zero disables a limit.
+type InputLimits struct {
+	MaxPixels      int64
+	MaxTotalPixels int64
+	MaxPages       int
+	MaxFrames      int
+}
+
+var DefaultInputLimits = InputLimits{
+	MaxPixels:      100_000_000,
+	MaxTotalPixels: 250_000_000,
+	MaxPages:       1000,
+	MaxFrames:      1000,
+}
+
+var inputLimits = DefaultInputLimits
+
+// SetInputLimits replaces the decode limits. It is meant for startup and must
+// not run concurrently with conversions.
+func SetInputLimits(limits InputLimits) {
+	inputLimits = limits
+}
+
+func checkImageSize(width int, height int) error {
+	pixels := int64(width) * int64(height)
+	if inputLimits.MaxPixels > 0 && pixels > inputLimits.MaxPixels {
+		return fmt.Errorf("%w: %dx%d is %d pixels, above the limit of %d", ErrInputTooComplex, width, height, pixels, inputLimits.MaxPixels)
+	}
+
+	return nil
+}
+
+func checkTotalPixels(pixels int64) error {
+	if inputLimits.MaxTotalPixels > 0 && pixels > inputLimits.MaxTotalPixels {
+		return fmt.Errorf("%w: pages of %d pixels in total, above the limit of %d", ErrInputTooComplex, pixels, inputLimits.MaxTotalPixels)
+	}
+
+	return nil
+}
+
+func checkPageCount(pages int) error {
+	if inputLimits.MaxPages > 0 && pages > inputLimits.MaxPages {
+		return fmt.Errorf("%w: %d pages, above the limit of %d", ErrInputTooComplex, pages, inputLimits.MaxPages)
+	}
+
+	return nil
+}
+
+func checkFrameCount(frames int) error {
+	if inputLimits.MaxFrames > 0 && frames > inputLimits.MaxFrames {
+		return fmt.Errorf("%w: %d frames, above the limit of %d", ErrInputTooComplex, frames, inputLimits.MaxFrames)
+	}
+
+	return nil
+}
+
+// checkGoImageSize reads only the image header. Headers the standard decoders
+// cannot parse are left for the decoder to reject.
+func checkGoImageSize(input []byte) error {
+	config, _, err := image.DecodeConfig(bytes.NewReader(input))
+	if err != nil {
+		return nil
+	}
+
+	return checkImageSize(config.Width, config.Height)
+}
@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+)
+
+func TestGoBackendRejectsImagesAboveThePixelLimit(t *testing.T) {
+	_, err := newGoConverter("png", "jpeg").ConvertWithOptions(context.Background(), mustEncodePNGHeader(60000, 60000), Options{})
+	if !errors.Is(err, ErrInputTooComplex) {
+		t.Fatalf("expected input too complex error, got %v", err)
+	}
+	if errors.Is(err, ErrRetryable) {
+		t.Fatal("did not expect the limit to let another backend retry")
+	}
+}
+
+func TestCombineEnforcesInputLimits(t *testing.T) {
+	withInputLimits(t, InputLimits{MaxPixels: 1_000_000, MaxPages: 2})
+
+	if _, err := Combine(context.Background(), [][]byte{mustEncodePNG(t), mustEncodePNG(t), mustEncodePNG(t)}, "pdf", CombineOptions{}); !errors.Is(err, ErrInputTooComplex) {
+		t.Fatalf("expected the page limit to apply, got %v", err)
+	}
+	if _, err := Combine(context.Background(), [][]byte{mustEncodePNGHeader(2000, 2000)}, "pdf", CombineOptions{}); !errors.Is(err, ErrInputTooComplex) {
+		t.Fatalf("expected the pixel limit to apply, got %v", err)
+	}
+	if _, err := Combine(context.Background(), [][]byte{mustEncodePNG(t)}, "tiff", CombineOptions{PageSize: PageSizeA3, DPI: intPointer(600)}); !errors.Is(err, ErrInputTooComplex) {
+		t.Fatalf("expected the pixel limit to apply to rendered pages, got %v", err)
+	}
+}
+
+func TestConvertPagesEnforcesPageLimit(t *testing.T) {
+	requireFormatPairSupport(t, "tiff", "png")
+	withInputLimits(t, InputLimits{MaxPages: 2})
+
+	c, _ := PlanConversion("tiff", "png")
+	if _, err := c.ConvertWithOptions(context.Background(), mustEncodeMultiPageTIFF(t, 3), Options{Pages: PagesAll}); !errors.Is(err, ErrInputTooComplex) {
+		t.Fatalf("expected input too complex error, got %v", err)
+	}
+}
+
+func TestConvertAnimationEnforcesFrameLimit(t *testing.T) {
+	requireFormatPairSupport(t, "gif", "webp")
+	withInputLimits(t, InputLimits{MaxFrames: 2})
+
+	c, _ := PlanConversion("gif", "webp")
+	if _, err := c.ConvertWithOptions(context.Background(), mustEncodeAnimatedGIF(t, 3), Options{}); !errors.Is(err, ErrInputTooComplex) {
+		t.Fatalf("expected input too complex error, got %v", err)
+	}
+}
+
+func withInputLimits(t *testing.T, limits InputLimits) {
+	t.Helper()
+
+	previous := inputLimits
+	SetInputLimits(limits)
+	t.Cleanup(func() { SetInputLimits(previous) })
+}
+
+// mustEncodePNGHeader writes a PNG that declares the given size but carries
+// no pixel data, like a decompression bomb before its payload.
+func mustEncodePNGHeader(width uint32, height uint32) []byte {
+	var header bytes.Buffer
+	header.WriteString("IHDR")
+	binary.Write(&header, binary.BigEndian, []uint32{width, height})
+	header.Write([]byte{8, 6, 0, 0, 0})
+
+	var output bytes.Buffer
+	output.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&output, binary.BigEndian, uint32(header.Len()-4))
+	output.Write(header.Bytes())
+	binary.Write(&output, binary.BigEndian, crc32.ChecksumIEEE(header.Bytes()))
+	output.Write([]byte{0, 0, 0, 0, 'I', 'E', 'N', 'D', 0xae, 0x42, 0x60, 0x82})
+	return output.Bytes()
+}
@@ -0,0 +1,117 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+
+	"golang.org/x/image/bmp"
+)
+
+// magickVipsType is the bimg type name of the libvips ImageMagick loader.
+const magickVipsType = "magick"
+
+// magickSourceFormat is the deprecated catch-all source for ImageMagick
+// input.
+const magickSourceFormat = "magick"
+
+// The probes below build the smallest file each ImageMagick coder accepts:
+// a single black pixel. ImageMagick policies often disable coders such as
+// PSD or XCF, and libvips can only route inputs to coders that sniff their
+// own signature, so loading a real file is the only reliable check.
+
+func bmpProbe() []byte {
+	var output bytes.Buffer
+	if err := bmp.Encode(&output, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		return nil
+	}
+
+	return output.Bytes()
+}
+
+// icoProbe wraps a 32-bit DIB, whose header stores twice the height to
+// cover the AND mask that follows the pixels.
+func icoProbe() []byte {
+	var dib bytes.Buffer
+	writeLittleEndian(&dib, uint32(40), int32(1), int32(2), uint16(1), uint16(32), uint32(0), uint32(8), int32(0), int32(0), uint32(0), uint32(0))
+	dib.Write(make([]byte, 4+4))
+
+	var output bytes.Buffer
+	writeLittleEndian(&output, uint16(0), uint16(1), uint16(1))
+	writeLittleEndian(&output, uint8(1), uint8(1), uint8(0), uint8(0), uint16(1), uint16(32), uint32(dib.Len()), uint32(6+16))
+	output.Write(dib.Bytes())
+
+	return output.Bytes()
+}
+
+func psdProbe() []byte {
+	var output bytes.Buffer
+	output.WriteString("8BPS")
+	writeBigEndian(&output, uint16(1), [6]byte{}, uint16(3), uint32(1), uint32(1), uint16(8), uint16(3))
+	// Empty color mode data, image resources and layer info, then one raw
+	// RGB pixel.
+	writeBigEndian(&output, uint32(0), uint32(0), uint32(0), uint16(0))
+	output.Write(make([]byte, 3))
+
+	return output.Bytes()
+}
+
+// tgaProbe is an uncompressed true-color image with a TGA 2.0 footer.
+func tgaProbe() []byte {
+	var output bytes.Buffer
+	writeLittleEndian(&output, uint8(0), uint8(0), uint8(2), [5]byte{}, uint16(0), uint16(0), uint16(1), uint16(1), uint8(24), uint8(0))
+	output.Write(make([]byte, 3))
+	writeLittleEndian(&output, uint32(0), uint32(0))
+	output.WriteString("TRUEVISION-XFILE.\x00")
+
+	return output.Bytes()
+}
+
+// pcxProbe stores three 8-bit planes; scanlines are padded to an even
+// number of bytes.
+func pcxProbe() []byte {
+	var output bytes.Buffer
+	writeLittleEndian(&output, uint8(0x0a), uint8(5), uint8(1), uint8(8), uint16(0), uint16(0), uint16(0), uint16(0), uint16(72), uint16(72))
+	output.Write(make([]byte, 48))
+	writeLittleEndian(&output, uint8(0), uint8(3), uint16(2), uint16(1), uint16(0), uint16(0))
+	output.Write(make([]byte, 128-output.Len()))
+	output.Write(make([]byte, 3*2))
+
+	return output.Bytes()
+}
+
+// xcfProbe is an RGB image with one uncompressed RGBA layer. Offsets are
+// absolute: the header and its pointer lists take 46 bytes, the layer 32,
+// the hierarchy 20 and the level 16.
+func xcfProbe() []byte {
+	const (
+		layerOffset     = 46
+		hierarchyOffset = layerOffset + 32
+		levelOffset     = hierarchyOffset + 20
+		tileOffset      = levelOffset + 16
+	)
+
+	var output bytes.Buffer
+	output.WriteString("gimp xcf file\x00")
+	writeBigEndian(&output, uint32(1), uint32(1), uint32(0))
+	writeBigEndian(&output, uint32(0), uint32(0))
+	writeBigEndian(&output, uint32(layerOffset), uint32(0), uint32(0))
+	writeBigEndian(&output, uint32(1), uint32(1), uint32(1), uint32(2), [2]byte{'l'}, uint32(0), uint32(0), uint32(hierarchyOffset), uint32(0))
+	writeBigEndian(&output, uint32(1), uint32(1), uint32(4), uint32(levelOffset), uint32(0))
+	writeBigEndian(&output, uint32(1), uint32(1), uint32(tileOffset), uint32(0))
+	writeBigEndian(&output, [4]byte{0, 0, 0, 0xff})
+
+	return output.Bytes()
+}
+
+func writeLittleEndian(buf *bytes.Buffer, values ...any) {
+	for _, value := range values {
+		_ = binary.Write(buf, binary.LittleEndian, value)
+	}
+}
+
+func writeBigEndian(buf *bytes.Buffer, values ...any) {
+	for _, value := range values {
+		_ = binary.Write(buf, binary.BigEndian, value)
+	}
+}
@@ -0,0 +1,16 @@
+package converter
+
+import "testing"
+
+func TestLoadProbesAreDetectedAsTheirFormat(t *testing.T) {
+	for _, format := range formats {
+		if format.loadProbe == nil {
+			continue
+		}
+
+		detection, ok := DetectFormat(format.loadProbe())
+		if !ok || detection.Format != format.Name {
+			t.Fatalf("expected %s probe to be detected as %s, got %+v", format.Name, format.Name, detection)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package converter
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	MetadataKeep         = "keep"
+	MetadataStrip        = "strip"
+	MetadataStripPrivate = "strip-private"
+	MetadataKeepICC      = "keep-icc"
+)
+
+var metadataPolicies = []string{MetadataKeep, MetadataStrip, MetadataStripPrivate, MetadataKeepICC}
+
+// Metadata blocks reported as removed. The exif-gps and exif-serials blocks
+// are only reported by strip-private, which keeps the rest of EXIF.
+const (
+	MetadataBlockEXIF        = "exif"
+	MetadataBlockEXIFGPS     = "exif-gps"
+	MetadataBlockEXIFSerials = "exif-serials"
+	MetadataBlockXMP         = "xmp"
+	MetadataBlockIPTC        = "iptc"
+	MetadataBlockICC         = "icc"
+)
+
+// libvips exposes each parsed EXIF tag as an "exif-ifdN-Name" field and
+// rebuilds the EXIF block from those fields when saving, so removing a field
+// drops the tag from the output. IFD 3 is the GPS directory.
+const exifGPSFieldPrefix = "exif-ifd3-"
+
+const iccProfileField = "icc-profile-data"
+
+var exifSerialFields = map[string]struct{}{
+	"exif-ifd0-HostComputer":     {},
+	"exif-ifd2-BodySerialNumber": {},
+	"exif-ifd2-CameraOwnerName":  {},
+	"exif-ifd2-ImageUniqueID":    {},
+	"exif-ifd2-LensSerialNumber": {},
+	"exif-ifd2-MakerNote":        {},
+}
+
+func validateMetadataPolicy(policy string) error {
+	if policy == "" || slices.Contains(metadataPolicies, policy) {
+		return nil
+	}
+
+	return invalidOptionsError("metadata must be one of %s", strings.Join(metadataPolicies, ", "))
+}
+
+func metadataBlock(field string) string {
+	switch {
+	case field == iccProfileField:
+		return MetadataBlockICC
+	case field == "xmp-data":
+		return MetadataBlockXMP
+	case field == "iptc-data":
+		return MetadataBlockIPTC
+	case strings.HasPrefix(field, exifGPSFieldPrefix):
+		return MetadataBlockEXIFGPS
+	case isEXIFSerialField(field):
+		return MetadataBlockEXIFSerials
+	case field == "exif-data" || field == vipsOrientationField || strings.HasPrefix(field, "exif-"):
+		return MetadataBlockEXIF
+	default:
+		return ""
+	}
+}
+
+func isEXIFSerialField(field string) bool {
+	_, ok := exifSerialFields[field]
+	return ok
+}
+
+func metadataPolicyRemoves(policy string, block string) bool {
+	switch policy {
+	case MetadataStrip:
+		return true
+	case MetadataKeepICC:
+		return block != MetadataBlockICC
+	case MetadataStripPrivate:
+		return block != MetadataBlockEXIF && block != MetadataBlockICC
+	default:
+		return false
+	}
+}
+
+// metadataFieldsToRemove returns the image fields a policy drops and the
+// sorted list of blocks they belong to.
+func metadataFieldsToRemove(policy string, fields []string) ([]string, []string) {
+	names := []string{}
+	removedBlocks := map[string]struct{}{}
+	for _, field := range fields {
+		block := metadataBlock(field)
+		if block == "" || !metadataPolicyRemoves(policy, block) {
+			continue
+		}
+		if policy != MetadataStripPrivate && strings.HasPrefix(block, MetadataBlockEXIF) {
+			block = MetadataBlockEXIF
+		}
+
+		names = append(names, field)
+		removedBlocks[block] = struct{}{}
+	}
+
+	blocks := make([]string, 0, len(removedBlocks))
+	for block := range removedBlocks {
+		blocks = append(blocks, block)
+	}
+	sort.Strings(blocks)
+
+	return names, blocks
+}
+
+func applyMetadataPolicy(image *vipsImage, policy string) ([]string, error) {
+	if policy == "" || policy == MetadataKeep {
+		return nil, nil
+	}
+
+	names, blocks := metadataFieldsToRemove(policy, image.fields())
+	if err := image.removeFields(names); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+func mergeMetadataBlocks(current []string, blocks []string) []string {
+	for _, block := range blocks {
+		if !slices.Contains(current, block) {
+			current = append(current, block)
+		}
+	}
+	sort.Strings(current)
+
+	return current
+}
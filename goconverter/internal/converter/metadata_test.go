@@ -0,0 +1,106 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var sampleMetadataFields = []string{
+	"width",
+	"orientation",
+	"exif-data",
+	"exif-ifd0-Make",
+	"exif-ifd2-BodySerialNumber",
+	"exif-ifd3-GPSLatitude",
+	"xmp-data",
+	"iptc-data",
+	"icc-profile-data",
+}
+
+func TestMetadataFieldsToRemove(t *testing.T) {
+	cases := []struct {
+		policy         string
+		expectedFields []string
+		expectedBlocks []string
+	}{
+		{
+			policy:         MetadataKeep,
+			expectedFields: []string{},
+			expectedBlocks: []string{},
+		},
+		{
+			policy:         MetadataStrip,
+			expectedFields: []string{"orientation", "exif-data", "exif-ifd0-Make", "exif-ifd2-BodySerialNumber", "exif-ifd3-GPSLatitude", "xmp-data", "iptc-data", "icc-profile-data"},
+			expectedBlocks: []string{MetadataBlockEXIF, MetadataBlockICC, MetadataBlockIPTC, MetadataBlockXMP},
+		},
+		{
+			policy:         MetadataKeepICC,
+			expectedFields: []string{"orientation", "exif-data", "exif-ifd0-Make", "exif-ifd2-BodySerialNumber", "exif-ifd3-GPSLatitude", "xmp-data", "iptc-data"},
+			expectedBlocks: []string{MetadataBlockEXIF, MetadataBlockIPTC, MetadataBlockXMP},
+		},
+		{
+			policy:         MetadataStripPrivate,
+			expectedFields: []string{"exif-ifd2-BodySerialNumber", "exif-ifd3-GPSLatitude", "xmp-data", "iptc-data"},
+			expectedBlocks: []string{MetadataBlockEXIFGPS, MetadataBlockEXIFSerials, MetadataBlockIPTC, MetadataBlockXMP},
+		},
+	}
+
+	for _, tc := range cases {
+		fields, blocks := metadataFieldsToRemove(tc.policy, sampleMetadataFields)
+		if !reflect.DeepEqual(fields, tc.expectedFields) {
+			t.Fatalf("policy %s: expected fields %v, got %v", tc.policy, tc.expectedFields, fields)
+		}
+		if !reflect.DeepEqual(blocks, tc.expectedBlocks) {
+			t.Fatalf("policy %s: expected blocks %v, got %v", tc.policy, tc.expectedBlocks, blocks)
+		}
+	}
+}
+
+func TestValidateOptionsRejectsUnknownMetadataPolicy(t *testing.T) {
+	err := ValidateOptions("jpeg", "png", Options{Metadata: "private"})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected unknown metadata policy to be rejected, got: %v", err)
+	}
+}
+
+func TestConvertWithOptionsStripsMetadata(t *testing.T) {
+	requireFormatPairSupport(t, "jpeg", "webp")
+
+	input := mustReadBIMGTestdataFile(t, "test_exif_full.jpg")
+	result, err := newPairConverter("jpeg", "webp").ConvertWithOptions(context.Background(), input, Options{Metadata: MetadataStrip})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if !slices.Contains(result.MetadataRemoved, MetadataBlockEXIF) {
+		t.Fatalf("expected exif to be reported as removed, got %v", result.MetadataRemoved)
+	}
+
+	for _, field := range mustReadFields(t, result.Output) {
+		if strings.HasPrefix(field, "exif-") {
+			t.Fatalf("expected no EXIF in output, found field %q", field)
+		}
+	}
+}
+
+func TestConvertWithOptionsStripsPrivateEXIF(t *testing.T) {
+	requireFormatPairSupport(t, "jpeg", "webp")
+
+	input := mustReadBIMGTestdataFile(t, "test_exif_full.jpg")
+	result, err := newPairConverter("jpeg", "webp").ConvertWithOptions(context.Background(), input, Options{Metadata: MetadataStripPrivate})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if slices.Contains(result.MetadataRemoved, MetadataBlockEXIF) {
+		t.Fatalf("expected the EXIF block to be kept, got %v", result.MetadataRemoved)
+	}
+
+	for _, field := range mustReadFields(t, result.Output) {
+		if strings.HasPrefix(field, exifGPSFieldPrefix) || isEXIFSerialField(field) {
+			t.Fatalf("expected private EXIF fields to be removed, found %q", field)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	ChromaSubsamplingAuto = "auto"
+	ChromaSubsampling420  = "4:2:0"
+	ChromaSubsampling444  = "4:4:4"
+)
+
+var chromaSubsamplingModes = []string{ChromaSubsamplingAuto, ChromaSubsampling420, ChromaSubsampling444}
+
+// PageSizeAuto sizes each PDF page to its image at the requested DPI; the
+// named sizes center the image on a page turned to match its orientation.
+const (
+	PageSizeAuto   = "auto"
+	PageSizeA3     = "a3"
+	PageSizeA4     = "a4"
+	PageSizeA5     = "a5"
+	PageSizeLetter = "letter"
+	PageSizeLegal  = "legal"
+)
+
+var pageSizes = []string{PageSizeAuto, PageSizeA3, PageSizeA4, PageSizeA5, PageSizeLetter, PageSizeLegal}
+
+var ErrInvalidOptions = errors.New("invalid conversion options")
+
+// Options tunes a single conversion. Encoder fields apply to the target
+// format and keep the libvips defaults when unset; Transform reshapes the
+// image before it is encoded. Pages selects which pages of a multi-page
+// source are converted; Frame extracts one frame of an animation as a still.
+// Metadata picks which EXIF, XMP, IPTC and ICC blocks survive the conversion;
+// ColorSpace and ICCProfile control the color transform before encoding.
+// DisableAutoRotate keeps pixels as stored and the EXIF orientation as is.
+// DPI and PageSize lay out PDF output; Rasterize renders SVG and PDF sources.
+type Options struct {
+	Quality           *int
+	Lossless          bool
+	Effort            *int
+	Progressive       bool
+	ChromaSubsampling string
+	CompressionLevel  *int
+	DPI               *int
+	PageSize          string
+	Transform         Transform
+	Rasterize         Rasterization
+	Pages             string
+	Frame             *int
+	Metadata          string
+	ColorSpace        string
+	ICCProfile        string
+	DisableAutoRotate bool
+}
+
+type intRange struct {
+	min int
+	max int
+}
+
+// encoderCapabilities lists the encoder options a target accepts. The
+// defaults are the libvips ones used when an option is unset.
+type encoderCapabilities struct {
+	quality           bool
+	defaultQuality    int
+	lossless          bool
+	effort            *intRange
+	defaultEffort     int
+	progressive       bool
+	chromaSubsampling bool
+	compressionLevel  bool
+	pageLayout        bool
+}
+
+var qualityRange = intRange{min: 1, max: 100}
+var compressionLevelRange = intRange{min: 0, max: 9}
+var dpiRange = intRange{min: 1, max: 2400}
+
+const (
+	defaultCompressionLevel = 6
+	defaultDPI              = 72
+)
+
+func (o Options) IsZero() bool {
+	return o.encoderOptionsAreZero() && o.Transform.IsZero() && o.Rasterize.IsZero() && o.Pages == "" && o.Frame == nil && o.Metadata == "" && o.ColorSpace == "" && o.ICCProfile == "" && !o.DisableAutoRotate
+}
+
+func (o Options) encoderOptionsAreZero() bool {
+	return o.Quality == nil &&
+		!o.Lossless &&
+		o.Effort == nil &&
+		!o.Progressive &&
+		o.ChromaSubsampling == "" &&
+		o.CompressionLevel == nil &&
+		o.DPI == nil &&
+		o.PageSize == ""
+}
+
+func (o Options) withoutEncoderOptions() Options {
+	output := o
+	output.Quality = nil
+	output.Lossless = false
+	output.Effort = nil
+	output.Progressive = false
+	output.ChromaSubsampling = ""
+	output.CompressionLevel = nil
+	output.DPI = nil
+	output.PageSize = ""
+	return output
+}
+
+func (o Options) encoderOptions() Options {
+	return Options{
+		Quality:           o.Quality,
+		Lossless:          o.Lossless,
+		Effort:            o.Effort,
+		Progressive:       o.Progressive,
+		ChromaSubsampling: o.ChromaSubsampling,
+		CompressionLevel:  o.CompressionLevel,
+		DPI:               o.DPI,
+		PageSize:          o.PageSize,
+	}
+}
+
+func ValidateOptions(sourceFormat string, targetFormat string, options Options) error {
+	if err := validateTransform(options.Transform); err != nil {
+		return err
+	}
+	if err := validateRasterization(sourceFormat, options.Rasterize); err != nil {
+		return err
+	}
+	if err := validatePageSelector(sourceFormat, options.Pages); err != nil {
+		return err
+	}
+	if err := validateFrame(sourceFormat, options.Frame); err != nil {
+		return err
+	}
+	if err := validateMetadataPolicy(options.Metadata); err != nil {
+		return err
+	}
+	if err := validateColorOptions(options.ColorSpace, options.ICCProfile); err != nil {
+		return err
+	}
+
+	format, ok := LookupFormat(targetFormat)
+	if !ok || (!format.CanSave() && format.encoder == (encoderCapabilities{})) {
+		if options.encoderOptionsAreZero() {
+			return nil
+		}
+		return invalidOptionsError("%s output does not accept encoder options", targetFormat)
+	}
+
+	return validateEncoderOptions(targetFormat, format.encoder, options)
+}
+
+func validateEncoderOptions(targetFormat string, capabilities encoderCapabilities, options Options) error {
+	if options.Quality != nil {
+		if !capabilities.quality {
+			return unsupportedOptionError("quality", targetFormat)
+		}
+		if err := validateRange("quality", *options.Quality, qualityRange); err != nil {
+			return err
+		}
+	}
+
+	if options.Lossless {
+		if !capabilities.lossless {
+			return unsupportedOptionError("lossless", targetFormat)
+		}
+		if options.Quality != nil {
+			return invalidOptionsError("quality cannot be combined with lossless")
+		}
+	}
+
+	if options.Effort != nil {
+		if capabilities.effort == nil {
+			return unsupportedOptionError("effort", targetFormat)
+		}
+		if err := validateRange("effort", *options.Effort, *capabilities.effort); err != nil {
+			return err
+		}
+	}
+
+	if options.Progressive && !capabilities.progressive {
+		return unsupportedOptionError("progressive", targetFormat)
+	}
+
+	if options.ChromaSubsampling != "" {
+		if !capabilities.chromaSubsampling {
+			return unsupportedOptionError("chromaSubsampling", targetFormat)
+		}
+		if !slices.Contains(chromaSubsamplingModes, options.ChromaSubsampling) {
+			return invalidOptionsError("chromaSubsampling must be one of %s", strings.Join(chromaSubsamplingModes, ", "))
+		}
+		if options.Lossless {
+			return invalidOptionsError("chromaSubsampling cannot be combined with lossless")
+		}
+	}
+
+	if options.CompressionLevel != nil {
+		if !capabilities.compressionLevel {
+			return unsupportedOptionError("compressionLevel", targetFormat)
+		}
+		if err := validateRange("compressionLevel", *options.CompressionLevel, compressionLevelRange); err != nil {
+			return err
+		}
+	}
+
+	if options.DPI != nil {
+		if !capabilities.pageLayout {
+			return unsupportedOptionError("dpi", targetFormat)
+		}
+		if err := validateRange("dpi", *options.DPI, dpiRange); err != nil {
+			return err
+		}
+	}
+
+	if options.PageSize != "" {
+		if !capabilities.pageLayout {
+			return unsupportedOptionError("pageSize", targetFormat)
+		}
+		if !slices.Contains(pageSizes, options.PageSize) {
+			return invalidOptionsError("pageSize must be one of %s", strings.Join(pageSizes, ", "))
+		}
+	}
+
+	return nil
+}
+
+// saveOptionString renders the libvips save suffix for the target format,
+// e.g. ".jpg[Q=80,interlace=true]".
+func saveOptionString(targetFormat string, options Options) (string, error) {
+	format, ok := LookupFormat(targetFormat)
+	if !ok || !format.CanSave() {
+		return "", fmt.Errorf("no libvips saver for %s", targetFormat)
+	}
+
+	suffix := format.saveSuffix
+	parameters := append(make([]string, 0, 6), format.saveParameters...)
+	if options.Quality != nil {
+		parameters = append(parameters, "Q="+strconv.Itoa(*options.Quality))
+	}
+	if options.Lossless {
+		parameters = append(parameters, "lossless=true")
+	}
+	if options.Effort != nil {
+		parameters = append(parameters, "effort="+strconv.Itoa(*options.Effort))
+	}
+	if options.Progressive {
+		parameters = append(parameters, "interlace=true")
+	}
+	switch options.ChromaSubsampling {
+	case ChromaSubsamplingAuto:
+		parameters = append(parameters, "subsample_mode=auto")
+	case ChromaSubsampling420:
+		parameters = append(parameters, "subsample_mode=on")
+	case ChromaSubsampling444:
+		parameters = append(parameters, "subsample_mode=off")
+	}
+	if options.CompressionLevel != nil {
+		parameters = append(parameters, "compression="+strconv.Itoa(*options.CompressionLevel))
+	}
+
+	if len(parameters) == 0 {
+		return suffix, nil
+	}
+
+	return suffix + "[" + strings.Join(parameters, ",") + "]", nil
+}
+
+func validateRange(name string, value int, allowed intRange) error {
+	if value < allowed.min || value > allowed.max {
+		return invalidOptionsError("%s must be between %d and %d", name, allowed.min, allowed.max)
+	}
+
+	return nil
+}
+
+func unsupportedOptionError(name string, targetFormat string) error {
+	return invalidOptionsError("%s is not supported for %s output", name, targetFormat)
+}
+
+func invalidOptionsError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidOptions, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,143 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateOptionsAcceptsSupportedOptions(t *testing.T) {
+	cases := []struct {
+		target  string
+		options Options
+	}{
+		{target: "jpeg", options: Options{Quality: intPointer(85), Progressive: true, ChromaSubsampling: ChromaSubsampling444}},
+		{target: "webp", options: Options{Lossless: true, Effort: intPointer(6)}},
+		{target: "avif", options: Options{Quality: intPointer(50), Effort: intPointer(0), ChromaSubsampling: ChromaSubsampling420}},
+		{target: "heif", options: Options{Quality: intPointer(1)}},
+		{target: "png", options: Options{CompressionLevel: intPointer(0), Progressive: true}},
+		{target: "gif", options: Options{}},
+		{target: "tiff", options: Options{}},
+		{target: "jxl", options: Options{Lossless: true, Effort: intPointer(9)}},
+		{target: "jp2k", options: Options{Quality: intPointer(40), ChromaSubsampling: ChromaSubsampling444}},
+		{target: "pdf", options: Options{DPI: intPointer(300), PageSize: PageSizeA4}},
+	}
+
+	for _, tc := range cases {
+		if err := ValidateOptions("png", tc.target, tc.options); err != nil {
+			t.Fatalf("expected options to be valid for %s, got error: %v", tc.target, err)
+		}
+	}
+}
+
+func TestValidateOptionsRejectsUnsupportedOrOutOfRangeOptions(t *testing.T) {
+	cases := []struct {
+		target   string
+		options  Options
+		expected string
+	}{
+		{target: "jpeg", options: Options{Quality: intPointer(0)}, expected: "quality must be between 1 and 100"},
+		{target: "jpeg", options: Options{Quality: intPointer(101)}, expected: "quality must be between 1 and 100"},
+		{target: "jpeg", options: Options{Lossless: true}, expected: "lossless is not supported for jpeg output"},
+		{target: "png", options: Options{Quality: intPointer(80)}, expected: "quality is not supported for png output"},
+		{target: "png", options: Options{CompressionLevel: intPointer(10)}, expected: "compressionLevel must be between 0 and 9"},
+		{target: "webp", options: Options{Effort: intPointer(7)}, expected: "effort must be between 0 and 6"},
+		{target: "webp", options: Options{Progressive: true}, expected: "progressive is not supported for webp output"},
+		{target: "webp", options: Options{Lossless: true, Quality: intPointer(90)}, expected: "quality cannot be combined with lossless"},
+		{target: "jpeg", options: Options{ChromaSubsampling: "4:1:1"}, expected: "chromaSubsampling must be one of"},
+		{target: "gif", options: Options{Effort: intPointer(1)}, expected: "effort is not supported for gif output"},
+		{target: "svg", options: Options{Quality: intPointer(80)}, expected: "svg output does not accept encoder options"},
+		{target: "pdf", options: Options{Quality: intPointer(80)}, expected: "quality is not supported for pdf output"},
+		{target: "png", options: Options{DPI: intPointer(300)}, expected: "dpi is not supported for png output"},
+		{target: "pdf", options: Options{DPI: intPointer(0)}, expected: "dpi must be between 1 and 2400"},
+		{target: "pdf", options: Options{PageSize: "tabloid"}, expected: "pageSize must be one of auto, a3, a4, a5, letter, legal"},
+	}
+
+	for _, tc := range cases {
+		err := ValidateOptions("png", tc.target, tc.options)
+		if err == nil {
+			t.Fatalf("expected options %+v to be rejected for %s", tc.options, tc.target)
+		}
+		if !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("expected ErrInvalidOptions, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), tc.expected) {
+			t.Fatalf("expected error to contain %q, got: %v", tc.expected, err)
+		}
+	}
+}
+
+func TestSaveOptionString(t *testing.T) {
+	cases := []struct {
+		target   string
+		options  Options
+		expected string
+	}{
+		{target: "jpeg", options: Options{}, expected: ".jpg"},
+		{target: "jpeg", options: Options{Quality: intPointer(82), Progressive: true, ChromaSubsampling: ChromaSubsampling444}, expected: ".jpg[Q=82,interlace=true,subsample_mode=off]"},
+		{target: "avif", options: Options{}, expected: ".avif[compression=av1]"},
+		{target: "avif", options: Options{Lossless: true, Effort: intPointer(4)}, expected: ".avif[compression=av1,lossless=true,effort=4]"},
+		{target: "png", options: Options{CompressionLevel: intPointer(9)}, expected: ".png[compression=9]"},
+		{target: "heif", options: Options{ChromaSubsampling: ChromaSubsampling420}, expected: ".heic[subsample_mode=on]"},
+		{target: "jxl", options: Options{Quality: intPointer(90), Effort: intPointer(3)}, expected: ".jxl[Q=90,effort=3]"},
+		{target: "jp2k", options: Options{Lossless: true}, expected: ".jp2[lossless=true]"},
+	}
+
+	for _, tc := range cases {
+		got, err := saveOptionString(tc.target, tc.options)
+		if err != nil {
+			t.Fatalf("expected save options for %s, got error: %v", tc.target, err)
+		}
+		if got != tc.expected {
+			t.Fatalf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestConvertWithOptionsRejectsInvalidOptions(t *testing.T) {
+	c := newPairConverter("png", "jpeg")
+
+	_, err := c.ConvertWithOptions(context.Background(), mustEncodePNG(t), Options{Lossless: true})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected ErrInvalidOptions, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "convert png to jpeg") {
+		t.Fatalf("expected wrapped conversion error, got: %v", err)
+	}
+}
+
+func TestConvertWithOptionsQualityChangesOutputSize(t *testing.T) {
+	requireFormatPairSupport(t, "png", "jpeg")
+
+	c := newPairConverter("png", "jpeg")
+	input := mustEncodeGradientPNG(t, 64, 64)
+
+	low, err := c.ConvertWithOptions(context.Background(), input, Options{Quality: intPointer(5)})
+	if err != nil {
+		t.Fatalf("expected low quality conversion to succeed, got error: %v", err)
+	}
+	high, err := c.ConvertWithOptions(context.Background(), input, Options{Quality: intPointer(100), ChromaSubsampling: ChromaSubsampling444})
+	if err != nil {
+		t.Fatalf("expected high quality conversion to succeed, got error: %v", err)
+	}
+
+	assertOutputFormat(t, low.Output, "jpeg")
+	assertOutputFormat(t, high.Output, "jpeg")
+	if len(low.Output) >= len(high.Output) {
+		t.Fatalf("expected quality 5 output (%d bytes) to be smaller than quality 100 output (%d bytes)", len(low.Output), len(high.Output))
+	}
+}
+
+func TestConvertWithOptionsLosslessWEBP(t *testing.T) {
+	requireFormatPairSupport(t, "png", "webp")
+
+	c := newPairConverter("png", "webp")
+
+	result, err := c.ConvertWithOptions(context.Background(), mustEncodePNG(t), Options{Lossless: true, Effort: intPointer(6)})
+	if err != nil {
+		t.Fatalf("expected lossless conversion to succeed, got error: %v", err)
+	}
+
+	assertOutputFormat(t, result.Output, "webp")
+}
@@ -0,0 +1,122 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+)
+
+const PagesAll = "all"
+
+// PageResult is the converted output of one selected page. Page numbers are
+// 1-based, matching the selector syntax.
+type PageResult struct {
+	Page     int
+	Output   []byte
+	Geometry Geometry
+	// Backends names the backend that produced the page at each hop, in
+	// route order.
+	Backends []string
+}
+
+type pageRange struct {
+	first int
+	// last is 0 for open-ended ranges such as "3-".
+	last int
+}
+
+func SupportsPageSelection(sourceFormat string) bool {
+	format, ok := LookupFormat(sourceFormat)
+	return ok && format.MultiPage
+}
+
+func validatePageSelector(sourceFormat string, selector string) error {
+	if selector == "" {
+		return nil
+	}
+	if !SupportsPageSelection(sourceFormat) {
+		return invalidOptionsError("pages is not supported for %s input", sourceFormat)
+	}
+
+	_, err := parsePageSelector(selector)
+	return err
+}
+
+// parsePageSelector accepts "all" or a comma separated list of pages and
+// ranges, e.g. "1,3-5,8-".
+func parsePageSelector(selector string) ([]pageRange, error) {
+	selector = strings.ToLower(strings.TrimSpace(selector))
+	if selector == PagesAll {
+		return []pageRange{{first: 1}}, nil
+	}
+
+	parts := strings.Split(selector, ",")
+	ranges := make([]pageRange, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		firstText, lastText, isRange := strings.Cut(part, "-")
+
+		first, err := parsePageNumber(firstText)
+		if err != nil {
+			return nil, err
+		}
+		if !isRange {
+			ranges = append(ranges, pageRange{first: first, last: first})
+			continue
+		}
+		if strings.TrimSpace(lastText) == "" {
+			ranges = append(ranges, pageRange{first: first})
+			continue
+		}
+
+		last, err := parsePageNumber(lastText)
+		if err != nil {
+			return nil, err
+		}
+		if last < first {
+			return nil, invalidOptionsError("page range %q is descending", part)
+		}
+		ranges = append(ranges, pageRange{first: first, last: last})
+	}
+
+	return ranges, nil
+}
+
+func parsePageNumber(value string) (int, error) {
+	page, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || page < 1 {
+		return 0, invalidOptionsError("pages must be \"all\" or a list of page numbers and ranges starting at 1, e.g. \"1,3-5\"")
+	}
+
+	return page, nil
+}
+
+// resolvePages expands the selector against the document page count,
+// dropping duplicates while keeping the requested order.
+func resolvePages(selector string, pageCount int) ([]int, error) {
+	ranges, err := parsePageSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]struct{}, pageCount)
+	pages := make([]int, 0, pageCount)
+	for _, r := range ranges {
+		last := r.last
+		if last == 0 {
+			last = pageCount
+		}
+		if r.first > pageCount || last > pageCount {
+			return nil, invalidOptionsError("pages selects page %d but the document has %d page(s)", max(r.first, last), pageCount)
+		}
+
+		for page := r.first; page <= last; page++ {
+			if _, ok := seen[page]; ok {
+				continue
+			}
+			seen[page] = struct{}{}
+			pages = append(pages, page)
+		}
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolvePages(t *testing.T) {
+	cases := []struct {
+		selector string
+		count    int
+		expected []int
+	}{
+		{selector: "all", count: 3, expected: []int{1, 2, 3}},
+		{selector: "ALL", count: 1, expected: []int{1}},
+		{selector: "2", count: 3, expected: []int{2}},
+		{selector: "1,3-4", count: 5, expected: []int{1, 3, 4}},
+		{selector: "4-", count: 5, expected: []int{4, 5}},
+		{selector: "3, 1-3", count: 3, expected: []int{3, 1, 2}},
+	}
+
+	for _, tc := range cases {
+		pages, err := resolvePages(tc.selector, tc.count)
+		if err != nil {
+			t.Fatalf("expected selector %q to resolve, got error: %v", tc.selector, err)
+		}
+		if !reflect.DeepEqual(pages, tc.expected) {
+			t.Fatalf("selector %q: expected pages %v, got %v", tc.selector, tc.expected, pages)
+		}
+	}
+}
+
+func TestResolvePagesRejectsInvalidSelectors(t *testing.T) {
+	cases := []struct {
+		selector string
+		count    int
+		expected string
+	}{
+		{selector: "0", count: 3, expected: "starting at 1"},
+		{selector: "a-b", count: 3, expected: "starting at 1"},
+		{selector: "3-1", count: 3, expected: "descending"},
+		{selector: "1,", count: 3, expected: "starting at 1"},
+		{selector: "4", count: 3, expected: "document has 3 page(s)"},
+		{selector: "2-9", count: 3, expected: "selects page 9"},
+	}
+
+	for _, tc := range cases {
+		_, err := resolvePages(tc.selector, tc.count)
+		if !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("expected selector %q to be rejected, got: %v", tc.selector, err)
+		}
+		if !strings.Contains(err.Error(), tc.expected) {
+			t.Fatalf("selector %q: expected error to contain %q, got: %v", tc.selector, tc.expected, err)
+		}
+	}
+}
+
+func TestValidateOptionsRejectsPagesForSinglePageSources(t *testing.T) {
+	if err := ValidateOptions("pdf", "png", Options{Pages: "1-2"}); err != nil {
+		t.Fatalf("expected pages to be accepted for pdf input, got error: %v", err)
+	}
+
+	err := ValidateOptions("png", "jpeg", Options{Pages: "1"})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected pages to be rejected for png input, got: %v", err)
+	}
+}
+
+func TestConvertWithOptionsSelectsTIFFPages(t *testing.T) {
+	requireFormatPairSupport(t, "tiff", "png")
+
+	c := newPairConverter("tiff", "png")
+	input := mustEncodeMultiPageTIFF(t, 3)
+
+	result, err := c.ConvertWithOptions(context.Background(), input, Options{Pages: PagesAll})
+	if err != nil {
+		t.Fatalf("expected all-pages conversion to succeed, got error: %v", err)
+	}
+	if len(result.Pages) != 3 {
+		t.Fatalf("expected 3 page results, got %d", len(result.Pages))
+	}
+	for index, page := range result.Pages {
+		if page.Page != index+1 {
+			t.Fatalf("expected page %d at index %d, got %d", index+1, index, page.Page)
+		}
+		assertOutputFormat(t, page.Output, "png")
+	}
+
+	result, err = c.ConvertWithOptions(context.Background(), input, Options{Pages: "2"})
+	if err != nil {
+		t.Fatalf("expected single page conversion to succeed, got error: %v", err)
+	}
+	if len(result.Pages) != 0 {
+		t.Fatalf("expected single page selection to return one output, got %d pages", len(result.Pages))
+	}
+	assertOutputFormat(t, result.Output, "png")
+
+	_, err = c.ConvertWithOptions(context.Background(), input, Options{Pages: "4"})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected out of range page to be rejected, got: %v", err)
+	}
+}
@@ -0,0 +1,331 @@
+package converter
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// pdfEncoder is what the PDF writer accepts; it never resamples or
+// recompresses pixels, so there are no quality settings.
+var pdfEncoder = encoderCapabilities{pageLayout: true}
+
+const pointsPerInch = 72
+
+// pageDimensions are portrait page sizes in points.
+var pageDimensions = map[string][2]float64{
+	PageSizeA3:     {841.89, 1190.55},
+	PageSizeA4:     {595.28, 841.89},
+	PageSizeA5:     {419.53, 595.28},
+	PageSizeLetter: {612, 792},
+	PageSizeLegal:  {612, 1008},
+}
+
+func encodeGoPDF(w io.Writer, img image.Image, options Options) error {
+	return writePDF(w, []pdfImage{{pixels: img}}, newPageLayout(options.PageSize, options.DPI), nil)
+}
+
+// pdfImage is the image of one page: decoded pixels, or a JPEG file that is
+// embedded as is. JPEG images carry their size and color space.
+type pdfImage struct {
+	pixels     image.Image
+	jpeg       []byte
+	size       image.Point
+	colorSpace string
+}
+
+func (i pdfImage) bounds() image.Point {
+	if i.jpeg != nil {
+		return i.size
+	}
+
+	return i.pixels.Bounds().Size()
+}
+
+func (i pdfImage) opaque() bool {
+	return i.jpeg != nil || isOpaque(i.pixels)
+}
+
+// pageLayout places images on document pages. Margins are in points; fit is
+// PageFitShrink or one of FitContain, FitCover and FitFill.
+type pageLayout struct {
+	pageSize    string
+	orientation string
+	margin      float64
+	fit         string
+	dpi         int
+}
+
+// pagePlacement is where an image goes on its page, in points from the
+// bottom left corner. Images covering the printable area are clipped to it.
+type pagePlacement struct {
+	pageWidth  float64
+	pageHeight float64
+	area       [4]float64
+	image      [4]float64
+	clip       bool
+}
+
+func newPageLayout(pageSize string, dpi *int) pageLayout {
+	layout := pageLayout{pageSize: pageSize, orientation: OrientationAuto, fit: PageFitShrink, dpi: defaultDPI}
+	if layout.pageSize == "" {
+		layout.pageSize = PageSizeAuto
+	}
+	if dpi != nil {
+		layout.dpi = *dpi
+	}
+
+	return layout
+}
+
+// place sizes an image of the given pixel size at the layout DPI. Auto pages
+// wrap the image and its margins; named pages turn to match the image unless
+// an orientation is forced.
+func (l pageLayout) place(size image.Point) pagePlacement {
+	width := float64(size.X) * pointsPerInch / float64(l.dpi)
+	height := float64(size.Y) * pointsPerInch / float64(l.dpi)
+
+	dimensions, ok := pageDimensions[l.pageSize]
+	if !ok {
+		area := [4]float64{l.margin, l.margin, width, height}
+		return pagePlacement{pageWidth: width + 2*l.margin, pageHeight: height + 2*l.margin, area: area, image: area}
+	}
+
+	pageWidth, pageHeight := dimensions[0], dimensions[1]
+	landscape := l.orientation == OrientationLandscape || (l.orientation != OrientationPortrait && width > height)
+	if landscape {
+		pageWidth, pageHeight = pageHeight, pageWidth
+	}
+	areaWidth, areaHeight := pageWidth-2*l.margin, pageHeight-2*l.margin
+
+	switch scaleX, scaleY := areaWidth/width, areaHeight/height; l.fit {
+	case FitContain:
+		width, height = width*min(scaleX, scaleY), height*min(scaleX, scaleY)
+	case FitCover:
+		width, height = width*max(scaleX, scaleY), height*max(scaleX, scaleY)
+	case FitFill:
+		width, height = areaWidth, areaHeight
+	default:
+		if scale := min(scaleX, scaleY); scale < 1 {
+			width, height = width*scale, height*scale
+		}
+	}
+
+	return pagePlacement{
+		pageWidth:  pageWidth,
+		pageHeight: pageHeight,
+		area:       [4]float64{l.margin, l.margin, areaWidth, areaHeight},
+		image:      [4]float64{l.margin + (areaWidth-width)/2, l.margin + (areaHeight-height)/2, width, height},
+		clip:       l.fit == FitCover,
+	}
+}
+
+// writePDF embeds each image as one page. JPEG files are stored unchanged;
+// pixels are stored losslessly unless jpegQuality is set. Transparency is
+// kept as a soft mask.
+func writePDF(w io.Writer, images []pdfImage, layout pageLayout, jpegQuality *int) error {
+	var pdf pdfWriter
+	pdf.buffer.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// The catalog and page tree come first; page objects follow in groups
+	// of page, contents, image and an optional soft mask.
+	const catalog, pageTree, firstPage = 1, 2, 3
+	next := firstPage
+	kids := make([]string, 0, len(images))
+	for _, img := range images {
+		kids = append(kids, pdfReference(next))
+		next += 3
+		if !img.opaque() {
+			next++
+		}
+	}
+
+	pdf.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %s >>", pdfReference(pageTree)))
+	pdf.object(pageTree, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(images)))
+
+	number := firstPage
+	for _, img := range images {
+		page, contents, xobject := number, number+1, number+2
+		number += 3
+
+		size := img.bounds()
+		placement := layout.place(size)
+
+		pdf.object(page, fmt.Sprintf("<< /Type /Page /Parent %s /MediaBox [0 0 %s %s] /Resources << /XObject << /Im0 %s >> >> /Contents %s >>",
+			pdfReference(pageTree), pdfNumber(placement.pageWidth), pdfNumber(placement.pageHeight), pdfReference(xobject), pdfReference(contents)))
+		if err := pdf.stream(contents, "", []byte(placement.contents()), false); err != nil {
+			return err
+		}
+
+		if img.jpeg != nil {
+			dictionary := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode", size.X, size.Y, img.colorSpace)
+			if err := pdf.stream(xobject, dictionary, img.jpeg, false); err != nil {
+				return err
+			}
+			continue
+		}
+
+		pixels, alpha, colorSpace := pdfImageData(img.pixels)
+		mask := ""
+		if alpha != nil {
+			mask = " /SMask " + pdfReference(number)
+		}
+		var err error
+		if jpegQuality != nil {
+			err = pdf.jpegStream(xobject, img.pixels, colorSpace, mask, *jpegQuality)
+		} else {
+			err = pdf.imageStream(xobject, size, colorSpace, mask, pixels)
+		}
+		if err != nil {
+			return err
+		}
+		if alpha != nil {
+			if err := pdf.imageStream(number, size, "/DeviceGray", "", alpha); err != nil {
+				return err
+			}
+			number++
+		}
+	}
+
+	pdf.finish(catalog)
+	_, err := w.Write(pdf.buffer.Bytes())
+	return err
+}
+
+// contents draws the page image, clipped to the printable area when it
+// overflows it.
+func (p pagePlacement) contents() string {
+	clip := ""
+	if p.clip {
+		clip = fmt.Sprintf("%s %s %s %s re W n ", pdfNumber(p.area[0]), pdfNumber(p.area[1]), pdfNumber(p.area[2]), pdfNumber(p.area[3]))
+	}
+
+	return fmt.Sprintf("q %s%s 0 0 %s %s %s cm /Im0 Do Q", clip, pdfNumber(p.image[2]), pdfNumber(p.image[3]), pdfNumber(p.image[0]), pdfNumber(p.image[1]))
+}
+
+// pdfImageData splits an image into 8-bit color samples and, unless it is
+// opaque, an 8-bit alpha channel. PDF expects straight alpha.
+func pdfImageData(img image.Image) ([]byte, []byte, string) {
+	bounds := img.Bounds()
+	gray := false
+	switch img.ColorModel() {
+	case color.GrayModel, color.Gray16Model:
+		gray = true
+	}
+
+	channels, colorSpace := 3, "/DeviceRGB"
+	if gray {
+		channels, colorSpace = 1, "/DeviceGray"
+	}
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*channels)
+	var alpha []byte
+	if !isOpaque(img) {
+		alpha = make([]byte, 0, bounds.Dx()*bounds.Dy())
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixel := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if gray {
+				pixels = append(pixels, pixel.R)
+			} else {
+				pixels = append(pixels, pixel.R, pixel.G, pixel.B)
+			}
+			if alpha != nil {
+				alpha = append(alpha, pixel.A)
+			}
+		}
+	}
+
+	return pixels, alpha, colorSpace
+}
+
+func isOpaque(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+
+	return false
+}
+
+// pdfWriter builds a PDF file in memory and records object offsets for the
+// cross-reference table.
+type pdfWriter struct {
+	buffer  bytes.Buffer
+	offsets []int
+}
+
+func (p *pdfWriter) object(number int, body string) {
+	p.begin(number)
+	p.buffer.WriteString(body)
+	p.buffer.WriteString("\nendobj\n")
+}
+
+func (p *pdfWriter) imageStream(number int, size image.Point, colorSpace string, extra string, samples []byte) error {
+	dictionary := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8%s", size.X, size.Y, colorSpace, extra)
+	return p.stream(number, dictionary, samples, true)
+}
+
+// jpegStream stores the image as a baseline JPEG; the soft mask, if any,
+// stays lossless.
+func (p *pdfWriter) jpegStream(number int, img image.Image, colorSpace string, extra string, quality int) error {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: quality}); err != nil {
+		return err
+	}
+
+	size := img.Bounds().Size()
+	dictionary := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8%s /Filter /DCTDecode", size.X, size.Y, colorSpace, extra)
+	return p.stream(number, dictionary, encoded.Bytes(), false)
+}
+
+func (p *pdfWriter) stream(number int, dictionary string, data []byte, compress bool) error {
+	if compress {
+		var compressed bytes.Buffer
+		writer := zlib.NewWriter(&compressed)
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		data = compressed.Bytes()
+		dictionary += " /Filter /FlateDecode"
+	}
+
+	p.begin(number)
+	fmt.Fprintf(&p.buffer, "<< %s /Length %d >>\nstream\n", strings.TrimSpace(dictionary), len(data))
+	p.buffer.Write(data)
+	p.buffer.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+func (p *pdfWriter) begin(number int) {
+	for len(p.offsets) < number {
+		p.offsets = append(p.offsets, 0)
+	}
+	p.offsets[number-1] = p.buffer.Len()
+	fmt.Fprintf(&p.buffer, "%d 0 obj\n", number)
+}
+
+func (p *pdfWriter) finish(root int) {
+	start := p.buffer.Len()
+	fmt.Fprintf(&p.buffer, "xref\n0 %d\n0000000000 65535 f \n", len(p.offsets)+1)
+	for _, offset := range p.offsets {
+		fmt.Fprintf(&p.buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&p.buffer, "trailer\n<< /Size %d /Root %s >>\nstartxref\n%d\n%%%%EOF\n", len(p.offsets)+1, pdfReference(root), start)
+}
+
+func pdfReference(number int) string {
+	return strconv.Itoa(number) + " 0 R"
+}
+
+func pdfNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 32)
+}
@@ -0,0 +1,518 @@
+package converter
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// pdfRect is a box in points: left, bottom, right and top.
+type pdfRect [4]float64
+
+// pdfPage is a page with its boxes resolved: inherited from the page tree,
+// defaulted as the spec says and clipped to the box they default to.
+type pdfPage struct {
+	media  pdfRect
+	crop   pdfRect
+	trim   pdfRect
+	rotate int
+	// cropKey is where the crop box in effect is set, or nil when the page
+	// has none.
+	cropKey *pdfKey
+}
+
+// pdfKey is a dictionary key of the page tree. offset is the position of
+// its name in the file; keys read from compressed object streams have none.
+type pdfKey struct {
+	offset     int
+	compressed bool
+}
+
+// pdfObjectStreamLimit bounds the decompressed size of one object stream.
+const pdfObjectStreamLimit = 16 << 20
+
+var pdfObjectStart = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+type pdfRef int
+
+type pdfName string
+
+// pdfDict keeps where each key is written, so keys can be renamed in place.
+type pdfDict struct {
+	entries map[pdfName]any
+	keys    map[pdfName]int
+}
+
+type pdfObject struct {
+	value      any
+	compressed bool
+}
+
+// readPDFPages reads the page tree of a PDF file. Objects are found by
+// scanning the file rather than through its cross-reference table, so a
+// later definition, from an incremental update, replaces an earlier one.
+func readPDFPages(input []byte) ([]pdfPage, error) {
+	objects, catalog, err := scanPDFObjects(input)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := objects[catalog].value.(pdfDict)
+	if !ok {
+		return nil, errors.New("no document catalog")
+	}
+
+	reader := pdfPageReader{objects: objects, visited: map[pdfRef]bool{}}
+	if err := reader.walk(root.entries["Pages"], pdfPage{}, 0); err != nil {
+		return nil, err
+	}
+	if len(reader.pages) == 0 {
+		return nil, errors.New("no pages")
+	}
+
+	return reader.pages, nil
+}
+
+func scanPDFObjects(input []byte) (map[pdfRef]pdfObject, pdfRef, error) {
+	objects := map[pdfRef]pdfObject{}
+	catalog := pdfRef(-1)
+	record := func(number pdfRef, value any, compressed bool) {
+		objects[number] = pdfObject{value: value, compressed: compressed}
+		if dict, ok := value.(pdfDict); ok && dict.entries["Type"] == pdfName("Catalog") {
+			catalog = number
+		}
+	}
+
+	for position := 0; position < len(input); {
+		match := pdfObjectStart.FindSubmatchIndex(input[position:])
+		if match == nil {
+			break
+		}
+		number, err := strconv.Atoi(string(input[position+match[2] : position+match[3]]))
+		if err != nil {
+			return nil, 0, err
+		}
+		parser := pdfParser{data: input, position: position + match[1]}
+		position += match[1]
+		value, err := parser.value()
+		if err != nil {
+			continue
+		}
+		position = parser.position
+		record(pdfRef(number), value, false)
+
+		dict, ok := value.(pdfDict)
+		if !ok || !parser.keyword("stream") {
+			continue
+		}
+		data, end := parser.streamData(dict)
+		position = end
+		if dict.entries["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		// Object streams that cannot be read leave their objects out; pages
+		// among them are then missing from the tree.
+		if contained, err := readPDFObjectStream(dict, data); err == nil {
+			for number, value := range contained {
+				record(number, value, true)
+			}
+		}
+	}
+	if catalog < 0 {
+		return nil, 0, errors.New("no document catalog")
+	}
+
+	return objects, catalog, nil
+}
+
+func readPDFObjectStream(dict pdfDict, data []byte) (map[pdfRef]any, error) {
+	if _, ok := dict.entries["DecodeParms"]; ok {
+		return nil, errors.New("object stream predictors are not supported")
+	}
+	switch dict.entries["Filter"] {
+	case nil:
+	case pdfName("FlateDecode"):
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		if data, err = io.ReadAll(io.LimitReader(reader, pdfObjectStreamLimit)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("object stream filter %v is not supported", dict.entries["Filter"])
+	}
+
+	count, _ := dict.entries["N"].(float64)
+	first, _ := dict.entries["First"].(float64)
+	header := pdfParser{data: data}
+	objects := make(map[pdfRef]any, int(count))
+	for range int(count) {
+		number, numberErr := header.value()
+		offset, offsetErr := header.value()
+		if numberErr != nil || offsetErr != nil {
+			return nil, errors.New("invalid object stream header")
+		}
+		start := int(first) + int(offset.(float64))
+		if start < 0 || start >= len(data) {
+			return nil, errors.New("invalid object stream offset")
+		}
+		parser := pdfParser{data: data, position: start}
+		value, err := parser.value()
+		if err != nil {
+			return nil, err
+		}
+		objects[pdfRef(number.(float64))] = value
+	}
+
+	return objects, nil
+}
+
+type pdfPageReader struct {
+	objects map[pdfRef]pdfObject
+	visited map[pdfRef]bool
+	pages   []pdfPage
+}
+
+// walk visits a node of the page tree. MediaBox, CropBox and Rotate are
+// inherited by the pages below the node that sets them.
+func (r *pdfPageReader) walk(node any, inherited pdfPage, depth int) error {
+	ref, ok := node.(pdfRef)
+	if !ok || depth > 64 || r.visited[ref] {
+		return errors.New("invalid page tree")
+	}
+	r.visited[ref] = true
+	object := r.objects[ref]
+	dict, ok := object.value.(pdfDict)
+	if !ok {
+		return errors.New("invalid page tree")
+	}
+
+	page := inherited
+	if box, ok := r.rect(dict.entries["MediaBox"]); ok {
+		page.media = box
+	}
+	if box, ok := r.rect(dict.entries["CropBox"]); ok {
+		page.crop = box
+		page.cropKey = &pdfKey{offset: dict.keys["CropBox"], compressed: object.compressed}
+	}
+	if rotate, ok := r.resolve(dict.entries["Rotate"]).(float64); ok {
+		page.rotate = int(rotate)
+	}
+
+	if kids, ok := r.resolve(dict.entries["Kids"]).([]any); ok && dict.entries["Type"] != pdfName("Page") {
+		for _, kid := range kids {
+			if err := r.walk(kid, page, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if page.media == (pdfRect{}) {
+		return fmt.Errorf("page %d has no media box", len(r.pages)+1)
+	}
+	if page.cropKey == nil {
+		page.crop = page.media
+	}
+	page.crop = page.crop.intersect(page.media)
+	page.trim = page.crop
+	if box, ok := r.rect(dict.entries["TrimBox"]); ok {
+		page.trim = box.intersect(page.crop)
+	}
+	page.rotate = ((page.rotate % 360) + 360) % 360 / 90 * 90
+	r.pages = append(r.pages, page)
+
+	return nil
+}
+
+func (r *pdfPageReader) resolve(value any) any {
+	for range 8 {
+		ref, ok := value.(pdfRef)
+		if !ok {
+			return value
+		}
+		value = r.objects[ref].value
+	}
+
+	return nil
+}
+
+func (r *pdfPageReader) rect(value any) (pdfRect, bool) {
+	values, ok := r.resolve(value).([]any)
+	if !ok || len(values) != 4 {
+		return pdfRect{}, false
+	}
+	var box pdfRect
+	for index, value := range values {
+		number, ok := r.resolve(value).(float64)
+		if !ok {
+			return pdfRect{}, false
+		}
+		box[index] = number
+	}
+
+	return pdfRect{min(box[0], box[2]), min(box[1], box[3]), max(box[0], box[2]), max(box[1], box[3])}, true
+}
+
+func (b pdfRect) intersect(other pdfRect) pdfRect {
+	box := pdfRect{max(b[0], other[0]), max(b[1], other[1]), min(b[2], other[2]), min(b[3], other[3])}
+	if box[0] >= box[2] || box[1] >= box[3] {
+		return other
+	}
+
+	return box
+}
+
+// pdfParser reads PDF objects. Strings are skipped, as pages only need
+// names, numbers, arrays, dictionaries and references.
+type pdfParser struct {
+	data     []byte
+	position int
+}
+
+var errPDFSyntax = errors.New("invalid PDF syntax")
+
+func (p *pdfParser) value() (any, error) {
+	p.skipSpace()
+	if p.position >= len(p.data) {
+		return nil, errPDFSyntax
+	}
+
+	switch c := p.data[p.position]; {
+	case c == '/':
+		return p.name(), nil
+	case c == '<' && p.peek(1) == '<':
+		return p.dict()
+	case c == '<':
+		return p.skipTo('>')
+	case c == '(':
+		return p.literalString()
+	case c == '[':
+		return p.array()
+	case c == '+' || c == '-' || c == '.' || isPDFDigit(c):
+		return p.number()
+	case isPDFRegular(c):
+		start := p.position
+		for p.position < len(p.data) && isPDFRegular(p.data[p.position]) {
+			p.position++
+		}
+		switch string(p.data[start:p.position]) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	}
+
+	return nil, errPDFSyntax
+}
+
+func (p *pdfParser) dict() (pdfDict, error) {
+	dict := pdfDict{entries: map[pdfName]any{}, keys: map[pdfName]int{}}
+	p.position += 2
+	for {
+		p.skipSpace()
+		if p.position >= len(p.data) {
+			return pdfDict{}, errPDFSyntax
+		}
+		if p.data[p.position] == '>' && p.peek(1) == '>' {
+			p.position += 2
+			return dict, nil
+		}
+		if p.data[p.position] != '/' {
+			return pdfDict{}, errPDFSyntax
+		}
+		offset := p.position
+		key := p.name()
+		value, err := p.value()
+		if err != nil {
+			return pdfDict{}, err
+		}
+		dict.entries[key] = value
+		dict.keys[key] = offset
+	}
+}
+
+func (p *pdfParser) array() ([]any, error) {
+	var values []any
+	p.position++
+	for {
+		p.skipSpace()
+		if p.position >= len(p.data) {
+			return nil, errPDFSyntax
+		}
+		if p.data[p.position] == ']' {
+			p.position++
+			return values, nil
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+}
+
+// number also reads references, which start with the object number.
+func (p *pdfParser) number() (any, error) {
+	start := p.position
+	for p.position < len(p.data) && (isPDFDigit(p.data[p.position]) || bytes.IndexByte([]byte("+-."), p.data[p.position]) >= 0) {
+		p.position++
+	}
+	number, err := strconv.ParseFloat(string(p.data[start:p.position]), 64)
+	if err != nil {
+		return nil, errPDFSyntax
+	}
+
+	end := p.position
+	if p.integer() {
+		p.skipSpace()
+		if p.position < len(p.data) && p.data[p.position] == 'R' && !isPDFRegular(p.peek(1)) {
+			p.position++
+			return pdfRef(number), nil
+		}
+	}
+	p.position = end
+
+	return number, nil
+}
+
+func (p *pdfParser) integer() bool {
+	p.skipSpace()
+	start := p.position
+	for p.position < len(p.data) && isPDFDigit(p.data[p.position]) {
+		p.position++
+	}
+
+	return p.position > start
+}
+
+func (p *pdfParser) name() pdfName {
+	p.position++
+	var name []byte
+	for p.position < len(p.data) && isPDFRegular(p.data[p.position]) {
+		c := p.data[p.position]
+		if c == '#' && p.position+2 < len(p.data) {
+			if decoded, err := strconv.ParseUint(string(p.data[p.position+1:p.position+3]), 16, 8); err == nil {
+				name = append(name, byte(decoded))
+				p.position += 3
+				continue
+			}
+		}
+		name = append(name, c)
+		p.position++
+	}
+
+	return pdfName(name)
+}
+
+func (p *pdfParser) literalString() (any, error) {
+	depth := 0
+	for ; p.position < len(p.data); p.position++ {
+		switch p.data[p.position] {
+		case '\\':
+			p.position++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.position++
+				return "", nil
+			}
+		}
+	}
+
+	return nil, errPDFSyntax
+}
+
+func (p *pdfParser) skipTo(end byte) (any, error) {
+	index := bytes.IndexByte(p.data[p.position:], end)
+	if index < 0 {
+		return nil, errPDFSyntax
+	}
+	p.position += index + 1
+
+	return "", nil
+}
+
+// keyword consumes the keyword if it comes next.
+func (p *pdfParser) keyword(keyword string) bool {
+	p.skipSpace()
+	if !bytes.HasPrefix(p.data[p.position:], []byte(keyword)) {
+		return false
+	}
+	p.position += len(keyword)
+
+	return true
+}
+
+// streamData returns the data of the stream whose dictionary was just read
+// and the position after it. Lengths given by reference are not followed;
+// the data then ends at the endstream keyword.
+func (p *pdfParser) streamData(dict pdfDict) ([]byte, int) {
+	start := p.position
+	if bytes.HasPrefix(p.data[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(p.data) && (p.data[start] == '\n' || p.data[start] == '\r') {
+		start++
+	}
+
+	if length, ok := dict.entries["Length"].(float64); ok && length >= 0 && start+int(length) <= len(p.data) {
+		end := start + int(length)
+		after := pdfParser{data: p.data, position: end}
+		if after.keyword("endstream") {
+			return p.data[start:end], after.position
+		}
+	}
+	index := bytes.Index(p.data[start:], []byte("endstream"))
+	if index < 0 {
+		return p.data[start:], len(p.data)
+	}
+
+	return bytes.TrimRight(p.data[start:start+index], "\r\n"), start + index + len("endstream")
+}
+
+func (p *pdfParser) skipSpace() {
+	for p.position < len(p.data) {
+		switch c := p.data[p.position]; {
+		case c == '%':
+			for p.position < len(p.data) && p.data[p.position] != '\n' && p.data[p.position] != '\r' {
+				p.position++
+			}
+		case isPDFSpace(c):
+			p.position++
+		default:
+			return
+		}
+	}
+}
+
+func (p *pdfParser) peek(offset int) byte {
+	if p.position+offset >= len(p.data) {
+		return 0
+	}
+
+	return p.data[p.position+offset]
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isPDFDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isPDFRegular reports characters that are neither white space nor
+// delimiters.
+func isPDFRegular(c byte) bool {
+	return !isPDFSpace(c) && bytes.IndexByte([]byte("()<>[]{}/%"), c) < 0
+}
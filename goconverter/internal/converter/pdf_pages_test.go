@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadPDFPagesInheritsBoxesAndKeepsLaterObjects(t *testing.T) {
+	input := mustBuildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 612 792] /Rotate 90 >>",
+		"<< /Type /Page /Parent 2 0 R /Rotate -90 >>",
+		"<< /Type /Page /Parent 2 0 R /CropBox 5 0 R /Contents [(\\)>>) <3E3E>] >>",
+		"[10 20 30 40]",
+	)
+	// An incremental update replaces the media box of the pages.
+	input = append(input, "2 0 obj\n<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [612 792 0 0] >>\nendobj\n"...)
+
+	pages, err := readPDFPages(input)
+	if err != nil {
+		t.Fatalf("expected the page tree to be read, got error: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+	if pages[0].media != (pdfRect{0, 0, 612, 792}) || pages[0].crop != pages[0].media || pages[0].rotate != 270 || pages[0].cropKey != nil {
+		t.Fatalf("expected the first page to inherit the updated media box, got %+v", pages[0])
+	}
+	if pages[1].crop != (pdfRect{10, 20, 30, 40}) || pages[1].trim != pages[1].crop || pages[1].rotate != 0 {
+		t.Fatalf("expected the second page to resolve its crop box, got %+v", pages[1])
+	}
+}
+
+func TestPDFPageTrimRegionFollowsRotation(t *testing.T) {
+	page := pdfPage{crop: pdfRect{0, 0, 100, 200}, trim: pdfRect{10, 20, 50, 180}}
+	tests := []struct {
+		rotate   int
+		expected pdfRegion
+	}{
+		{rotate: 0, expected: pdfRegion{0.1, 0.1, 0.5, 0.9}},
+		{rotate: 90, expected: pdfRegion{0.1, 0.1, 0.9, 0.5}},
+		{rotate: 180, expected: pdfRegion{0.5, 0.1, 0.9, 0.9}},
+		{rotate: 270, expected: pdfRegion{0.1, 0.5, 0.9, 0.9}},
+	}
+
+	for _, tt := range tests {
+		page.rotate = tt.rotate
+		if region := page.trimRegion(); !regionsAlmostEqual(region, tt.expected) {
+			t.Fatalf("rotate %d: expected region %v, got %v", tt.rotate, tt.expected, region)
+		}
+	}
+}
+
+func regionsAlmostEqual(a pdfRegion, b pdfRegion) bool {
+	const epsilon = 1e-9
+	return math.Abs(a.left-b.left) < epsilon && math.Abs(a.top-b.top) < epsilon &&
+		math.Abs(a.right-b.right) < epsilon && math.Abs(a.bottom-b.bottom) < epsilon
+}
+
+// mustBuildPDF numbers the objects from 1. Pages are found by scanning for
+// objects, so no cross-reference table is written.
+func mustBuildPDF(objects ...string) []byte {
+	var output bytes.Buffer
+	output.WriteString("%PDF-1.7\n")
+	for index, object := range objects {
+		fmt.Fprintf(&output, "%d 0 obj\n%s\nendobj\n", index+1, object)
+	}
+	output.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	return output.Bytes()
+}
+
+// mustBuildPDFObjectStream returns a compressed object stream holding the
+// objects, to be passed to mustBuildPDF.
+func mustBuildPDFObjectStream(t *testing.T, objects map[int]string) string {
+	t.Helper()
+
+	var header, body strings.Builder
+	numbers := make([]int, 0, len(objects))
+	for number := range objects {
+		numbers = append(numbers, number)
+	}
+	slices.Sort(numbers)
+	for _, number := range numbers {
+		fmt.Fprintf(&header, "%d %d ", number, body.Len())
+		body.WriteString(objects[number] + "\n")
+	}
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(header.String() + body.String())); err != nil {
+		t.Fatalf("compress object stream: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("compress object stream: %v", err)
+	}
+
+	return fmt.Sprintf("<< /Type /ObjStm /N %d /First %d /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+		len(numbers), header.Len(), compressed.Len(), compressed.String())
+}
@@ -0,0 +1,89 @@
+package converter
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestWritePDFSizesPagesFromDPI(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     image.Point
+		options  Options
+		mediaBox string
+		placed   string
+	}{
+		{name: "default", size: image.Pt(64, 32), options: Options{}, mediaBox: "[0 0 64 32]", placed: "q 64 0 0 32 0 0 cm"},
+		{name: "dpi", size: image.Pt(64, 32), options: Options{DPI: intPointer(144)}, mediaBox: "[0 0 32 16]", placed: "q 32 0 0 16 0 0 cm"},
+		{name: "a4", size: image.Pt(100, 200), options: Options{PageSize: PageSizeA4}, mediaBox: "[0 0 595.28 841.89]", placed: "q 100 0 0 200 247.64 320.945 cm"},
+		{name: "landscape", size: image.Pt(200, 100), options: Options{PageSize: PageSizeLetter}, mediaBox: "[0 0 792 612]", placed: "q 200 0 0 100 296 256 cm"},
+		{name: "scaled down", size: image.Pt(1224, 1584), options: Options{PageSize: PageSizeLetter}, mediaBox: "[0 0 612 792]", placed: "q 612 0 0 792 0 0 cm"},
+	}
+
+	for _, tt := range tests {
+		var output bytes.Buffer
+		if err := writePDF(&output, []pdfImage{{pixels: image.NewGray(image.Rectangle{Max: tt.size})}}, newPageLayout(tt.options.PageSize, tt.options.DPI), nil); err != nil {
+			t.Fatalf("%s: expected pdf to be written, got error: %v", tt.name, err)
+		}
+
+		assertPDFCrossReferences(t, output.Bytes())
+		if !bytes.Contains(output.Bytes(), []byte("/MediaBox "+tt.mediaBox)) {
+			t.Fatalf("%s: expected media box %s in %q", tt.name, tt.mediaBox, output.Bytes())
+		}
+		if !bytes.Contains(output.Bytes(), []byte(tt.placed)) {
+			t.Fatalf("%s: expected placement %q in %q", tt.name, tt.placed, output.Bytes())
+		}
+	}
+}
+
+func TestWritePDFKeepsTransparencyAsSoftMask(t *testing.T) {
+	translucent := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	translucent.Set(0, 0, color.NRGBA{R: 255, A: 128})
+
+	var output bytes.Buffer
+	if err := writePDF(&output, []pdfImage{{pixels: translucent}, {pixels: image.NewGray(image.Rect(0, 0, 1, 1))}}, newPageLayout("", nil), nil); err != nil {
+		t.Fatalf("expected pdf to be written, got error: %v", err)
+	}
+
+	assertPDFCrossReferences(t, output.Bytes())
+	if count := bytes.Count(output.Bytes(), []byte("/SMask ")); count != 1 {
+		t.Fatalf("expected one soft mask, got %d", count)
+	}
+	if !bytes.Contains(output.Bytes(), []byte("/Kids [3 0 R 7 0 R] /Count 2")) {
+		t.Fatalf("expected two pages after the soft mask, got %q", output.Bytes())
+	}
+}
+
+var pdfObjectHeader = regexp.MustCompile(`^(\d+) 0 obj\n`)
+
+// assertPDFCrossReferences checks that every xref entry points at the start
+// of its object and that startxref points at the table.
+func assertPDFCrossReferences(t *testing.T, pdf []byte) {
+	t.Helper()
+
+	start := bytes.LastIndex(pdf, []byte("startxref\n"))
+	if start < 0 {
+		t.Fatal("expected startxref")
+	}
+	fields := bytes.Fields(pdf[start+len("startxref\n"):])
+	table, err := strconv.Atoi(string(fields[0]))
+	if err != nil || !bytes.HasPrefix(pdf[table:], []byte("xref\n")) {
+		t.Fatalf("expected startxref to point at the xref table, got %q", fields[0])
+	}
+
+	lines := bytes.Split(pdf[table:], []byte("\n"))
+	for index, line := range lines[3:] {
+		if !bytes.HasSuffix(line, []byte(" n ")) {
+			break
+		}
+		offset, _ := strconv.Atoi(string(line[:10]))
+		match := pdfObjectHeader.FindSubmatch(pdf[offset:])
+		if match == nil || string(match[1]) != strconv.Itoa(index+1) {
+			t.Fatalf("expected object %d at offset %d", index+1, offset)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	hopCost          = 1
+	lossyHopPenalty  = 10
+	unreachableRoute = -1
+)
+
+// Plan is the cheapest route from a source to a target format through the
+// registered converters. A direct pair is a one-step plan.
+type Plan struct {
+	steps []Converter
+	cost  int
+}
+
+var _ Converter = Plan{}
+
+func (p Plan) SourceFormat() string {
+	return p.steps[0].SourceFormat()
+}
+
+func (p Plan) TargetFormat() string {
+	return p.steps[len(p.steps)-1].TargetFormat()
+}
+
+// Route lists every format the data passes through, source and target
+// included.
+func (p Plan) Route() []string {
+	route := make([]string, 0, len(p.steps)+1)
+	route = append(route, p.SourceFormat())
+	for _, step := range p.steps {
+		route = append(route, step.TargetFormat())
+	}
+
+	return route
+}
+
+// Backends names the backend of every hop, in route order.
+func (p Plan) Backends() []string {
+	backends := make([]string, 0, len(p.steps))
+	for _, step := range p.steps {
+		backends = append(backends, converterBackend(step))
+	}
+
+	return backends
+}
+
+func (p Plan) Cost() int {
+	return p.cost
+}
+
+func (p Plan) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := p.ConvertWithOptions(ctx, input, Options{})
+	return result.Output, err
+}
+
+// ConvertWithOptions applies source-side options such as pages, transforms
+// and metadata on the first hop, passes intermediates losslessly where the
+// format allows it, and applies the encoder options on the last hop.
+func (p Plan) ConvertWithOptions(ctx context.Context, input []byte, options Options) (Result, error) {
+	return p.convert(ctx, input, options, nil)
+}
+
+// ConvertTo is ConvertWithOptions writing the output to w, so the last hop
+// can stream it while it is encoded; Result.Output is left empty. Several
+// selected pages are returned in Result.Pages and nothing is written.
+func (p Plan) ConvertTo(ctx context.Context, input []byte, options Options, w io.Writer) (Result, error) {
+	return p.convert(ctx, input, options, w)
+}
+
+// convert writes the output of the last hop to w, or returns it when w is
+// nil.
+func (p Plan) convert(ctx context.Context, input []byte, options Options, w io.Writer) (Result, error) {
+	if len(p.steps) == 1 {
+		return convertStep(ctx, p.steps[0], input, options, w)
+	}
+	if err := ValidateOptions(p.SourceFormat(), p.TargetFormat(), options); err != nil {
+		return Result{}, fmt.Errorf("convert %s to %s: %w", p.SourceFormat(), p.TargetFormat(), err)
+	}
+
+	firstOptions := options.withoutEncoderOptions()
+	firstOptions.Lossless = losslessIntermediate(p.steps[0].TargetFormat())
+	result, err := p.steps[0].ConvertWithOptions(progressPart(ctx, 0, len(p.steps)), input, firstOptions)
+	if err != nil {
+		return Result{}, err
+	}
+
+	rest := p.steps[1:]
+	if len(result.Pages) == 0 {
+		output, frames, backends, err := convertThrough(ctx, rest, result.Output, options, w)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Output, result.Frames = output, frames
+		result.Backends = append(result.Backends, backends...)
+		return result, nil
+	}
+
+	hopBackends := make([][]string, len(rest))
+	for index, page := range result.Pages {
+		output, _, pageBackends, err := convertThrough(ctx, rest, page.Output, options, nil)
+		if err != nil {
+			return Result{}, fmt.Errorf("page %d: %w", page.Page, err)
+		}
+		result.Pages[index].Output = output
+		result.Pages[index].Backends = append(slices.Clone(page.Backends), pageBackends...)
+		for hop, backend := range pageBackends {
+			if !slices.Contains(hopBackends[hop], backend) {
+				hopBackends[hop] = append(hopBackends[hop], backend)
+			}
+		}
+	}
+	for _, backends := range hopBackends {
+		result.Backends = append(result.Backends, strings.Join(backends, "+"))
+	}
+
+	return result, nil
+}
+
+// convertThrough runs already processed data through the remaining hops and
+// returns the backend used for each of them. The last hop writes to w unless
+// it is nil. Progress is reported as if the first hop were done.
+func convertThrough(ctx context.Context, steps []Converter, input []byte, options Options, w io.Writer) ([]byte, int, []string, error) {
+	output := input
+	frames := 0
+	backends := make([]string, 0, len(steps))
+	for index, step := range steps {
+		stepOptions := Options{Lossless: losslessIntermediate(step.TargetFormat())}
+		stepWriter := io.Writer(nil)
+		if index == len(steps)-1 {
+			stepOptions = options.encoderOptions()
+			stepWriter = w
+		}
+		stepOptions.ColorSpace = ColorSpaceKeep
+		stepOptions.DisableAutoRotate = true
+
+		result, err := convertStep(progressPart(ctx, index+1, len(steps)+1), step, output, stepOptions, stepWriter)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		output, frames = result.Output, result.Frames
+		backends = append(backends, result.Backends...)
+	}
+
+	return output, frames, backends, nil
+}
+
+func convertStep(ctx context.Context, step Converter, input []byte, options Options, w io.Writer) (Result, error) {
+	if w == nil {
+		return step.ConvertWithOptions(ctx, input, options)
+	}
+
+	return convertTo(ctx, step, input, options, w)
+}
+
+func losslessIntermediate(format string) bool {
+	details, ok := LookupFormat(format)
+	return ok && details.encoder.lossless
+}
+
+func routeHopCost(target string) int {
+	details, ok := LookupFormat(target)
+	if ok && details.Lossy {
+		return hopCost + lossyHopPenalty
+	}
+
+	return hopCost
+}
+
+// PlanConversion finds the cheapest route between two formats. Every hop costs
+// the same, and hops into lossy formats cost more, so lossless intermediates
+// win over shorter lossy routes.
+func PlanConversion(source string, target string) (Plan, bool) {
+	plans := planRoutes(converters, source)
+	plan, ok := plans[target]
+	return plan, ok
+}
+
+// planRoutes runs Dijkstra over the given converters. Ties keep the first
+// route found in registry order, so direct pairs beat equal-cost detours.
+func planRoutes(registered []Converter, source string) map[string]Plan {
+	edges := map[string][]Converter{}
+	for _, c := range registered {
+		edges[c.SourceFormat()] = append(edges[c.SourceFormat()], c)
+	}
+
+	plans := map[string]Plan{}
+	costs := map[string]int{source: 0}
+	visited := map[string]bool{}
+	routes := map[string][]Converter{source: nil}
+	for {
+		current, currentCost := "", unreachableRoute
+		for _, format := range formats {
+			cost, ok := costs[format.Name]
+			if !ok || visited[format.Name] {
+				continue
+			}
+			if currentCost == unreachableRoute || cost < currentCost {
+				current, currentCost = format.Name, cost
+			}
+		}
+		if current == "" {
+			break
+		}
+		visited[current] = true
+		if current != source {
+			plans[current] = Plan{steps: routes[current], cost: currentCost}
+		}
+
+		for _, edge := range edges[current] {
+			next := edge.TargetFormat()
+			cost := currentCost + routeHopCost(next)
+			if existing, ok := costs[next]; ok && existing <= cost {
+				continue
+			}
+			costs[next] = cost
+			routes[next] = append(append([]Converter{}, routes[current]...), edge)
+		}
+	}
+
+	return plans
+}
+
+// ConversionRoutes returns the planned route for every reachable source and
+// target pair.
+func ConversionRoutes() map[string]map[string][]string {
+	output := map[string]map[string][]string{}
+	for _, format := range formats {
+		plans := planRoutes(converters, format.Name)
+		if len(plans) == 0 {
+			continue
+		}
+
+		routes := make(map[string][]string, len(plans))
+		for target, plan := range plans {
+			routes[target] = plan.Route()
+		}
+		output[format.Name] = routes
+	}
+
+	return output
+}
@@ -0,0 +1,208 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type recordingConverter struct {
+	source  string
+	target  string
+	options *[]Options
+}
+
+func (c recordingConverter) SourceFormat() string {
+	return c.source
+}
+
+func (c recordingConverter) TargetFormat() string {
+	return c.target
+}
+
+func (c recordingConverter) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := c.ConvertWithOptions(ctx, input, Options{})
+	return result.Output, err
+}
+
+func (c recordingConverter) ConvertWithOptions(ctx context.Context, input []byte, options Options) (Result, error) {
+	if c.options != nil {
+		*c.options = append(*c.options, options)
+	}
+	ReportProgress(ctx, 100)
+
+	return Result{Output: append(append([]byte{}, input...), c.target...)}, nil
+}
+
+func recordingGraph(options *[]Options, pairs ...[2]string) []Converter {
+	output := make([]Converter, 0, len(pairs))
+	for _, pair := range pairs {
+		output = append(output, recordingConverter{source: pair[0], target: pair[1], options: options})
+	}
+
+	return output
+}
+
+func TestPlanRoutesPrefersLosslessIntermediates(t *testing.T) {
+	graph := recordingGraph(nil,
+		[2]string{"svg", "gif"},
+		[2]string{"gif", "jpeg"},
+		[2]string{"svg", "png"},
+		[2]string{"png", "tiff"},
+		[2]string{"tiff", "jpeg"},
+	)
+
+	plan, ok := planRoutes(graph, "svg")["jpeg"]
+	if !ok {
+		t.Fatal("expected a route from svg to jpeg")
+	}
+	if route := plan.Route(); !reflect.DeepEqual(route, []string{"svg", "png", "tiff", "jpeg"}) {
+		t.Fatalf("expected lossless route through png and tiff, got %v", route)
+	}
+	if plan.Cost() != 3*hopCost+lossyHopPenalty {
+		t.Fatalf("expected cost %d, got %d", 3*hopCost+lossyHopPenalty, plan.Cost())
+	}
+}
+
+func TestPlanRoutesPrefersDirectPairs(t *testing.T) {
+	graph := recordingGraph(nil,
+		[2]string{"png", "tiff"},
+		[2]string{"tiff", "webp"},
+		[2]string{"png", "webp"},
+	)
+
+	plan, ok := planRoutes(graph, "png")["webp"]
+	if !ok {
+		t.Fatal("expected a route from png to webp")
+	}
+	if route := plan.Route(); !reflect.DeepEqual(route, []string{"png", "webp"}) {
+		t.Fatalf("expected direct route, got %v", route)
+	}
+
+	if _, ok := planRoutes(graph, "webp")["png"]; ok {
+		t.Fatal("did not expect a route from webp to png")
+	}
+}
+
+func TestPlanConvertWithOptionsSplitsOptionsAcrossHops(t *testing.T) {
+	var recorded []Options
+	graph := recordingGraph(&recorded,
+		[2]string{"pdf", "webp"},
+		[2]string{"webp", "png"},
+		[2]string{"png", "jpeg"},
+	)
+	plan := planRoutes(graph, "pdf")["jpeg"]
+
+	options := Options{
+		Quality:   intPointer(70),
+		Transform: Transform{Width: 10},
+		Metadata:  MetadataStrip,
+	}
+	result, err := plan.ConvertWithOptions(context.Background(), []byte("in:"), options)
+	if err != nil {
+		t.Fatalf("expected plan to succeed, got error: %v", err)
+	}
+	if string(result.Output) != "in:webppngjpeg" {
+		t.Fatalf("expected output to pass through every hop, got %q", result.Output)
+	}
+	if len(recorded) != 3 {
+		t.Fatalf("expected 3 hops, got %d", len(recorded))
+	}
+
+	first, intermediate, last := recorded[0], recorded[1], recorded[2]
+	if first.Quality != nil || !first.Lossless || first.Transform.Width != 10 || first.Metadata != MetadataStrip {
+		t.Fatalf("expected first hop to get source options and lossless output, got %+v", first)
+	}
+	if intermediate.Lossless || intermediate.Transform.Width != 0 || intermediate.ColorSpace != ColorSpaceKeep || !intermediate.DisableAutoRotate {
+		t.Fatalf("expected png hop to pass pixels through untouched, got %+v", intermediate)
+	}
+	if last.Quality == nil || *last.Quality != 70 || last.Transform.Width != 0 {
+		t.Fatalf("expected last hop to get encoder options only, got %+v", last)
+	}
+}
+
+func TestPlanReportsProgressPerHop(t *testing.T) {
+	graph := recordingGraph(nil,
+		[2]string{"pdf", "webp"},
+		[2]string{"webp", "png"},
+		[2]string{"png", "jpeg"},
+		[2]string{"jpeg", "gif"},
+	)
+	plan := planRoutes(graph, "pdf")["gif"]
+
+	var reported []int
+	ctx := WithProgress(context.Background(), func(percent int) { reported = append(reported, percent) })
+	if _, err := plan.ConvertWithOptions(ctx, []byte("in:"), Options{}); err != nil {
+		t.Fatalf("expected plan to succeed, got error: %v", err)
+	}
+	if !reflect.DeepEqual(reported, []int{25, 50, 75, 100}) {
+		t.Fatalf("expected each hop to report its share, got %v", reported)
+	}
+}
+
+func TestPlanConvertToWritesTheLastHop(t *testing.T) {
+	graph := recordingGraph(nil,
+		[2]string{"webp", "png"},
+		[2]string{"png", "jpeg"},
+	)
+	plan := planRoutes(graph, "webp")["jpeg"]
+
+	var output bytes.Buffer
+	result, err := plan.ConvertTo(context.Background(), []byte("in:"), Options{}, &output)
+	if err != nil {
+		t.Fatalf("expected plan to succeed, got error: %v", err)
+	}
+	if output.String() != "in:pngjpeg" {
+		t.Fatalf("expected the output to be written, got %q", output.String())
+	}
+	if result.Output != nil {
+		t.Fatalf("expected written output not to be returned, got %q", result.Output)
+	}
+}
+
+// pagingConverter splits its input into two pages.
+type pagingConverter struct {
+	scriptedConverter
+}
+
+func (pagingConverter) ConvertWithOptions(_ context.Context, _ []byte, _ Options) (Result, error) {
+	return Result{Pages: []PageResult{{Page: 1, Output: []byte("1")}, {Page: 2, Output: []byte("2")}}}, nil
+}
+
+// pickyConverter fails with a retryable error on the second page.
+type pickyConverter struct {
+	scriptedConverter
+}
+
+func (pickyConverter) ConvertWithOptions(_ context.Context, input []byte, _ Options) (Result, error) {
+	if string(input) == "2" {
+		return Result{}, RetryableError(errors.New("no loader"))
+	}
+
+	return Result{Output: input}, nil
+}
+
+func TestPlanReportsTheBackendsOfEachPage(t *testing.T) {
+	plan := Plan{steps: []Converter{
+		&backendChain{source: "pdf", target: "png", candidates: []backendCandidate{
+			{backend: "libvips", converter: pagingConverter{scriptedConverter{source: "pdf", target: "png"}}},
+		}},
+		&backendChain{source: "png", target: "jpeg", candidates: []backendCandidate{
+			{backend: "libvips", converter: pickyConverter{scriptedConverter{source: "png", target: "jpeg"}}},
+			{backend: "imagemagick", converter: scriptedConverter{source: "png", target: "jpeg", output: "2"}},
+		}},
+	}}
+
+	result, err := plan.ConvertWithOptions(context.Background(), []byte("in"), Options{})
+	if err != nil {
+		t.Fatalf("expected plan to succeed, got error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Backends, []string{"libvips", "libvips+imagemagick"}) {
+		t.Fatalf("expected both backends of the second hop, got %v", result.Backends)
+	}
+	if !reflect.DeepEqual(result.Pages[0].Backends, []string{"libvips", "libvips"}) || !reflect.DeepEqual(result.Pages[1].Backends, []string{"libvips", "imagemagick"}) {
+		t.Fatalf("expected the backends of each page, got %v and %v", result.Pages[0].Backends, result.Pages[1].Backends)
+	}
+}
@@ -0,0 +1,46 @@
+package converter
+
+import "context"
+
+type progressKey struct{}
+
+// progressRange maps the 0 to 100 percent of one step of a conversion onto
+// its share of the whole.
+type progressRange struct {
+	report func(percent int)
+	from   int
+	to     int
+}
+
+// WithProgress returns a context under which conversions report how far
+// they got, in percent. Reports may come from libvips threads and are not
+// guaranteed to increase.
+func WithProgress(ctx context.Context, report func(percent int)) context.Context {
+	return context.WithValue(ctx, progressKey{}, progressRange{report: report, to: 100})
+}
+
+// ReportProgress reports percent of the work done under ctx, if anyone
+// listens.
+func ReportProgress(ctx context.Context, percent int) {
+	if r, ok := ctx.Value(progressKey{}).(progressRange); ok {
+		r.report(r.from + (r.to-r.from)*min(max(percent, 0), 100)/100)
+	}
+}
+
+func progressReported(ctx context.Context) bool {
+	_, ok := ctx.Value(progressKey{}).(progressRange)
+	return ok
+}
+
+// progressPart narrows the reports made under the returned context to part
+// of parts equal shares of the work done under ctx.
+func progressPart(ctx context.Context, part int, parts int) context.Context {
+	r, ok := ctx.Value(progressKey{}).(progressRange)
+	if !ok {
+		return ctx
+	}
+
+	span := r.to - r.from
+	r.from, r.to = r.from+span*part/parts, r.from+span*(part+1)/parts
+	return context.WithValue(ctx, progressKey{}, r)
+}
@@ -0,0 +1,287 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PDF boxes a page can be rendered from. Loaders render the crop box, which
+// defaults to the media box; the trim box defaults to the crop box.
+const (
+	PDFBoxMedia = "media"
+	PDFBoxCrop  = "crop"
+	PDFBoxTrim  = "trim"
+)
+
+var pdfBoxes = []string{PDFBoxMedia, PDFBoxCrop, PDFBoxTrim}
+
+// rasterizedFormats are the vector sources rasterization options apply to.
+var rasterizedFormats = []string{"svg", "pdf"}
+
+var scaleRange = [2]float64{0.01, 100}
+
+// Rasterization controls how vector sources are rendered to pixels. DPI and
+// Scale set the density; Width and Height instead pick the density that
+// renders the page at that size, fitting inside both when both are set.
+// Background flattens transparent areas onto a color and PDFBox picks the
+// page box PDF pages are rendered from.
+type Rasterization struct {
+	DPI        *int
+	Scale      *float64
+	Width      int
+	Height     int
+	Background string
+	PDFBox     string
+}
+
+func (r Rasterization) IsZero() bool {
+	return r.DPI == nil && r.Scale == nil && r.Width == 0 && r.Height == 0 && r.Background == "" && r.PDFBox == ""
+}
+
+// SupportsRasterization reports whether the source accepts rasterization
+// options.
+func SupportsRasterization(sourceFormat string) bool {
+	return slices.Contains(rasterizedFormats, sourceFormat)
+}
+
+func validateRasterization(sourceFormat string, r Rasterization) error {
+	if r.IsZero() {
+		return nil
+	}
+	if !SupportsRasterization(sourceFormat) {
+		return invalidOptionsError("rasterize is not supported for %s input", sourceFormat)
+	}
+
+	if r.DPI != nil && r.Scale != nil {
+		return invalidOptionsError("rasterize dpi cannot be combined with scale")
+	}
+	if (r.DPI != nil || r.Scale != nil) && (r.Width != 0 || r.Height != 0) {
+		return invalidOptionsError("rasterize width and height cannot be combined with dpi or scale")
+	}
+	if r.DPI != nil {
+		if err := validateRange("rasterize dpi", *r.DPI, dpiRange); err != nil {
+			return err
+		}
+	}
+	if r.Scale != nil && (*r.Scale < scaleRange[0] || *r.Scale > scaleRange[1]) {
+		return invalidOptionsError("rasterize scale must be between %g and %g", scaleRange[0], scaleRange[1])
+	}
+	if r.Width < 0 || r.Height < 0 || r.Width > maxTransformDimension || r.Height > maxTransformDimension {
+		return invalidOptionsError("rasterize width and height must be between 1 and %d", maxTransformDimension)
+	}
+
+	if r.Background != "" {
+		color, err := parseColor(r.Background)
+		if err != nil {
+			return err
+		}
+		if color.a != 255 {
+			return invalidOptionsError("rasterize background must be opaque")
+		}
+	}
+
+	if r.PDFBox != "" {
+		if sourceFormat != "pdf" {
+			return invalidOptionsError("pdfBox is not supported for %s input", sourceFormat)
+		}
+		if !slices.Contains(pdfBoxes, r.PDFBox) {
+			return invalidOptionsError("pdfBox must be one of %s", strings.Join(pdfBoxes, ", "))
+		}
+	}
+
+	return nil
+}
+
+// loadVipsImage loads one image with the given loader parameters plus those
+// for rasterization, and crops it to the region. A target size needs the
+// natural size first, so the source is loaded twice; vector loaders only
+// render on demand.
+func loadVipsImage(input []byte, parameters []string, r Rasterization, region pdfRegion) (*vipsImage, error) {
+	if region == (pdfRegion{}) {
+		region = wholePage
+	}
+
+	switch {
+	case r.DPI != nil:
+		parameters = append(parameters, "dpi="+strconv.Itoa(*r.DPI))
+	case r.Scale != nil:
+		parameters = append(parameters, "scale="+formatLoadScale(*r.Scale))
+	case r.Width != 0 || r.Height != 0:
+		natural, err := vipsLoadBuffer(input, loadOptionString(parameters))
+		if err != nil {
+			return nil, err
+		}
+		width := float64(natural.width()) * (region.right - region.left)
+		height := float64(natural.height()) * (region.bottom - region.top)
+		scale := r.targetScale(max(1, int(math.Round(width))), max(1, int(math.Round(height))))
+		natural.close()
+		parameters = append(parameters, "scale="+formatLoadScale(scale))
+	}
+
+	var background rgbaColor
+	if r.Background != "" {
+		var err error
+		if background, err = parseColor(r.Background); err != nil {
+			return nil, err
+		}
+		// PDF loaders paint the page white before rendering it.
+		if detection, ok := DetectFormat(input); ok && detection.Format == "pdf" {
+			parameters = append(parameters, fmt.Sprintf(`background="%d %d %d 255"`, background.r, background.g, background.b))
+		}
+	}
+
+	image, err := vipsLoadBuffer(input, loadOptionString(parameters))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkImageSize(image.width(), image.height()); err != nil {
+		image.close()
+		return nil, err
+	}
+	if !region.isWholePage() {
+		if err := extractRegion(image, region); err != nil {
+			image.close()
+			return nil, fmt.Errorf("extract %s box: %w", r.PDFBox, err)
+		}
+	}
+	if r.Background != "" {
+		if err := image.flatten(background); err != nil {
+			image.close()
+			return nil, fmt.Errorf("flatten: %w", err)
+		}
+	}
+
+	return image, nil
+}
+
+// targetScale is the render scale that makes a page of the natural size fit
+// the requested width and height.
+func (r Rasterization) targetScale(width int, height int) float64 {
+	scale := math.Inf(1)
+	if r.Width != 0 {
+		scale = float64(r.Width) / float64(width)
+	}
+	if r.Height != 0 {
+		scale = min(scale, float64(r.Height)/float64(height))
+	}
+
+	return scale
+}
+
+func formatLoadScale(scale float64) string {
+	return strconv.FormatFloat(scale, 'f', -1, 64)
+}
+
+func loadOptionString(parameters []string) string {
+	if len(parameters) == 0 {
+		return ""
+	}
+
+	return "[" + strings.Join(parameters, ",") + "]"
+}
+
+// pdfRegion is the part of a rendered page to keep, as fractions of its
+// width and height. The zero region keeps the whole page.
+type pdfRegion struct {
+	left, top, right, bottom float64
+}
+
+var wholePage = pdfRegion{right: 1, bottom: 1}
+
+func (r pdfRegion) isWholePage() bool {
+	return r == pdfRegion{} || r == wholePage
+}
+
+// selectPDFBox prepares a PDF for rendering the chosen box and returns the
+// region of each page to keep once rendered. Loaders render the crop box, so
+// the media box is rendered by renaming the crop box keys of the page tree
+// in place, which keeps every object offset valid, and the trim box is
+// cropped from the rendered crop box. A box that cannot be applied, such as
+// a media box on a page whose crop box is set in a compressed object stream,
+// is an invalid option rather than being ignored.
+func selectPDFBox(input []byte, box string) ([]byte, []pdfRegion, error) {
+	key := map[string]string{PDFBoxMedia: "/CropBox", PDFBoxTrim: "/TrimBox"}[box]
+	if key == "" || !bytes.Contains(input, []byte(key)) && !bytes.Contains(input, []byte("/ObjStm")) {
+		return input, nil, nil
+	}
+
+	pages, err := readPDFPages(input)
+	if err != nil {
+		return nil, nil, invalidOptionsError("pdfBox %s cannot be applied: %v", box, err)
+	}
+
+	if box == PDFBoxTrim {
+		regions := make([]pdfRegion, len(pages))
+		for index, page := range pages {
+			regions[index] = page.trimRegion()
+		}
+		return input, regions, nil
+	}
+
+	output := input
+	cloned := false
+	for index, page := range pages {
+		if page.cropKey == nil || page.crop == page.media {
+			continue
+		}
+		offset := page.cropKey.offset
+		if page.cropKey.compressed || !bytes.HasPrefix(input[offset:], []byte(key)) {
+			return nil, nil, invalidOptionsError("pdfBox %s cannot be applied: the crop box of page %d cannot be removed", box, index+1)
+		}
+		if !cloned {
+			output, cloned = slices.Clone(input), true
+		}
+		copy(output[offset:], "/CropOff")
+	}
+
+	return output, nil, nil
+}
+
+// trimRegion is where the trim box lies on the rendered crop box, which is
+// turned by the page rotation.
+func (p pdfPage) trimRegion() pdfRegion {
+	if p.trim == p.crop {
+		return wholePage
+	}
+
+	width, height := p.crop[2]-p.crop[0], p.crop[3]-p.crop[1]
+	left := (p.trim[0] - p.crop[0]) / width
+	right := (p.trim[2] - p.crop[0]) / width
+	top := (p.crop[3] - p.trim[3]) / height
+	bottom := (p.crop[3] - p.trim[1]) / height
+	switch p.rotate {
+	case 90:
+		return pdfRegion{1 - bottom, left, 1 - top, right}
+	case 180:
+		return pdfRegion{1 - right, 1 - bottom, 1 - left, 1 - top}
+	case 270:
+		return pdfRegion{top, 1 - right, bottom, 1 - left}
+	}
+
+	return pdfRegion{left, top, right, bottom}
+}
+
+// pageRegion is the region to keep of a 1-based page.
+func pageRegion(regions []pdfRegion, page int) pdfRegion {
+	if page < 1 || page > len(regions) {
+		return wholePage
+	}
+
+	return regions[page-1]
+}
+
+// extractRegion crops a rendered page to the region, keeping at least one
+// pixel in each direction.
+func extractRegion(image *vipsImage, region pdfRegion) error {
+	width, height := float64(image.width()), float64(image.height())
+	left := min(int(math.Round(region.left*width)), image.width()-1)
+	top := min(int(math.Round(region.top*height)), image.height()-1)
+	right := max(int(math.Round(region.right*width)), left+1)
+	bottom := max(int(math.Round(region.bottom*height)), top+1)
+
+	return image.extractArea(left, top, min(right, image.width())-left, min(bottom, image.height())-top)
+}
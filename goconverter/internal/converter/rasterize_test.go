@@ -0,0 +1,151 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"testing"
+)
+
+func TestValidateRasterizationRejectsInvalidOptions(t *testing.T) {
+	scale := 0.001
+	tests := []struct {
+		name   string
+		source string
+		r      Rasterization
+	}{
+		{name: "raster source", source: "png", r: Rasterization{DPI: intPointer(150)}},
+		{name: "dpi and scale", source: "svg", r: Rasterization{DPI: intPointer(150), Scale: new(float64)}},
+		{name: "size and dpi", source: "svg", r: Rasterization{DPI: intPointer(150), Width: 64}},
+		{name: "dpi range", source: "pdf", r: Rasterization{DPI: intPointer(0)}},
+		{name: "scale range", source: "pdf", r: Rasterization{Scale: &scale}},
+		{name: "width range", source: "svg", r: Rasterization{Width: maxTransformDimension + 1}},
+		{name: "background", source: "svg", r: Rasterization{Background: "white"}},
+		{name: "translucent background", source: "svg", r: Rasterization{Background: "#ffffff80"}},
+		{name: "box for svg", source: "svg", r: Rasterization{PDFBox: PDFBoxTrim}},
+		{name: "box", source: "pdf", r: Rasterization{PDFBox: "bleed"}},
+	}
+
+	for _, tt := range tests {
+		if err := validateRasterization(tt.source, tt.r); !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("%s: expected invalid options error, got %v", tt.name, err)
+		}
+	}
+
+	if err := validateRasterization("pdf", Rasterization{Width: 800, Background: "#fff", PDFBox: PDFBoxMedia}); err != nil {
+		t.Fatalf("expected rasterization options to be valid, got %v", err)
+	}
+}
+
+func TestRasterizationTargetScaleFitsRequestedSize(t *testing.T) {
+	tests := []struct {
+		r        Rasterization
+		expected float64
+	}{
+		{r: Rasterization{Width: 300}, expected: 3},
+		{r: Rasterization{Height: 100}, expected: 2},
+		{r: Rasterization{Width: 300, Height: 100}, expected: 2},
+	}
+
+	for _, tt := range tests {
+		if scale := tt.r.targetScale(100, 50); scale != tt.expected {
+			t.Fatalf("%+v: expected scale %g, got %g", tt.r, tt.expected, scale)
+		}
+	}
+}
+
+func TestSelectPDFBoxRenamesOnlyPageTreeKeys(t *testing.T) {
+	content := "/CropBox [0 0 1 1] /TrimBox"
+	input := mustBuildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 612 792] >>",
+		"<< /Type /Page /Parent 2 0 R /CropBox [9 9 603 783] /TrimBox [18 18 594 774] /Contents 4 0 R >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	)
+
+	media, regions, err := selectPDFBox(input, PDFBoxMedia)
+	if err != nil {
+		t.Fatalf("expected the media box to be applied, got error: %v", err)
+	}
+	if !bytes.Contains(media, []byte("/CropOff [9 9 603 783] /TrimBox")) || !bytes.Contains(media, []byte(content)) {
+		t.Fatalf("expected only the page crop box to be dropped, got %q", media)
+	}
+	if len(media) != len(input) || regions != nil {
+		t.Fatalf("expected offsets to be kept and no regions, got length %d for %d and %v", len(media), len(input), regions)
+	}
+	if !bytes.Contains(input, []byte("/CropBox [9 9 603 783]")) {
+		t.Fatal("expected the input to be left unchanged")
+	}
+
+	trim, regions, err := selectPDFBox(input, PDFBoxTrim)
+	if err != nil {
+		t.Fatalf("expected the trim box to be applied, got error: %v", err)
+	}
+	if !bytes.Equal(trim, input) {
+		t.Fatalf("expected the trim box to be cropped after rendering, got %q", trim)
+	}
+	if expected := (pdfRegion{9.0 / 594, 9.0 / 774, 585.0 / 594, 765.0 / 774}); len(regions) != 1 || regions[0] != expected {
+		t.Fatalf("expected region %v, got %v", expected, regions)
+	}
+}
+
+func TestSelectPDFBoxTrimFallsBackToCropBox(t *testing.T) {
+	input := mustBuildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /MediaBox [0 0 612 792] /CropBox [9 9 603 783] >>",
+		"<< /Type /Page /Parent 2 0 R /TrimBox [18 18 594 774] >>",
+		"<< /Type /Page /Parent 2 0 R >>",
+	)
+
+	_, regions, err := selectPDFBox(input, PDFBoxTrim)
+	if err != nil {
+		t.Fatalf("expected the trim box to be applied, got error: %v", err)
+	}
+	if len(regions) != 2 || regions[0].isWholePage() || !regions[1].isWholePage() {
+		t.Fatalf("expected only the first page to be cropped, got %v", regions)
+	}
+}
+
+func TestSelectPDFBoxRejectsBoxesItCannotApply(t *testing.T) {
+	objects := mustBuildPDFObjectStream(t, map[int]string{
+		2: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		3: "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /CropBox [9 9 603 783] /TrimBox [18 18 594 774] >>",
+	})
+	input := mustBuildPDF("<< /Type /Catalog /Pages 2 0 R >>", objects)
+
+	if _, regions, err := selectPDFBox(input, PDFBoxTrim); err != nil || len(regions) != 1 || regions[0].isWholePage() {
+		t.Fatalf("expected the trim box to be read from the object stream, got %v and %v", regions, err)
+	}
+	if _, _, err := selectPDFBox(input, PDFBoxMedia); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected a compressed crop box to be an invalid option, got %v", err)
+	}
+	if _, _, err := selectPDFBox([]byte("%PDF-1.7\n/TrimBox"), PDFBoxTrim); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected a PDF without page tree to be an invalid option, got %v", err)
+	}
+	if output, _, err := selectPDFBox([]byte("%PDF-1.7\n/TrimBox"), PDFBoxCrop); err != nil || !bytes.HasSuffix(output, []byte("/TrimBox")) {
+		t.Fatalf("expected the crop box to be left alone, got %q and %v", output, err)
+	}
+}
+
+func TestConvertSVGRasterizesAtRequestedWidth(t *testing.T) {
+	requireFormatPairSupport(t, "svg", "png")
+
+	c, _ := PlanConversion("svg", "png")
+	result, err := c.ConvertWithOptions(context.Background(), mustEncodeSVG(), Options{Rasterize: Rasterization{Width: 64, Background: "#00ff00"}})
+	if err != nil {
+		t.Fatalf("expected svg to rasterize, got error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(result.Output))
+	if err != nil {
+		t.Fatalf("expected png output, got error: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 64 || size.Y != 64 {
+		t.Fatalf("expected a 64x64 rendering, got %v", size)
+	}
+	if _, _, _, alpha := img.At(0, 0).RGBA(); alpha != 0xffff {
+		t.Fatalf("expected the background to flatten transparency, got alpha %d", alpha)
+	}
+}
@@ -1,87 +1,139 @@
 package converter
 
-import "github.com/h2non/bimg"
-
-var converterFactories = []func() Converter{
-	func() Converter { return NewAVIFToGIFConverter() },
-	func() Converter { return NewAVIFToHEIFConverter() },
-	func() Converter { return NewAVIFToJPEGConverter() },
-	func() Converter { return NewAVIFToPNGConverter() },
-	func() Converter { return NewAVIFToTIFFConverter() },
-	func() Converter { return NewAVIFToWEBPConverter() },
-	func() Converter { return NewGIFToAVIFConverter() },
-	func() Converter { return NewGIFToHEIFConverter() },
-	func() Converter { return NewGIFToJPEGConverter() },
-	func() Converter { return NewGIFToPNGConverter() },
-	func() Converter { return NewGIFToTIFFConverter() },
-	func() Converter { return NewGIFToWEBPConverter() },
-	func() Converter { return NewHEIFToAVIFConverter() },
-	func() Converter { return NewHEIFToGIFConverter() },
-	func() Converter { return NewHEIFToJPEGConverter() },
-	func() Converter { return NewHEIFToPNGConverter() },
-	func() Converter { return NewHEIFToTIFFConverter() },
-	func() Converter { return NewHEIFToWEBPConverter() },
-	func() Converter { return NewJPEGToAVIFConverter() },
-	func() Converter { return NewJPEGToGIFConverter() },
-	func() Converter { return NewJPEGToHEIFConverter() },
-	func() Converter { return NewJPEGToPNGConverter() },
-	func() Converter { return NewJPEGToTIFFConverter() },
-	func() Converter { return NewJPEGToWEBPConverter() },
-	func() Converter { return NewPNGToAVIFConverter() },
-	func() Converter { return NewPNGToGIFConverter() },
-	func() Converter { return NewPNGToHEIFConverter() },
-	func() Converter { return NewPNGToJPEGConverter() },
-	func() Converter { return NewPNGToTIFFConverter() },
-	func() Converter { return NewPNGToWEBPConverter() },
-	func() Converter { return NewTIFFToAVIFConverter() },
-	func() Converter { return NewTIFFToGIFConverter() },
-	func() Converter { return NewTIFFToHEIFConverter() },
-	func() Converter { return NewTIFFToJPEGConverter() },
-	func() Converter { return NewTIFFToPNGConverter() },
-	func() Converter { return NewTIFFToWEBPConverter() },
-	func() Converter { return NewWEBPToAVIFConverter() },
-	func() Converter { return NewWEBPToGIFConverter() },
-	func() Converter { return NewWEBPToHEIFConverter() },
-	func() Converter { return NewWEBPToJPEGConverter() },
-	func() Converter { return NewWEBPToPNGConverter() },
-	func() Converter { return NewWEBPToTIFFConverter() },
-	func() Converter { return NewMAGICKToAVIFConverter() },
-	func() Converter { return NewMAGICKToGIFConverter() },
-	func() Converter { return NewMAGICKToHEIFConverter() },
-	func() Converter { return NewMAGICKToJPEGConverter() },
-	func() Converter { return NewMAGICKToPNGConverter() },
-	func() Converter { return NewMAGICKToTIFFConverter() },
-	func() Converter { return NewMAGICKToWEBPConverter() },
-	func() Converter { return NewPDFToAVIFConverter() },
-	func() Converter { return NewPDFToGIFConverter() },
-	func() Converter { return NewPDFToHEIFConverter() },
-	func() Converter { return NewPDFToJPEGConverter() },
-	func() Converter { return NewPDFToPNGConverter() },
-	func() Converter { return NewPDFToTIFFConverter() },
-	func() Converter { return NewPDFToWEBPConverter() },
-	func() Converter { return NewSVGToAVIFConverter() },
-	func() Converter { return NewSVGToGIFConverter() },
-	func() Converter { return NewSVGToHEIFConverter() },
-	func() Converter { return NewSVGToJPEGConverter() },
-	func() Converter { return NewSVGToPNGConverter() },
-	func() Converter { return NewSVGToTIFFConverter() },
-	func() Converter { return NewSVGToWEBPConverter() },
+import (
+	"context"
+	"io"
+	"slices"
+
+	"github.com/h2non/bimg"
+)
+
+// pairConverter converts between two formats of the format table through the
+// shared libvips pipeline.
+type pairConverter struct {
+	source string
+	target string
+}
+
+var _ streamConverter = (*pairConverter)(nil)
+
+func newPairConverter(source string, target string) *pairConverter {
+	return &pairConverter{source: source, target: target}
+}
+
+func (c *pairConverter) SourceFormat() string {
+	return c.source
+}
+
+func (c *pairConverter) TargetFormat() string {
+	return c.target
+}
+
+func (c *pairConverter) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := c.ConvertWithOptions(ctx, input, Options{})
+	return result.Output, err
+}
+
+func (c *pairConverter) ConvertWithOptions(ctx context.Context, input []byte, options Options) (Result, error) {
+	return convertWithVips(ctx, input, c.source, c.target, options, nil)
+}
+
+func (c *pairConverter) convertTo(ctx context.Context, input []byte, options Options, w io.Writer) (Result, error) {
+	return convertWithVips(ctx, input, c.source, c.target, options, w)
+}
+
+// vipsLoadable and vipsSavable record which formats this libvips build
+// loads and saves. They are probed once at startup because probe files go
+// through the full loader.
+var (
+	vipsLoadable = probeVipsFormats(func(format Format) bool { return format.Load && vipsCanLoad(format) })
+	vipsSavable  = probeVipsFormats(func(format Format) bool { return format.CanSave() && vipsCanSave(format) })
+)
+
+func probeVipsFormats(supported func(Format) bool) map[string]bool {
+	output := make(map[string]bool, len(formats))
+	for _, format := range formats {
+		output[format.Name] = supported(format)
+	}
+
+	return output
+}
+
+func vipsCanLoad(format Format) bool {
+	switch {
+	case format.vipsOperation != "":
+		if !vipsOperationExists(format.vipsOperation + "load_buffer") {
+			return false
+		}
+	case !bimg.IsTypeNameSupported(format.typeName()):
+		return false
+	}
+	if format.loadProbe == nil {
+		return true
+	}
+
+	image, err := vipsLoadBuffer(format.loadProbe(), "")
+	if err != nil {
+		return false
+	}
+	image.close()
+
+	return true
+}
+
+func vipsCanSave(format Format) bool {
+	if format.vipsOperation != "" {
+		return vipsOperationExists(format.vipsOperation + "save_buffer")
+	}
+
+	return bimg.IsTypeNameSupportedSave(format.typeName())
+}
+
+func vipsConverters() []Converter {
+	var output []Converter
+	for _, source := range formats {
+		if !vipsLoadable[source.Name] {
+			continue
+		}
+
+		for _, target := range formats {
+			if target.Name != source.Name && vipsSavable[target.Name] {
+				output = append(output, newPairConverter(source.Name, target.Name))
+			}
+		}
+	}
+
+	return output
 }
 
 var converters = buildConverters()
 
+// buildConverters registers one backend chain per pair, in format-table
+// order, with the pair's backends sorted by descending priority.
 func buildConverters() []Converter {
-	output := make([]Converter, 0, len(converterFactories))
-	for _, factory := range converterFactories {
-		converter := factory()
-		if !bimg.IsTypeNameSupported(converter.SourceFormat()) {
-			continue
+	sorted := slices.Clone(backends)
+	slices.SortStableFunc(sorted, func(a Backend, b Backend) int {
+		return b.Priority - a.Priority
+	})
+
+	candidates := map[[2]string][]backendCandidate{}
+	for _, backend := range sorted {
+		for _, c := range backend.Converters() {
+			pair := [2]string{c.SourceFormat(), c.TargetFormat()}
+			candidates[pair] = append(candidates[pair], backendCandidate{backend: backend.Name, converter: c})
 		}
-		if !bimg.IsTypeNameSupportedSave(converter.TargetFormat()) {
-			continue
+	}
+
+	output := make([]Converter, 0, len(candidates))
+	for _, source := range formats {
+		for _, target := range formats {
+			pair := [2]string{source.Name, target.Name}
+			if len(candidates[pair]) > 0 {
+				output = append(output, &backendChain{source: source.Name, target: target.Name, candidates: candidates[pair]})
+			}
 		}
-		output = append(output, converter)
 	}
+
 	return output
 }
 
@@ -101,14 +153,32 @@ func FindConverter(source string, target string) (Converter, bool) {
 	return nil, false
 }
 
+// ConversionBackends lists the backends registered for each pair, in the
+// order they are tried.
+func ConversionBackends() map[string]map[string][]string {
+	output := map[string]map[string][]string{}
+	for _, c := range converters {
+		if output[c.SourceFormat()] == nil {
+			output[c.SourceFormat()] = map[string][]string{}
+		}
+		output[c.SourceFormat()][c.TargetFormat()] = c.(*backendChain).backends()
+	}
+
+	return output
+}
+
+// ConversionTargetsBySource lists every target reachable from each source,
+// directly or through a planned route.
 func ConversionTargetsBySource() map[string][]string {
-	registered := RegisteredConverters()
-	output := make(map[string][]string, len(registered))
+	routes := ConversionRoutes()
+	output := make(map[string][]string, len(routes))
 
-	for _, c := range registered {
-		source := c.SourceFormat()
-		target := c.TargetFormat()
-		output[source] = append(output[source], target)
+	for _, source := range formats {
+		for _, target := range formats {
+			if _, ok := routes[source.Name][target.Name]; ok {
+				output[source.Name] = append(output[source.Name], target.Name)
+			}
+		}
 	}
 
 	return output
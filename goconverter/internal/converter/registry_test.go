@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"context"
 	"reflect"
 	"slices"
 	"testing"
@@ -44,13 +45,64 @@ func TestFindConverter(t *testing.T) {
 	}
 }
 
+// TestRegisteredPairs runs the same conversion checks for every source and
+// target pair the format table produces.
+func TestRegisteredPairs(t *testing.T) {
+	for _, source := range formats {
+		if !source.Load {
+			continue
+		}
+
+		for _, target := range formats {
+			if target.Name == source.Name || !target.CanSave() {
+				continue
+			}
+
+			t.Run(source.Name+"_to_"+target.Name, func(t *testing.T) {
+				requireFormatPairSupport(t, source.Name, target.Name)
+
+				c, ok := FindConverter(source.Name, target.Name)
+				if !ok {
+					t.Fatalf("expected converter for %s -> %s", source.Name, target.Name)
+				}
+
+				output, err := c.Convert(context.Background(), mustEncodeFormat(t, source.Name))
+				if err != nil {
+					t.Fatalf("expected conversion to succeed, got error: %v", err)
+				}
+
+				assertOutputFormat(t, output, target.Name)
+			})
+
+			t.Run(source.Name+"_to_"+target.Name+"_rejects_invalid_input", func(t *testing.T) {
+				assertInvalidInputError(t, newPairConverter(source.Name, target.Name), source.Name, target.Name)
+			})
+		}
+	}
+}
+
+func TestFormatsHaveUniqueNamesAndAliases(t *testing.T) {
+	seen := map[string]string{}
+	for _, format := range formats {
+		for _, name := range append([]string{format.Name}, format.Aliases...) {
+			if owner, ok := seen[name]; ok {
+				t.Fatalf("name %q is used by both %s and %s", name, owner, format.Name)
+			}
+			seen[name] = format.Name
+		}
+		if format.MimeType == "" {
+			t.Fatalf("format %s has no MIME type", format.Name)
+		}
+	}
+}
+
 func expectedConversionTargetsBySource() map[string][]string {
-	loadSupportedSources := []string{"avif", "gif", "heif", "jpeg", "png", "tiff", "webp", "magick", "pdf", "svg"}
-	saveSupportedTargets := []string{"avif", "gif", "heif", "jpeg", "png", "tiff", "webp"}
+	loadSupportedSources := []string{"avif", "gif", "heif", "jpeg", "png", "tiff", "webp", "jxl", "jp2k", "bmp", "ico", "psd", "tga", "pcx", "xcf", "raw", "magick", "pdf", "svg"}
+	saveSupportedTargets := []string{"avif", "gif", "heif", "jpeg", "png", "tiff", "webp", "jxl", "jp2k"}
 
 	expected := make(map[string][]string)
 	for _, source := range loadSupportedSources {
-		if !bimg.IsTypeNameSupported(source) {
+		if !vipsLoadable[source] {
 			continue
 		}
 
@@ -58,7 +110,7 @@ func expectedConversionTargetsBySource() map[string][]string {
 			if source == target {
 				continue
 			}
-			if !bimg.IsTypeNameSupportedSave(target) {
+			if !vipsSavable[target] {
 				continue
 			}
 
@@ -66,6 +118,30 @@ func expectedConversionTargetsBySource() map[string][]string {
 		}
 	}
 
+	for source := range goCodecs {
+		for target := range goCodecs {
+			if source != target && goBackendSupports(source, target) && !slices.Contains(expected[source], target) {
+				expected[source] = append(expected[source], target)
+			}
+		}
+	}
+
+	// Targets only one backend writes, such as PDF from the Go backend, are
+	// reachable through another backend's output.
+	for grown := true; grown; {
+		grown = false
+		for source, targets := range expected {
+			for _, intermediate := range targets {
+				for _, target := range expected[intermediate] {
+					if target != source && !slices.Contains(expected[source], target) {
+						expected[source] = append(expected[source], target)
+						grown = true
+					}
+				}
+			}
+		}
+	}
+
 	return expected
 }
 
@@ -0,0 +1,181 @@
+package converter
+
+import "slices"
+
+const (
+	OptionTypeInteger = "integer"
+	OptionTypeBoolean = "boolean"
+	OptionTypeString  = "string"
+	OptionTypeNumber  = "number"
+)
+
+// OptionSchema describes one request option. Names are request field paths,
+// e.g. "options.quality" or "transform.width"; Default is nil when the option
+// has no effect until it is set.
+type OptionSchema struct {
+	Name    string
+	Type    string
+	Minimum *int
+	Maximum *int
+	Enum    []string
+	Default any
+}
+
+// PairSchema lists the options a conversion accepts. Lossy is set when the
+// default output discards information, either because a hop goes through a
+// lossy format or because the target encoder is lossy unless told otherwise.
+type PairSchema struct {
+	Route   []string
+	Lossy   bool
+	Options []OptionSchema
+}
+
+// SchemaFor builds the option schema for a pair from the same format table
+// and value lists that ValidateOptions checks against. Each hop offers the
+// options of its most capable backend, since the backend chain skips the
+// backends that reject a request; pairs served only by external tools or
+// wasm plugins take no options.
+func SchemaFor(sourceFormat string, targetFormat string) (PairSchema, bool) {
+	plan, ok := PlanConversion(sourceFormat, targetFormat)
+	if !ok {
+		return PairSchema{}, false
+	}
+
+	target, _ := LookupFormat(targetFormat)
+	first, last := plan.steps[0], plan.steps[len(plan.steps)-1]
+	var options []OptionSchema
+	switch {
+	case runsOnBackend(last, BackendVips):
+		options = encoderOptionSchemas(target.encoder)
+	case runsOnBackend(last, BackendGo):
+		options = encoderOptionSchemas(goCodecs[targetFormat].encoder)
+	}
+	switch {
+	case runsOnBackend(first, BackendVips):
+		options = append(options, vipsSourceOptionSchemas(sourceFormat)...)
+	case runsOnBackend(first, BackendGo):
+		options = append(options, goSourceOptionSchemas()...)
+	}
+
+	return PairSchema{Route: plan.Route(), Lossy: planIsLossy(plan, target), Options: options}, true
+}
+
+// vipsSourceOptionSchemas lists the options libvips applies while decoding
+// and processing the source image.
+func vipsSourceOptionSchemas(sourceFormat string) []OptionSchema {
+	options := transformOptionSchemas()
+	if SupportsRasterization(sourceFormat) {
+		options = append(options, rasterizeOptionSchemas(sourceFormat)...)
+	}
+	if SupportsPageSelection(sourceFormat) {
+		options = append(options, OptionSchema{Name: "pages", Type: OptionTypeString, Default: "1"})
+	}
+	if SupportsFrameSelection(sourceFormat) {
+		firstFrame := 1
+		options = append(options, OptionSchema{Name: "frame", Type: OptionTypeInteger, Minimum: &firstFrame})
+	}
+
+	return append(options,
+		OptionSchema{Name: "metadata", Type: OptionTypeString, Enum: slices.Clone(metadataPolicies), Default: MetadataKeep},
+		OptionSchema{Name: "colorSpace", Type: OptionTypeString, Enum: slices.Clone(colorSpaces), Default: ColorSpaceSRGB},
+		OptionSchema{Name: "iccProfile", Type: OptionTypeString, Enum: slices.Clone(iccProfiles), Default: ICCProfileEmbed},
+		OptionSchema{Name: "autoRotate", Type: OptionTypeBoolean, Default: true},
+	)
+}
+
+func encoderOptionSchemas(capabilities encoderCapabilities) []OptionSchema {
+	var output []OptionSchema
+	if capabilities.quality {
+		output = append(output, rangeOptionSchema("options.quality", qualityRange, capabilities.defaultQuality))
+	}
+	if capabilities.lossless {
+		output = append(output, OptionSchema{Name: "options.lossless", Type: OptionTypeBoolean, Default: false})
+	}
+	if capabilities.effort != nil {
+		output = append(output, rangeOptionSchema("options.effort", *capabilities.effort, capabilities.defaultEffort))
+	}
+	if capabilities.progressive {
+		output = append(output, OptionSchema{Name: "options.progressive", Type: OptionTypeBoolean, Default: false})
+	}
+	if capabilities.chromaSubsampling {
+		output = append(output, OptionSchema{
+			Name:    "options.chromaSubsampling",
+			Type:    OptionTypeString,
+			Enum:    slices.Clone(chromaSubsamplingModes),
+			Default: ChromaSubsamplingAuto,
+		})
+	}
+	if capabilities.compressionLevel {
+		output = append(output, rangeOptionSchema("options.compressionLevel", compressionLevelRange, defaultCompressionLevel))
+	}
+	if capabilities.pageLayout {
+		output = append(output,
+			rangeOptionSchema("options.dpi", dpiRange, defaultDPI),
+			OptionSchema{Name: "options.pageSize", Type: OptionTypeString, Enum: slices.Clone(pageSizes), Default: PageSizeAuto},
+		)
+	}
+
+	return output
+}
+
+func transformOptionSchemas() []OptionSchema {
+	dimensions := intRange{min: 0, max: maxTransformDimension}
+	return []OptionSchema{
+		{Name: "transform.width", Type: OptionTypeInteger, Minimum: &dimensions.min, Maximum: &dimensions.max},
+		{Name: "transform.height", Type: OptionTypeInteger, Minimum: &dimensions.min, Maximum: &dimensions.max},
+		{Name: "transform.fit", Type: OptionTypeString, Enum: slices.Clone(fitModes), Default: FitCover},
+		{Name: "transform.gravity", Type: OptionTypeString, Enum: slices.Clone(gravities), Default: GravityCentre},
+		{Name: "transform.withoutEnlargement", Type: OptionTypeBoolean, Default: false},
+		{Name: "transform.background", Type: OptionTypeString, Default: defaultBackground},
+	}
+}
+
+func rasterizeOptionSchemas(sourceFormat string) []OptionSchema {
+	dimensions := intRange{min: 1, max: maxTransformDimension}
+	options := []OptionSchema{
+		rangeOptionSchema("rasterize.dpi", dpiRange, defaultDPI),
+		{Name: "rasterize.scale", Type: OptionTypeNumber, Default: 1},
+		{Name: "rasterize.width", Type: OptionTypeInteger, Minimum: &dimensions.min, Maximum: &dimensions.max},
+		{Name: "rasterize.height", Type: OptionTypeInteger, Minimum: &dimensions.min, Maximum: &dimensions.max},
+		{Name: "rasterize.background", Type: OptionTypeString},
+	}
+	if sourceFormat == "pdf" {
+		options = append(options, OptionSchema{Name: "rasterize.pdfBox", Type: OptionTypeString, Enum: slices.Clone(pdfBoxes), Default: PDFBoxCrop})
+	}
+
+	return options
+}
+
+// goSourceOptionSchemas lists the options the Go backend applies while
+// decoding, with the only value it accepts for those it cannot vary.
+func goSourceOptionSchemas() []OptionSchema {
+	return []OptionSchema{
+		{Name: "metadata", Type: OptionTypeString, Enum: []string{MetadataStrip}, Default: MetadataStrip},
+		{Name: "colorSpace", Type: OptionTypeString, Enum: []string{ColorSpaceKeep}, Default: ColorSpaceKeep},
+		{Name: "iccProfile", Type: OptionTypeString, Enum: []string{ICCProfileOmit}, Default: ICCProfileOmit},
+		{Name: "autoRotate", Type: OptionTypeBoolean, Default: true},
+	}
+}
+
+func rangeOptionSchema(name string, allowed intRange, defaultValue int) OptionSchema {
+	return OptionSchema{
+		Name:    name,
+		Type:    OptionTypeInteger,
+		Minimum: &allowed.min,
+		Maximum: &allowed.max,
+		Default: defaultValue,
+	}
+}
+
+func planIsLossy(plan Plan, target Format) bool {
+	if target.encoder.quality {
+		return true
+	}
+	for _, format := range plan.Route()[1:] {
+		if details, ok := LookupFormat(format); ok && details.Lossy {
+			return true
+		}
+	}
+
+	return false
+}
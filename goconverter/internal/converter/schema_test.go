@@ -0,0 +1,159 @@
+package converter
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSchemaForListsTargetEncoderOptions(t *testing.T) {
+	requireFormatPairSupport(t, "png", "jpeg")
+
+	schema, ok := SchemaFor("png", "jpeg")
+	if !ok {
+		t.Fatal("expected a schema for png to jpeg")
+	}
+	if !schema.Lossy {
+		t.Fatal("expected png to jpeg to be lossy")
+	}
+
+	quality := mustFindOptionSchema(t, schema, "options.quality")
+	if quality.Type != OptionTypeInteger || *quality.Minimum != qualityRange.min || *quality.Maximum != qualityRange.max || quality.Default != 75 {
+		t.Fatalf("unexpected quality schema: %+v", quality)
+	}
+	chroma := mustFindOptionSchema(t, schema, "options.chromaSubsampling")
+	if !slices.Equal(chroma.Enum, chromaSubsamplingModes) {
+		t.Fatalf("expected chromaSubsampling values %v, got %v", chromaSubsamplingModes, chroma.Enum)
+	}
+
+	for _, name := range []string{"options.lossless", "options.effort", "options.compressionLevel", "pages", "frame"} {
+		if _, ok := findOptionSchema(schema, name); ok {
+			t.Fatalf("did not expect %s for png to jpeg", name)
+		}
+	}
+}
+
+func TestSchemaForFollowsSourceCapabilities(t *testing.T) {
+	requireFormatPairSupport(t, "tiff", "png")
+
+	schema, ok := SchemaFor("tiff", "png")
+	if !ok {
+		t.Fatal("expected a schema for tiff to png")
+	}
+	if schema.Lossy {
+		t.Fatal("expected tiff to png to be lossless")
+	}
+	mustFindOptionSchema(t, schema, "pages")
+	level := mustFindOptionSchema(t, schema, "options.compressionLevel")
+	if level.Default != defaultCompressionLevel {
+		t.Fatalf("expected compressionLevel default %d, got %v", defaultCompressionLevel, level.Default)
+	}
+	if _, ok := findOptionSchema(schema, "options.quality"); ok {
+		t.Fatal("did not expect quality for png output")
+	}
+}
+
+func TestSchemaForListsPageLayoutForPDFOutput(t *testing.T) {
+	schema, ok := SchemaFor("png", "pdf")
+	if !ok {
+		t.Fatal("expected a schema for png to pdf")
+	}
+	if schema.Lossy {
+		t.Fatal("expected png to pdf to be lossless")
+	}
+
+	dpi := mustFindOptionSchema(t, schema, "options.dpi")
+	if *dpi.Minimum != dpiRange.min || *dpi.Maximum != dpiRange.max || dpi.Default != defaultDPI {
+		t.Fatalf("unexpected dpi schema: %+v", dpi)
+	}
+	pageSize := mustFindOptionSchema(t, schema, "options.pageSize")
+	if !slices.Equal(pageSize.Enum, pageSizes) || pageSize.Default != PageSizeAuto {
+		t.Fatalf("unexpected pageSize schema: %+v", pageSize)
+	}
+}
+
+func TestSchemaForListsRasterizationForVectorSources(t *testing.T) {
+	requireFormatPairSupport(t, "pdf", "png")
+
+	schema, ok := SchemaFor("pdf", "png")
+	if !ok {
+		t.Fatal("expected a schema for pdf to png")
+	}
+	dpi := mustFindOptionSchema(t, schema, "rasterize.dpi")
+	if dpi.Default != defaultDPI {
+		t.Fatalf("unexpected rasterize dpi schema: %+v", dpi)
+	}
+	box := mustFindOptionSchema(t, schema, "rasterize.pdfBox")
+	if !slices.Equal(box.Enum, pdfBoxes) || box.Default != PDFBoxCrop {
+		t.Fatalf("unexpected pdfBox schema: %+v", box)
+	}
+}
+
+func TestSchemaForAcceptedValuesPassValidation(t *testing.T) {
+	for _, route := range [][2]string{{"png", "webp"}, {"gif", "avif"}, {"pdf", "jpeg"}, {"png", "pdf"}} {
+		schema, ok := SchemaFor(route[0], route[1])
+		if !ok {
+			continue
+		}
+
+		for _, option := range schema.Options {
+			for _, value := range option.Enum {
+				options := optionsWithSchemaValue(option.Name, value)
+				if err := ValidateOptions(route[0], route[1], options); err != nil {
+					t.Fatalf("%s to %s: expected %s=%s to be valid, got %v", route[0], route[1], option.Name, value, err)
+				}
+			}
+		}
+	}
+}
+
+func TestSchemaForRejectsUnsupportedPair(t *testing.T) {
+	if _, ok := SchemaFor("png", "svg"); ok {
+		t.Fatal("did not expect a schema for svg output")
+	}
+}
+
+// optionsWithSchemaValue sets one enum option with the prerequisites the
+// validator requires for it.
+func optionsWithSchemaValue(name string, value string) Options {
+	switch name {
+	case "options.chromaSubsampling":
+		return Options{ChromaSubsampling: value}
+	case "options.pageSize":
+		return Options{PageSize: value}
+	case "rasterize.pdfBox":
+		return Options{Rasterize: Rasterization{PDFBox: value}}
+	case "transform.fit":
+		return Options{Transform: Transform{Width: 10, Fit: value}}
+	case "transform.gravity":
+		return Options{Transform: Transform{Width: 10, Fit: FitCover, Gravity: value}}
+	case "metadata":
+		return Options{Metadata: value}
+	case "colorSpace":
+		return Options{ColorSpace: value}
+	case "iccProfile":
+		return Options{ICCProfile: value}
+	default:
+		return Options{}
+	}
+}
+
+func findOptionSchema(schema PairSchema, name string) (OptionSchema, bool) {
+	for _, option := range schema.Options {
+		if option.Name == name {
+			return option, true
+		}
+	}
+
+	return OptionSchema{}, false
+}
+
+func mustFindOptionSchema(t *testing.T, schema PairSchema, name string) OptionSchema {
+	t.Helper()
+
+	option, ok := findOptionSchema(schema, name)
+	if !ok {
+		t.Fatalf("expected option %s in schema", name)
+	}
+
+	return option
+}
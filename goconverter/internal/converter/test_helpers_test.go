@@ -2,12 +2,15 @@ package converter
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
+	"image/gif"
 	"image/jpeg"
 	"image/png"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/h2non/bimg"
+	"golang.org/x/image/tiff"
 )
 
 var (
@@ -30,10 +34,10 @@ var (
 func requireFormatPairSupport(t *testing.T, source string, target string) {
 	t.Helper()
 
-	if !bimg.IsTypeNameSupported(source) {
+	if !vipsLoadable[source] {
 		t.Skipf("source format %q is not load-supported by current libvips build", source)
 	}
-	if !bimg.IsTypeNameSupportedSave(target) {
+	if !vipsSavable[target] {
 		t.Skipf("target format %q is not save-supported by current libvips build", target)
 	}
 }
@@ -50,10 +54,18 @@ func mustEncodeFormat(t *testing.T, format string) []byte {
 		return mustEncodeWithBIMG(t, "heif", bimg.HEIF)
 	case "jpeg":
 		return mustEncodeJPEG(t)
-	case "magick":
-		// Converter selection is request-driven; conversion does not validate
-		// source bytes against SourceFormat(). Use a stable decodable fixture.
-		return mustEncodePNG(t)
+	case "jxl", "jp2k":
+		result, err := convertWithVips(context.Background(), mustEncodePNG(t), "png", format, Options{}, nil)
+		if err != nil {
+			t.Fatalf("failed to encode %s fixture: %v", format, err)
+		}
+		return result.Output
+	case "bmp", "ico", "psd", "tga", "pcx", "xcf":
+		details, _ := LookupFormat(format)
+		return details.loadProbe()
+	case "raw":
+		t.Skip("no camera raw fixture")
+		return nil
 	case "pdf":
 		return mustReadBIMGTestdataFile(t, "test.pdf")
 	case "png":
@@ -120,8 +132,8 @@ func mustEncodeSVG() []byte {
 func assertOutputFormat(t *testing.T, output []byte, expectedFormat string) {
 	t.Helper()
 
-	if got := bimg.DetermineImageTypeName(output); got != expectedFormat {
-		t.Fatalf("expected %s output, got %q", expectedFormat, got)
+	if detection, _ := DetectFormat(output); detection.Format != expectedFormat {
+		t.Fatalf("expected %s output, got %q", expectedFormat, detection.Format)
 	}
 
 	size, err := bimg.Size(output)
@@ -136,7 +148,7 @@ func assertOutputFormat(t *testing.T, output []byte, expectedFormat string) {
 func assertInvalidInputError(t *testing.T, c Converter, source string, target string) {
 	t.Helper()
 
-	_, err := c.Convert([]byte("invalid-input-data"))
+	_, err := c.Convert(context.Background(), []byte("invalid-input-data"))
 	if err == nil {
 		t.Fatalf("expected conversion to fail for invalid input data")
 	}
@@ -147,6 +159,247 @@ func assertInvalidInputError(t *testing.T, c Converter, source string, target st
 	}
 }
 
+func intPointer(value int) *int {
+	return &value
+}
+
+func mustEncodeGradientPNG(t *testing.T, width int, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / width), G: uint8(y * 255 / height), B: uint8((x * y) % 256), A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode gradient png fixture: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// mustEncodeMultiPageTIFF writes an uncompressed RGB TIFF with one 2x2 page
+// per image file directory.
+func mustEncodeMultiPageTIFF(t *testing.T, pages int) []byte {
+	t.Helper()
+
+	pixels := make([][]byte, 0, pages)
+	for page := 0; page < pages; page++ {
+		shade := byte(page * 80)
+		pixels = append(pixels, bytes.Repeat([]byte{shade, 255 - shade, 128}, 4))
+	}
+
+	return encodeUncompressedTIFF(tiffPhotometricRGB, 3, pixels)
+}
+
+// mustEncodeCMYKTIFF writes an untagged 2x2 CMYK TIFF filled with one ink mix.
+func mustEncodeCMYKTIFF(t *testing.T, cyan byte, magenta byte, yellow byte, black byte) []byte {
+	t.Helper()
+
+	pixels := bytes.Repeat([]byte{cyan, magenta, yellow, black}, 4)
+	return encodeUncompressedTIFF(tiffPhotometricSeparated, 4, [][]byte{pixels})
+}
+
+const tiffPhotometricSeparated = 5
+
+// encodeUncompressedTIFF writes a little-endian TIFF with one 2x2, 8-bit
+// image file directory per entry in pages.
+func encodeUncompressedTIFF(photometric uint16, samples int, pages [][]byte) []byte {
+	const entryCount = 10
+	ifdSize := 2 + entryCount*12 + 4
+	bitsSize := 2 * samples
+	pixelBytes := 2 * 2 * samples
+	pageSize := ifdSize + bitsSize + pixelBytes
+
+	var buf bytes.Buffer
+	buf.Write([]byte{'I', 'I', 42, 0})
+	writeUint32LE(&buf, 8)
+
+	for page, pixels := range pages {
+		ifdOffset := uint32(8 + page*pageSize)
+		bitsOffset := ifdOffset + uint32(ifdSize)
+		pixelsOffset := bitsOffset + uint32(bitsSize)
+		nextIFD := uint32(0)
+		if page < len(pages)-1 {
+			nextIFD = ifdOffset + uint32(pageSize)
+		}
+
+		writeUint16LE(&buf, entryCount)
+		writeTIFFEntry(&buf, 256, 3, 1, 2)
+		writeTIFFEntry(&buf, 257, 3, 1, 2)
+		writeTIFFEntry(&buf, 258, 3, uint32(samples), bitsOffset)
+		writeTIFFEntry(&buf, 259, 3, 1, 1)
+		writeTIFFEntry(&buf, 262, 3, 1, uint32(photometric))
+		writeTIFFEntry(&buf, 273, 4, 1, pixelsOffset)
+		writeTIFFEntry(&buf, 277, 3, 1, uint32(samples))
+		writeTIFFEntry(&buf, 278, 3, 1, 2)
+		writeTIFFEntry(&buf, 279, 4, 1, uint32(pixelBytes))
+		writeTIFFEntry(&buf, 284, 3, 1, 1)
+		writeUint32LE(&buf, nextIFD)
+
+		for i := 0; i < samples; i++ {
+			writeUint16LE(&buf, 8)
+		}
+		buf.Write(pixels)
+	}
+
+	return buf.Bytes()
+}
+
+func mustEncodeAnimatedGIF(t *testing.T, frames int) []byte {
+	t.Helper()
+
+	palette := color.Palette{color.Black, color.White, color.RGBA{R: 255, A: 255}}
+	animation := &gif.GIF{LoopCount: 0}
+	for index := 0; index < frames; index++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+		frame.SetColorIndex(index%4, index%4, uint8(index%len(palette)))
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, 10*(index+1))
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, animation); err != nil {
+		t.Fatalf("failed to encode animated GIF: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func writeTIFFEntry(buf *bytes.Buffer, tag uint16, fieldType uint16, count uint32, value uint32) {
+	writeUint16LE(buf, tag)
+	writeUint16LE(buf, fieldType)
+	writeUint32LE(buf, count)
+	writeUint32LE(buf, value)
+}
+
+func writeUint16LE(buf *bytes.Buffer, value uint16) {
+	buf.Write([]byte{byte(value), byte(value >> 8)})
+}
+
+func writeUint32LE(buf *bytes.Buffer, value uint32) {
+	buf.Write([]byte{byte(value), byte(value >> 8), byte(value >> 16), byte(value >> 24)})
+}
+
+// mustEncodeP3PNG tags a solid sRGB color with a Display P3 profile by letting
+// libvips convert it, so the stored values differ from the sRGB ones.
+func mustEncodeP3PNG(t *testing.T, fill color.RGBA) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetRGBA(x, y, fill)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+
+	fixture, err := vipsLoadBuffer(buf.Bytes(), "")
+	if err != nil {
+		t.Fatalf("failed to load PNG: %v", err)
+	}
+	defer fixture.close()
+	if err := fixture.iccTransform("p3", "srgb"); err != nil {
+		t.Fatalf("failed to convert fixture to Display P3: %v", err)
+	}
+
+	output, err := fixture.saveBuffer(context.Background(), ".png")
+	if err != nil {
+		t.Fatalf("failed to save P3 fixture: %v", err)
+	}
+
+	return output
+}
+
+func mustDecodeJPEGPixel(t *testing.T, input []byte) color.RGBA {
+	t.Helper()
+
+	decoded, err := jpeg.Decode(bytes.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to decode JPEG: %v", err)
+	}
+
+	return color.RGBAModel.Convert(decoded.At(1, 1)).(color.RGBA)
+}
+
+func assertColorNear(t *testing.T, actual color.RGBA, expected color.RGBA, tolerance int) {
+	t.Helper()
+
+	if colorDistance(actual, expected) > tolerance {
+		t.Fatalf("expected color near %+v, got %+v", expected, actual)
+	}
+}
+
+func colorDistance(a color.RGBA, b color.RGBA) int {
+	return max(absInt(int(a.R)-int(b.R)), absInt(int(a.G)-int(b.G)), absInt(int(a.B)-int(b.B)))
+}
+
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+func mustReadFields(t *testing.T, input []byte) []string {
+	t.Helper()
+
+	image, err := vipsLoadBuffer(input, "")
+	if err != nil {
+		t.Fatalf("failed to load output: %v", err)
+	}
+	defer image.close()
+
+	return image.fields()
+}
+
+func mustReadOrientation(t *testing.T, input []byte) int {
+	t.Helper()
+
+	image, err := vipsLoadBuffer(input, "")
+	if err != nil {
+		t.Fatalf("failed to load output: %v", err)
+	}
+	defer image.close()
+
+	return image.orientation()
+}
+
+func mustEncodeGoFixture(t *testing.T, encode func(io.Writer, image.Image) error) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := encode(&buf, fixtureImage()); err != nil {
+		t.Fatalf("failed to encode fixture: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func encodeUncompressedGoTIFF(w io.Writer, img image.Image) error {
+	return tiff.Encode(w, img, nil)
+}
+
+// writeToolScript writes an executable shell script that stands in for an
+// external conversion tool.
+func writeToolScript(t *testing.T, body string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tool.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatalf("failed to write tool script: %v", err)
+	}
+
+	return path
+}
+
 func fixtureImage() image.Image {
 	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
 	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 255, G: 0, B: 0, A: 255}), image.Point{}, draw.Src)
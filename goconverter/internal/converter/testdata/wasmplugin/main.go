@@ -0,0 +1,40 @@
+// Command wasmplugin is a test converter plugin built for GOOS=wasip1. It
+// declares svg to pdf and uppercases its input, except that "loop" spins
+// forever and "alloc" grows memory until the runtime refuses.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "pairs":
+		fmt.Println("svg pdf")
+	case "convert":
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			os.Exit(1)
+		}
+		switch string(input) {
+		case "loop":
+			for {
+			}
+		case "alloc":
+			var chunks [][]byte
+			for {
+				chunks = append(chunks, make([]byte, 1<<20))
+			}
+		}
+		os.Stdout.Write(bytes.ToUpper(input))
+	default:
+		os.Exit(2)
+	}
+}
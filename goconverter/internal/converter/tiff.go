@@ -0,0 +1,122 @@
+package converter
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"io"
+)
+
+const (
+	tiffTypeShort    = 3
+	tiffTypeLong     = 4
+	tiffTypeRational = 5
+
+	tiffCompressionDeflate = 8
+	tiffPhotometricGray    = 1
+	tiffPhotometricRGB     = 2
+	tiffResolutionInch     = 2
+	tiffUnassociatedAlpha  = 2
+)
+
+type tiffEntry struct {
+	tag       uint16
+	fieldType uint16
+	count     uint32
+	value     uint32
+}
+
+// writeTIFF stores every image as one Deflate compressed page of a
+// little-endian TIFF file, with a single strip per page. Gray images stay
+// gray; transparent ones keep an unassociated alpha channel.
+func writeTIFF(w io.Writer, images []image.Image, dpi int) error {
+	var output bytes.Buffer
+	output.WriteString("II*\x00")
+	nextPointer := output.Len()
+	output.Write(make([]byte, 4))
+
+	for _, img := range images {
+		size := img.Bounds().Size()
+		pixels, alpha, colorSpace := pdfImageData(img)
+		samples, photometric := 3, uint16(tiffPhotometricRGB)
+		if colorSpace == "/DeviceGray" {
+			samples, photometric = 1, tiffPhotometricGray
+		}
+		if alpha != nil {
+			pixels = interleaveAlpha(pixels, alpha, samples)
+			samples++
+		}
+
+		var strip bytes.Buffer
+		writer := zlib.NewWriter(&strip)
+		if _, err := writer.Write(pixels); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+
+		stripOffset := uint32(output.Len())
+		output.Write(strip.Bytes())
+		alignTIFF(&output)
+
+		bitsPerSample := tiffEntry{tag: 258, fieldType: tiffTypeShort, count: uint32(samples), value: 8}
+		if samples > 1 {
+			bitsPerSample.value = uint32(output.Len())
+			for range samples {
+				binary.Write(&output, binary.LittleEndian, uint16(8))
+			}
+		}
+		resolution := uint32(output.Len())
+		binary.Write(&output, binary.LittleEndian, []uint32{uint32(dpi), 1})
+
+		entries := []tiffEntry{
+			{tag: 256, fieldType: tiffTypeLong, count: 1, value: uint32(size.X)},
+			{tag: 257, fieldType: tiffTypeLong, count: 1, value: uint32(size.Y)},
+			bitsPerSample,
+			{tag: 259, fieldType: tiffTypeShort, count: 1, value: tiffCompressionDeflate},
+			{tag: 262, fieldType: tiffTypeShort, count: 1, value: uint32(photometric)},
+			{tag: 273, fieldType: tiffTypeLong, count: 1, value: stripOffset},
+			{tag: 277, fieldType: tiffTypeShort, count: 1, value: uint32(samples)},
+			{tag: 278, fieldType: tiffTypeLong, count: 1, value: uint32(size.Y)},
+			{tag: 279, fieldType: tiffTypeLong, count: 1, value: uint32(strip.Len())},
+			{tag: 282, fieldType: tiffTypeRational, count: 1, value: resolution},
+			{tag: 283, fieldType: tiffTypeRational, count: 1, value: resolution},
+			{tag: 284, fieldType: tiffTypeShort, count: 1, value: 1},
+			{tag: 296, fieldType: tiffTypeShort, count: 1, value: tiffResolutionInch},
+		}
+		if alpha != nil {
+			entries = append(entries, tiffEntry{tag: 338, fieldType: tiffTypeShort, count: 1, value: tiffUnassociatedAlpha})
+		}
+
+		binary.LittleEndian.PutUint32(output.Bytes()[nextPointer:], uint32(output.Len()))
+		binary.Write(&output, binary.LittleEndian, uint16(len(entries)))
+		for _, entry := range entries {
+			binary.Write(&output, binary.LittleEndian, entry)
+		}
+		nextPointer = output.Len()
+		output.Write(make([]byte, 4))
+	}
+
+	_, err := w.Write(output.Bytes())
+	return err
+}
+
+func interleaveAlpha(pixels []byte, alpha []byte, channels int) []byte {
+	output := make([]byte, 0, len(pixels)+len(alpha))
+	for index, value := range alpha {
+		output = append(output, pixels[index*channels:(index+1)*channels]...)
+		output = append(output, value)
+	}
+
+	return output
+}
+
+// alignTIFF pads to a word boundary, which TIFF requires for IFDs and
+// values stored by offset.
+func alignTIFF(output *bytes.Buffer) {
+	if output.Len()%2 != 0 {
+		output.WriteByte(0)
+	}
+}
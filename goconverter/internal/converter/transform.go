@@ -0,0 +1,317 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	FitContain = "contain"
+	FitCover   = "cover"
+	FitFill    = "fill"
+	FitInside  = "inside"
+	FitOutside = "outside"
+
+	GravityCentre    = "centre"
+	GravityNorth     = "north"
+	GravityNorthEast = "northeast"
+	GravityEast      = "east"
+	GravitySouthEast = "southeast"
+	GravitySouth     = "south"
+	GravitySouthWest = "southwest"
+	GravityWest      = "west"
+	GravityNorthWest = "northwest"
+	GravityAttention = "attention"
+	GravityEntropy   = "entropy"
+)
+
+var fitModes = []string{FitContain, FitCover, FitFill, FitInside, FitOutside}
+
+var gravities = []string{
+	GravityCentre,
+	GravityNorth,
+	GravityNorthEast,
+	GravityEast,
+	GravitySouthEast,
+	GravitySouth,
+	GravitySouthWest,
+	GravityWest,
+	GravityNorthWest,
+	GravityAttention,
+	GravityEntropy,
+}
+
+const maxTransformDimension = 16384
+
+const defaultBackground = "#000000"
+
+// Transform resizes the decoded image before it is encoded. Fit names follow
+// the CSS object-fit vocabulary: cover crops, contain pads, fill stretches,
+// inside and outside only scale.
+type Transform struct {
+	Width              int
+	Height             int
+	Fit                string
+	Gravity            string
+	WithoutEnlargement bool
+	Background         string
+}
+
+// Geometry reports the image dimensions seen at each transform step and the
+// fit and gravity that were applied, with defaults resolved.
+type Geometry struct {
+	SourceWidth  int
+	SourceHeight int
+	ScaledWidth  int
+	ScaledHeight int
+	Width        int
+	Height       int
+	Fit          string
+	Gravity      string
+}
+
+type rgbaColor struct {
+	r uint8
+	g uint8
+	b uint8
+	a uint8
+}
+
+// gravityAnchors places a box on each axis: 0 is left/top, 1 is right/bottom.
+var gravityAnchors = map[string][2]float64{
+	GravityCentre:    {0.5, 0.5},
+	GravityNorth:     {0.5, 0},
+	GravityNorthEast: {1, 0},
+	GravityEast:      {1, 0.5},
+	GravitySouthEast: {1, 1},
+	GravitySouth:     {0.5, 1},
+	GravitySouthWest: {0, 1},
+	GravityWest:      {0, 0.5},
+	GravityNorthWest: {0, 0},
+}
+
+func (t Transform) IsZero() bool {
+	return t == Transform{}
+}
+
+func (t Transform) fit() string {
+	if t.Fit == "" {
+		return FitCover
+	}
+
+	return t.Fit
+}
+
+func (t Transform) gravity() string {
+	switch t.Gravity {
+	case "", "center":
+		return GravityCentre
+	default:
+		return t.Gravity
+	}
+}
+
+func validateTransform(t Transform) error {
+	if t.IsZero() {
+		return nil
+	}
+
+	if t.Width < 0 || t.Height < 0 {
+		return invalidOptionsError("transform width and height must not be negative")
+	}
+	if t.Width > maxTransformDimension || t.Height > maxTransformDimension {
+		return invalidOptionsError("transform width and height must not exceed %d", maxTransformDimension)
+	}
+	if t.Width == 0 && t.Height == 0 {
+		return invalidOptionsError("transform requires width or height")
+	}
+
+	if !slices.Contains(fitModes, t.fit()) {
+		return invalidOptionsError("fit must be one of %s", strings.Join(fitModes, ", "))
+	}
+
+	gravity := t.gravity()
+	if !slices.Contains(gravities, gravity) {
+		return invalidOptionsError("gravity %q is not supported", t.Gravity)
+	}
+	if (gravity == GravityAttention || gravity == GravityEntropy) && t.fit() != FitCover {
+		return invalidOptionsError("gravity %s requires fit %s", gravity, FitCover)
+	}
+
+	if t.Background != "" {
+		if t.fit() != FitContain {
+			return invalidOptionsError("background requires fit %s", FitContain)
+		}
+		if _, err := parseColor(t.Background); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scaledSize returns the size the source is resized to before any crop or
+// padding is applied.
+func (t Transform) scaledSize(sourceWidth int, sourceHeight int) (int, int) {
+	xScale := float64(t.Width) / float64(sourceWidth)
+	yScale := float64(t.Height) / float64(sourceHeight)
+
+	switch {
+	case t.Width == 0:
+		xScale = yScale
+	case t.Height == 0:
+		yScale = xScale
+	default:
+		switch t.fit() {
+		case FitCover, FitOutside:
+			xScale = math.Max(xScale, yScale)
+			yScale = xScale
+		case FitContain, FitInside:
+			xScale = math.Min(xScale, yScale)
+			yScale = xScale
+		}
+	}
+
+	if t.WithoutEnlargement {
+		xScale = math.Min(xScale, 1)
+		yScale = math.Min(yScale, 1)
+	}
+
+	return scaleDimension(sourceWidth, xScale), scaleDimension(sourceHeight, yScale)
+}
+
+// frameSize returns the final canvas size for fits that crop or pad, given the
+// actual scaled size.
+func (t Transform) frameSize(scaledWidth int, scaledHeight int) (int, int) {
+	width := t.Width
+	if width == 0 {
+		width = scaledWidth
+	}
+	height := t.Height
+	if height == 0 {
+		height = scaledHeight
+	}
+
+	if t.fit() == FitCover {
+		return min(width, scaledWidth), min(height, scaledHeight)
+	}
+
+	return max(width, scaledWidth), max(height, scaledHeight)
+}
+
+func scaleDimension(size int, scale float64) int {
+	return max(1, int(math.Round(float64(size)*scale)))
+}
+
+// gravityOffset positions an inner box of the given size inside an outer box.
+func gravityOffset(gravity string, outerWidth int, outerHeight int, innerWidth int, innerHeight int) (int, int) {
+	anchor, ok := gravityAnchors[gravity]
+	if !ok {
+		anchor = gravityAnchors[GravityCentre]
+	}
+
+	left := int(float64(outerWidth-innerWidth) * anchor[0])
+	top := int(float64(outerHeight-innerHeight) * anchor[1])
+	return left, top
+}
+
+func parseColor(value string) (rgbaColor, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if len(hex) == 3 || len(hex) == 4 {
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return rgbaColor{}, invalidOptionsError("color %q must be #rgb, #rgba, #rrggbb or #rrggbbaa", value)
+	}
+
+	parsed, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgbaColor{}, invalidOptionsError("color %q must be #rgb, #rgba, #rrggbb or #rrggbbaa", value)
+	}
+
+	return rgbaColor{
+		r: uint8(parsed >> 24),
+		g: uint8(parsed >> 16),
+		b: uint8(parsed >> 8),
+		a: uint8(parsed),
+	}, nil
+}
+
+func applyTransform(image *vipsImage, t Transform) (Geometry, error) {
+	geometry := Geometry{SourceWidth: image.width(), SourceHeight: image.height()}
+	geometry.ScaledWidth, geometry.ScaledHeight = geometry.SourceWidth, geometry.SourceHeight
+	geometry.Width, geometry.Height = geometry.SourceWidth, geometry.SourceHeight
+	if t.IsZero() {
+		return geometry, nil
+	}
+	geometry.Fit, geometry.Gravity = t.fit(), t.gravity()
+
+	scaledWidth, scaledHeight := t.scaledSize(geometry.SourceWidth, geometry.SourceHeight)
+	if scaledWidth != geometry.SourceWidth || scaledHeight != geometry.SourceHeight {
+		xScale := float64(scaledWidth) / float64(geometry.SourceWidth)
+		yScale := float64(scaledHeight) / float64(geometry.SourceHeight)
+		if err := image.resize(xScale, yScale); err != nil {
+			return Geometry{}, fmt.Errorf("resize: %w", err)
+		}
+	}
+	geometry.ScaledWidth, geometry.ScaledHeight = image.width(), image.height()
+
+	frameWidth, frameHeight := t.frameSize(geometry.ScaledWidth, geometry.ScaledHeight)
+	switch t.fit() {
+	case FitCover:
+		if err := cropToFrame(image, t.gravity(), frameWidth, frameHeight); err != nil {
+			return Geometry{}, fmt.Errorf("crop: %w", err)
+		}
+	case FitContain:
+		if err := padToFrame(image, t, frameWidth, frameHeight); err != nil {
+			return Geometry{}, fmt.Errorf("pad: %w", err)
+		}
+	}
+
+	geometry.Width, geometry.Height = image.width(), image.height()
+	return geometry, nil
+}
+
+func cropToFrame(image *vipsImage, gravity string, width int, height int) error {
+	if width == image.width() && height == image.height() {
+		return nil
+	}
+
+	switch gravity {
+	case GravityAttention:
+		return image.smartCrop(width, height, vipsInterestingAttention)
+	case GravityEntropy:
+		return image.smartCrop(width, height, vipsInterestingEntropy)
+	}
+
+	left, top := gravityOffset(gravity, image.width(), image.height(), width, height)
+	return image.extractArea(left, top, width, height)
+}
+
+func padToFrame(image *vipsImage, t Transform, width int, height int) error {
+	if width == image.width() && height == image.height() {
+		return nil
+	}
+
+	background := t.Background
+	if background == "" {
+		background = defaultBackground
+	}
+	color, err := parseColor(background)
+	if err != nil {
+		return err
+	}
+
+	left, top := gravityOffset(t.gravity(), width, height, image.width(), image.height())
+	return image.embed(left, top, width, height, color)
+}
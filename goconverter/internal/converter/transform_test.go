@@ -0,0 +1,163 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/h2non/bimg"
+)
+
+func TestTransformScaledSize(t *testing.T) {
+	cases := []struct {
+		name           string
+		transform      Transform
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{name: "width only keeps aspect", transform: Transform{Width: 100}, expectedWidth: 100, expectedHeight: 50},
+		{name: "height only keeps aspect", transform: Transform{Height: 25}, expectedWidth: 50, expectedHeight: 25},
+		{name: "cover fills the box", transform: Transform{Width: 100, Height: 100, Fit: FitCover}, expectedWidth: 200, expectedHeight: 100},
+		{name: "outside fills the box", transform: Transform{Width: 100, Height: 100, Fit: FitOutside}, expectedWidth: 200, expectedHeight: 100},
+		{name: "contain fits the box", transform: Transform{Width: 100, Height: 100, Fit: FitContain}, expectedWidth: 100, expectedHeight: 50},
+		{name: "inside fits the box", transform: Transform{Width: 100, Height: 100, Fit: FitInside}, expectedWidth: 100, expectedHeight: 50},
+		{name: "fill stretches", transform: Transform{Width: 30, Height: 300, Fit: FitFill}, expectedWidth: 30, expectedHeight: 300},
+		{name: "without enlargement caps scale", transform: Transform{Width: 800, Fit: FitInside, WithoutEnlargement: true}, expectedWidth: 400, expectedHeight: 200},
+		{name: "fill without enlargement caps each axis", transform: Transform{Width: 100, Height: 800, Fit: FitFill, WithoutEnlargement: true}, expectedWidth: 100, expectedHeight: 200},
+	}
+
+	for _, tc := range cases {
+		width, height := tc.transform.scaledSize(400, 200)
+		if width != tc.expectedWidth || height != tc.expectedHeight {
+			t.Fatalf("%s: expected %dx%d, got %dx%d", tc.name, tc.expectedWidth, tc.expectedHeight, width, height)
+		}
+	}
+}
+
+func TestTransformFrameSize(t *testing.T) {
+	width, height := Transform{Width: 100, Height: 100, Fit: FitCover}.frameSize(200, 100)
+	if width != 100 || height != 100 {
+		t.Fatalf("expected cover frame 100x100, got %dx%d", width, height)
+	}
+
+	width, height = Transform{Width: 100, Height: 100, Fit: FitCover, WithoutEnlargement: true}.frameSize(40, 60)
+	if width != 40 || height != 60 {
+		t.Fatalf("expected cover frame to shrink to the unenlarged image, got %dx%d", width, height)
+	}
+
+	width, height = Transform{Width: 100, Height: 100, Fit: FitContain}.frameSize(100, 50)
+	if width != 100 || height != 100 {
+		t.Fatalf("expected contain frame 100x100, got %dx%d", width, height)
+	}
+}
+
+func TestGravityOffset(t *testing.T) {
+	cases := []struct {
+		gravity string
+		left    int
+		top     int
+	}{
+		{gravity: GravityCentre, left: 50, top: 25},
+		{gravity: GravityNorthWest, left: 0, top: 0},
+		{gravity: GravitySouthEast, left: 100, top: 50},
+		{gravity: GravityNorth, left: 50, top: 0},
+		{gravity: GravityWest, left: 0, top: 25},
+	}
+
+	for _, tc := range cases {
+		left, top := gravityOffset(tc.gravity, 200, 100, 100, 50)
+		if left != tc.left || top != tc.top {
+			t.Fatalf("gravity %s: expected offset %d,%d, got %d,%d", tc.gravity, tc.left, tc.top, left, top)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	cases := map[string]rgbaColor{
+		"#fff":      {r: 255, g: 255, b: 255, a: 255},
+		"#00000000": {},
+		"336699":    {r: 0x33, g: 0x66, b: 0x99, a: 255},
+		"#33669980": {r: 0x33, g: 0x66, b: 0x99, a: 0x80},
+	}
+
+	for input, expected := range cases {
+		got, err := parseColor(input)
+		if err != nil {
+			t.Fatalf("expected %q to parse, got error: %v", input, err)
+		}
+		if got != expected {
+			t.Fatalf("expected %q to parse as %+v, got %+v", input, expected, got)
+		}
+	}
+
+	if _, err := parseColor("#12345"); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected malformed color to be rejected, got: %v", err)
+	}
+}
+
+func TestValidateTransformRejectsInvalidCombinations(t *testing.T) {
+	cases := []struct {
+		transform Transform
+		expected  string
+	}{
+		{transform: Transform{Fit: FitCover}, expected: "transform requires width or height"},
+		{transform: Transform{Width: -1}, expected: "must not be negative"},
+		{transform: Transform{Width: maxTransformDimension + 1}, expected: "must not exceed"},
+		{transform: Transform{Width: 10, Fit: "stretch"}, expected: "fit must be one of"},
+		{transform: Transform{Width: 10, Gravity: "up"}, expected: "gravity \"up\" is not supported"},
+		{transform: Transform{Width: 10, Fit: FitContain, Gravity: GravityAttention}, expected: "requires fit cover"},
+		{transform: Transform{Width: 10, Fit: FitCover, Background: "#fff"}, expected: "background requires fit contain"},
+		{transform: Transform{Width: 10, Fit: FitContain, Background: "white"}, expected: "must be #rgb"},
+	}
+
+	for _, tc := range cases {
+		err := ValidateOptions("png", "png", Options{Transform: tc.transform})
+		if !errors.Is(err, ErrInvalidOptions) {
+			t.Fatalf("expected %+v to be rejected, got: %v", tc.transform, err)
+		}
+		if !strings.Contains(err.Error(), tc.expected) {
+			t.Fatalf("expected error to contain %q, got: %v", tc.expected, err)
+		}
+	}
+}
+
+func TestConvertWithOptionsAppliesTransform(t *testing.T) {
+	requireFormatPairSupport(t, "png", "webp")
+
+	input := mustEncodeGradientPNG(t, 64, 32)
+	cases := []struct {
+		transform      Transform
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{transform: Transform{Width: 16}, expectedWidth: 16, expectedHeight: 8},
+		{transform: Transform{Width: 20, Height: 20, Fit: FitCover}, expectedWidth: 20, expectedHeight: 20},
+		{transform: Transform{Width: 20, Height: 20, Fit: FitCover, Gravity: GravityAttention}, expectedWidth: 20, expectedHeight: 20},
+		{transform: Transform{Width: 20, Height: 20, Fit: FitContain, Background: "#ffffff00"}, expectedWidth: 20, expectedHeight: 20},
+		{transform: Transform{Width: 20, Height: 20, Fit: FitInside}, expectedWidth: 20, expectedHeight: 10},
+		{transform: Transform{Width: 20, Height: 20, Fit: FitFill}, expectedWidth: 20, expectedHeight: 20},
+		{transform: Transform{Width: 128, WithoutEnlargement: true}, expectedWidth: 64, expectedHeight: 32},
+	}
+
+	for _, tc := range cases {
+		result, err := newPairConverter("png", "webp").ConvertWithOptions(context.Background(), input, Options{Transform: tc.transform})
+		if err != nil {
+			t.Fatalf("expected transform %+v to succeed, got error: %v", tc.transform, err)
+		}
+
+		size, err := bimg.Size(result.Output)
+		if err != nil {
+			t.Fatalf("failed to read output size: %v", err)
+		}
+		if size.Width != tc.expectedWidth || size.Height != tc.expectedHeight {
+			t.Fatalf("transform %+v: expected %dx%d output, got %dx%d", tc.transform, tc.expectedWidth, tc.expectedHeight, size.Width, size.Height)
+		}
+		if result.Geometry.Width != size.Width || result.Geometry.Height != size.Height {
+			t.Fatalf("expected geometry to match output size, got %+v", result.Geometry)
+		}
+		if result.Geometry.SourceWidth != 64 || result.Geometry.SourceHeight != 32 {
+			t.Fatalf("expected source geometry 64x32, got %+v", result.Geometry)
+		}
+	}
+}
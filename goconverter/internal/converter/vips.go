@@ -0,0 +1,716 @@
+package converter
+
+/*
+#cgo pkg-config: vips
+#include <stdint.h>
+#include <stdlib.h>
+#include <vips/vips.h>
+
+static VipsImage *
+converter_load_buffer(const void *buf, size_t len, const char *option_string) {
+	return vips_image_new_from_buffer(buf, len, option_string, NULL);
+}
+
+static int
+converter_save_buffer(VipsImage *in, const char *suffix, void **buf, size_t *len) {
+	return vips_image_write_to_buffer(in, suffix, buf, len, NULL);
+}
+
+// Target writes go to the Go writer registered under the handle passed as
+// signal data.
+extern int64_t converterTargetWrite(uintptr_t handle, void *data, int64_t length);
+
+static gint64
+converter_target_write(VipsTargetCustom *target, const void *data, gint64 length, void *handle) {
+	return converterTargetWrite((uintptr_t)handle, (void *)data, length);
+}
+
+static int
+converter_can_save_target(const char *suffix) {
+	if (vips_foreign_find_save_target(suffix) == NULL) {
+		vips_error_clear();
+		return 0;
+	}
+
+	return 1;
+}
+
+static int
+converter_save_target(VipsImage *in, const char *suffix, uintptr_t handle) {
+	VipsTargetCustom *target = vips_target_custom_new();
+	int result;
+
+	g_signal_connect_data(target, "write", G_CALLBACK(converter_target_write), (void *)handle, NULL, 0);
+	result = vips_image_write_to_target(in, suffix, VIPS_TARGET(target), NULL);
+	g_object_unref(target);
+
+	return result;
+}
+
+static int
+converter_page_count(VipsImage *in) {
+	int n_pages;
+
+	if (vips_image_get_typeof(in, VIPS_META_N_PAGES) == 0 ||
+		vips_image_get_int(in, VIPS_META_N_PAGES, &n_pages) != 0) {
+		return 1;
+	}
+
+	return n_pages;
+}
+
+// Animations are loaded as one tall strip of frames; page-height records
+// where each frame starts. Joined frames keep the delay and loop metadata of
+// the first frame.
+static int
+converter_join_frames(VipsImage **frames, int n, VipsImage **out) {
+	VipsImage *joined;
+	int result;
+
+	if (vips_arrayjoin(frames, &joined, n, "across", 1, NULL)) {
+		return -1;
+	}
+	result = vips_copy(joined, out, NULL);
+	g_object_unref(joined);
+	if (result) {
+		return -1;
+	}
+	vips_image_set_int(*out, VIPS_META_PAGE_HEIGHT, vips_image_get_height(frames[0]));
+
+	return 0;
+}
+
+static int
+converter_copy(VipsImage *in, VipsImage **out) {
+	return vips_copy(in, out, NULL);
+}
+
+// embedded makes libvips prefer the image's own profile and fall back to
+// input_profile when there is none.
+static int
+converter_icc_transform(VipsImage *in, VipsImage **out, const char *output_profile, const char *input_profile) {
+	int depth = vips_image_get_format(in) == VIPS_FORMAT_USHORT ? 16 : 8;
+
+	return vips_icc_transform(in, out, output_profile,
+		"embedded", TRUE,
+		"input_profile", input_profile,
+		"intent", VIPS_INTENT_RELATIVE,
+		"depth", depth,
+		NULL);
+}
+
+static int
+converter_autorot(VipsImage *in, VipsImage **out) {
+	return vips_autorot(in, out, NULL);
+}
+
+static int
+converter_colourspace(VipsImage *in, VipsImage **out, VipsInterpretation space) {
+	return vips_colourspace(in, out, space, NULL);
+}
+
+// Resizing straight RGBA darkens edges, so alpha images are scaled
+// premultiplied and cast back to their original band format.
+static int
+converter_resize(VipsImage *in, VipsImage **out, double hscale, double vscale) {
+	VipsImage *premultiplied;
+	VipsImage *resized;
+	VipsImage *unpremultiplied;
+	int result;
+
+	if (!vips_image_hasalpha(in)) {
+		return vips_resize(in, out, hscale, "vscale", vscale, NULL);
+	}
+
+	if (vips_premultiply(in, &premultiplied, NULL)) {
+		return -1;
+	}
+	result = vips_resize(premultiplied, &resized, hscale, "vscale", vscale, NULL);
+	g_object_unref(premultiplied);
+	if (result) {
+		return -1;
+	}
+	result = vips_unpremultiply(resized, &unpremultiplied, NULL);
+	g_object_unref(resized);
+	if (result) {
+		return -1;
+	}
+	result = vips_cast(unpremultiplied, out, vips_image_get_format(in), NULL);
+	g_object_unref(unpremultiplied);
+
+	return result;
+}
+
+static int
+converter_extract_area(VipsImage *in, VipsImage **out, int left, int top, int width, int height) {
+	return vips_extract_area(in, out, left, top, width, height, NULL);
+}
+
+static int
+converter_smartcrop(VipsImage *in, VipsImage **out, int width, int height, VipsInteresting interesting) {
+	return vips_smartcrop(in, out, width, height, "interesting", interesting, NULL);
+}
+
+static int
+converter_addalpha(VipsImage *in, VipsImage **out) {
+	return vips_addalpha(in, out, NULL);
+}
+
+static int
+converter_embed(VipsImage *in, VipsImage **out, int left, int top, int width, int height, double *background, int n) {
+	VipsArrayDouble *array = vips_array_double_new(background, n);
+	int result = vips_embed(in, out, left, top, width, height,
+		"extend", VIPS_EXTEND_BACKGROUND,
+		"background", array,
+		NULL);
+	vips_area_unref(VIPS_AREA(array));
+
+	return result;
+}
+
+static int
+converter_flatten(VipsImage *in, VipsImage **out, double *background, int n) {
+	VipsArrayDouble *array = vips_array_double_new(background, n);
+	int result = vips_flatten(in, out, "background", array, NULL);
+	vips_area_unref(VIPS_AREA(array));
+
+	return result;
+}
+
+// An eval state is shared with Go while an image is computed. Go sets killed
+// once the request context is done; the eval callback runs on libvips
+// workers, makes the computation fail at the next tile once killed is set,
+// and records how far it got.
+typedef struct {
+	int killed;
+	int percent;
+} converter_eval_state;
+
+static void
+converter_eval(VipsImage *image, VipsProgress *progress, converter_eval_state *state) {
+	if (__atomic_load_n(&state->killed, __ATOMIC_SEQ_CST)) {
+		vips_image_set_kill(image, TRUE);
+	}
+	__atomic_store_n(&state->percent, progress->percent, __ATOMIC_SEQ_CST);
+}
+
+static gulong
+converter_watch_eval(VipsImage *in, converter_eval_state *state) {
+	vips_image_set_progress(in, TRUE);
+	return g_signal_connect_data(in, "eval", G_CALLBACK(converter_eval), state, NULL, 0);
+}
+
+static void
+converter_unwatch_eval(VipsImage *in, gulong handler) {
+	g_signal_handler_disconnect(in, handler);
+	vips_image_set_progress(in, FALSE);
+}
+
+static void
+converter_kill(converter_eval_state *state) {
+	__atomic_store_n(&state->killed, 1, __ATOMIC_SEQ_CST);
+}
+
+static int
+converter_eval_percent(converter_eval_state *state) {
+	return __atomic_load_n(&state->percent, __ATOMIC_SEQ_CST);
+}
+
+static int
+converter_operation_exists(const char *name) {
+	return vips_type_find("VipsOperation", name) != 0;
+}
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime/cgo"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// vipsImage owns one libvips image reference. libvips itself is started by
+// the bimg package initializer, so this package only talks to an already
+// initialized library.
+type vipsImage struct {
+	image *C.VipsImage
+	// input stays referenced because libvips decodes lazily from it.
+	input []byte
+}
+
+// vipsLoadBuffer decodes from memory, so the whole input is read before
+// libvips starts and held until the image closes. Only outputs stream,
+// through a libvips target. A custom source over the request body would not
+// save the copy: the loaders of PDF, HEIF and ImageMagick input seek, and
+// libvips reads unseekable sources into memory for them.
+func vipsLoadBuffer(input []byte, optionString string) (*vipsImage, error) {
+	if len(input) == 0 {
+		return nil, errors.New("input buffer is empty")
+	}
+
+	cOptionString := C.CString(optionString)
+	defer C.free(unsafe.Pointer(cOptionString))
+
+	image := C.converter_load_buffer(unsafe.Pointer(&input[0]), C.size_t(len(input)), cOptionString)
+	if image == nil {
+		// Another backend may have a loader this libvips build lacks.
+		return nil, RetryableError(vipsError())
+	}
+
+	return &vipsImage{image: image, input: input}, nil
+}
+
+func (i *vipsImage) close() {
+	if i.image == nil {
+		return
+	}
+
+	C.g_object_unref(C.gpointer(i.image))
+	i.image = nil
+	i.input = nil
+}
+
+func (i *vipsImage) replace(image *C.VipsImage) {
+	C.g_object_unref(C.gpointer(i.image))
+	i.image = image
+}
+
+const (
+	vipsInterestingAttention = C.VIPS_INTERESTING_ATTENTION
+	vipsInterestingEntropy   = C.VIPS_INTERESTING_ENTROPY
+)
+
+func (i *vipsImage) width() int {
+	return int(C.vips_image_get_width(i.image))
+}
+
+func (i *vipsImage) height() int {
+	return int(C.vips_image_get_height(i.image))
+}
+
+func (i *vipsImage) hasAlpha() bool {
+	return C.vips_image_hasalpha(i.image) != 0
+}
+
+func (i *vipsImage) pageCount() int {
+	return max(1, int(C.converter_page_count(i.image)))
+}
+
+// frameHeight is the height of one animation frame, or the full image height
+// for still images.
+func (i *vipsImage) frameHeight() int {
+	return int(C.vips_image_get_page_height(i.image))
+}
+
+func (i *vipsImage) extractFrame(index int, frameHeight int) (*vipsImage, error) {
+	var output *C.VipsImage
+	if C.converter_extract_area(i.image, &output, 0, C.int(index*frameHeight), C.int(i.width()), C.int(frameHeight)) != 0 {
+		return nil, vipsError()
+	}
+
+	return &vipsImage{image: output, input: i.input}, nil
+}
+
+func vipsJoinFrames(frames []*vipsImage) (*vipsImage, error) {
+	images := make([]*C.VipsImage, 0, len(frames))
+	for _, frame := range frames {
+		images = append(images, frame.image)
+	}
+
+	var output *C.VipsImage
+	if C.converter_join_frames(&images[0], C.int(len(images)), &output) != 0 {
+		return nil, vipsError()
+	}
+
+	return &vipsImage{image: output, input: frames[0].input}, nil
+}
+
+func (i *vipsImage) fields() []string {
+	fields := C.vips_image_get_fields(i.image)
+	if fields == nil {
+		return nil
+	}
+	defer C.g_strfreev(fields)
+
+	names := []string{}
+	for field := fields; *field != nil; field = (**C.gchar)(unsafe.Add(unsafe.Pointer(field), unsafe.Sizeof(*field))) {
+		names = append(names, C.GoString(*field))
+	}
+
+	return names
+}
+
+// privateCopy gives the image its own header so fields can be changed
+// without affecting other references to the same pixels.
+func (i *vipsImage) privateCopy() error {
+	var output *C.VipsImage
+	if C.converter_copy(i.image, &output) != 0 {
+		return vipsError()
+	}
+
+	i.replace(output)
+	return nil
+}
+
+func (i *vipsImage) removeFields(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	if err := i.privateCopy(); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		cName := C.CString(name)
+		C.vips_image_remove(i.image, cName)
+		C.free(unsafe.Pointer(cName))
+	}
+
+	return nil
+}
+
+func (i *vipsImage) isCMYK() bool {
+	return C.vips_image_get_interpretation(i.image) == C.VIPS_INTERPRETATION_CMYK
+}
+
+func (i *vipsImage) isGrey() bool {
+	switch C.vips_image_get_interpretation(i.image) {
+	case C.VIPS_INTERPRETATION_B_W, C.VIPS_INTERPRETATION_GREY16:
+		return true
+	default:
+		return false
+	}
+}
+
+func (i *vipsImage) hasField(name string) bool {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	return C.vips_image_get_typeof(i.image, cName) != 0
+}
+
+func (i *vipsImage) iccTransform(outputProfile string, inputProfile string) error {
+	cOutputProfile := C.CString(outputProfile)
+	defer C.free(unsafe.Pointer(cOutputProfile))
+	cInputProfile := C.CString(inputProfile)
+	defer C.free(unsafe.Pointer(cInputProfile))
+
+	var output *C.VipsImage
+	if C.converter_icc_transform(i.image, &output, cOutputProfile, cInputProfile) != 0 {
+		return vipsError()
+	}
+
+	i.replace(output)
+	return nil
+}
+
+const vipsOrientationField = "orientation"
+
+// autoRotate applies the EXIF orientation to the pixels and resets the tag to
+// upright, so viewers that honor EXIF do not rotate the output a second time.
+func (i *vipsImage) autoRotate() error {
+	if i.orientation() == 1 {
+		return nil
+	}
+
+	var output *C.VipsImage
+	if C.converter_autorot(i.image, &output) != 0 {
+		return vipsError()
+	}
+	i.replace(output)
+
+	if err := i.privateCopy(); err != nil {
+		return err
+	}
+
+	cName := C.CString(vipsOrientationField)
+	defer C.free(unsafe.Pointer(cName))
+	C.vips_image_set_int(i.image, cName, 1)
+
+	return nil
+}
+
+func (i *vipsImage) orientation() int {
+	if !i.hasField(vipsOrientationField) {
+		return 1
+	}
+
+	cName := C.CString(vipsOrientationField)
+	defer C.free(unsafe.Pointer(cName))
+
+	var value C.int
+	if C.vips_image_get_int(i.image, cName, &value) != 0 {
+		C.vips_error_clear()
+		return 1
+	}
+
+	return int(value)
+}
+
+func (i *vipsImage) toColourspace(space C.VipsInterpretation) error {
+	var output *C.VipsImage
+	if C.converter_colourspace(i.image, &output, space) != 0 {
+		return vipsError()
+	}
+
+	i.replace(output)
+	return nil
+}
+
+func (i *vipsImage) resize(xScale float64, yScale float64) error {
+	var output *C.VipsImage
+	if C.converter_resize(i.image, &output, C.double(xScale), C.double(yScale)) != 0 {
+		return vipsError()
+	}
+
+	i.replace(output)
+	return nil
+}
+
+func (i *vipsImage) extractArea(left int, top int, width int, height int) error {
+	var output *C.VipsImage
+	if C.converter_extract_area(i.image, &output, C.int(left), C.int(top), C.int(width), C.int(height)) != 0 {
+		return vipsError()
+	}
+
+	i.replace(output)
+	return nil
+}
+
+func (i *vipsImage) smartCrop(width int, height int, interesting C.VipsInteresting) error {
+	var output *C.VipsImage
+	if C.converter_smartcrop(i.image, &output, C.int(width), C.int(height), interesting) != 0 {
+		return vipsError()
+	}
+
+	i.replace(output)
+	return nil
+}
+
+func (i *vipsImage) embed(left int, top int, width int, height int, color rgbaColor) error {
+	switch C.vips_image_get_interpretation(i.image) {
+	case C.VIPS_INTERPRETATION_sRGB, C.VIPS_INTERPRETATION_B_W, C.VIPS_INTERPRETATION_RGB16, C.VIPS_INTERPRETATION_GREY16:
+	default:
+		if err := i.toColourspace(C.VIPS_INTERPRETATION_sRGB); err != nil {
+			return err
+		}
+	}
+
+	if color.a != 255 && !i.hasAlpha() {
+		var output *C.VipsImage
+		if C.converter_addalpha(i.image, &output) != 0 {
+			return vipsError()
+		}
+		i.replace(output)
+	}
+
+	background := i.backgroundValues(color)
+
+	var output *C.VipsImage
+	if C.converter_embed(
+		i.image,
+		&output,
+		C.int(left),
+		C.int(top),
+		C.int(width),
+		C.int(height),
+		(*C.double)(unsafe.Pointer(&background[0])),
+		C.int(len(background)),
+	) != 0 {
+		return vipsError()
+	}
+
+	i.replace(output)
+	return nil
+}
+
+// flatten blends the alpha channel away onto an opaque background color.
+func (i *vipsImage) flatten(color rgbaColor) error {
+	if !i.hasAlpha() {
+		return nil
+	}
+
+	background := i.backgroundValues(color)
+	background = background[:len(background)-1]
+
+	var output *C.VipsImage
+	if C.converter_flatten(i.image, &output, (*C.double)(unsafe.Pointer(&background[0])), C.int(len(background))) != 0 {
+		return vipsError()
+	}
+
+	i.replace(output)
+	return nil
+}
+
+// backgroundValues expresses an 8-bit color in the band layout and numeric
+// range of the current image.
+func (i *vipsImage) backgroundValues(color rgbaColor) []float64 {
+	var values []float64
+	switch C.vips_image_get_interpretation(i.image) {
+	case C.VIPS_INTERPRETATION_B_W, C.VIPS_INTERPRETATION_GREY16:
+		values = []float64{0.2126*float64(color.r) + 0.7152*float64(color.g) + 0.0722*float64(color.b)}
+	default:
+		values = []float64{float64(color.r), float64(color.g), float64(color.b)}
+	}
+	if i.hasAlpha() {
+		values = append(values, float64(color.a))
+	}
+
+	if C.vips_image_get_format(i.image) == C.VIPS_FORMAT_USHORT {
+		for index := range values {
+			values[index] *= 257
+		}
+	}
+
+	return values
+}
+
+// save writes the encoded image to w, or returns it when w is nil.
+func (i *vipsImage) save(ctx context.Context, optionString string, w io.Writer) ([]byte, error) {
+	if w == nil {
+		return i.saveBuffer(ctx, optionString)
+	}
+
+	return nil, i.saveTarget(ctx, optionString, w)
+}
+
+// saveBuffer computes and encodes the image. Pixels are only computed while
+// saving, so this is where a done context aborts libvips through its kill
+// switch.
+func (i *vipsImage) saveBuffer(ctx context.Context, optionString string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cOptionString := C.CString(optionString)
+	defer C.free(unsafe.Pointer(cOptionString))
+	defer i.watchContext(ctx)()
+
+	var buffer unsafe.Pointer
+	var length C.size_t
+	if C.converter_save_buffer(i.image, cOptionString, &buffer, &length) != 0 {
+		return nil, saveError(ctx, nil)
+	}
+	defer C.g_free(C.gpointer(buffer))
+
+	return C.GoBytes(buffer, C.int(length)), nil
+}
+
+// saveTarget encodes the image straight into w as libvips produces it, so
+// no encoded copy is held in memory. Savers without target support go
+// through saveBuffer.
+func (i *vipsImage) saveTarget(ctx context.Context, optionString string, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cOptionString := C.CString(optionString)
+	defer C.free(unsafe.Pointer(cOptionString))
+	if C.converter_can_save_target(cOptionString) == 0 {
+		output, err := i.saveBuffer(ctx, optionString)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(output)
+		return err
+	}
+	defer i.watchContext(ctx)()
+
+	target := &vipsTarget{w: w}
+	handle := cgo.NewHandle(target)
+	defer handle.Delete()
+	if C.converter_save_target(i.image, cOptionString, C.uintptr_t(handle)) != 0 {
+		return saveError(ctx, target.err)
+	}
+
+	return nil
+}
+
+// vipsProgressInterval is how often the progress of a computation is read
+// when ctx has a progress listener.
+const vipsProgressInterval = 200 * time.Millisecond
+
+// watchContext sets the kill switch of the image once ctx is done, and
+// reports the progress of its computation to ctx. The returned func stops
+// watching.
+func (i *vipsImage) watchContext(ctx context.Context) func() {
+	state := (*C.converter_eval_state)(C.calloc(1, C.size_t(unsafe.Sizeof(C.converter_eval_state{}))))
+	handler := C.converter_watch_eval(i.image, state)
+	killSet := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		C.converter_kill(state)
+		close(killSet)
+	})
+
+	reporting := make(chan struct{})
+	reported := make(chan struct{})
+	if progressReported(ctx) {
+		go func() {
+			defer close(reported)
+			ticker := time.NewTicker(vipsProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					ReportProgress(ctx, int(C.converter_eval_percent(state)))
+				case <-reporting:
+					return
+				}
+			}
+		}()
+	} else {
+		close(reported)
+	}
+
+	return func() {
+		if !stop() {
+			<-killSet
+		}
+		close(reporting)
+		<-reported
+		C.converter_unwatch_eval(i.image, handler)
+		C.free(unsafe.Pointer(state))
+	}
+}
+
+// saveError reports why a save failed: the context, then the writer, and
+// otherwise the libvips error, which another backend may not hit.
+func saveError(ctx context.Context, writeErr error) error {
+	err := vipsError()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+
+	return RetryableError(err)
+}
+
+// vipsOperationExists reports whether this libvips build has an operation,
+// e.g. "jxlsave_buffer".
+func vipsOperationExists(name string) bool {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	return C.converter_operation_exists(cName) != 0
+}
+
+func vipsThreadShutdown() {
+	C.vips_thread_shutdown()
+}
+
+func vipsError() error {
+	message := strings.TrimSpace(C.GoString(C.vips_error_buffer()))
+	C.vips_error_clear()
+	if message == "" {
+		message = "unknown libvips error"
+	}
+
+	return errors.New(message)
+}
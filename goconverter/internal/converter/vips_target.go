@@ -0,0 +1,34 @@
+package converter
+
+// #include <stdint.h>
+import "C"
+
+import (
+	"io"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// vipsTarget is the Go side of a libvips custom target. It keeps the first
+// write error so it can be reported instead of the libvips one.
+type vipsTarget struct {
+	w   io.Writer
+	err error
+}
+
+// converterTargetWrite lives apart from vips.go because files exporting
+// functions to C may only declare things in their preamble.
+//
+//export converterTargetWrite
+func converterTargetWrite(handle C.uintptr_t, data unsafe.Pointer, length C.int64_t) C.int64_t {
+	target := cgo.Handle(handle).Value().(*vipsTarget)
+	if target.err != nil {
+		return -1
+	}
+	if _, err := target.w.Write(unsafe.Slice((*byte)(data), int(length))); err != nil {
+		target.err = err
+		return -1
+	}
+
+	return length
+}
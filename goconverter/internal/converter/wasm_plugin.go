@@ -0,0 +1,275 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	defaultWasmPluginPriority = 150
+	defaultWasmMemoryBytes    = 256 << 20
+	defaultWasmTimeout        = 30 * time.Second
+	defaultWasmOutputBytes    = 256 << 20
+	wasmPageSize              = 64 << 10
+	wasmPluginExtension       = ".wasm"
+)
+
+// Commands passed to a plugin as its first argument.
+const (
+	wasmCommandPairs   = "pairs"
+	wasmCommandConvert = "convert"
+)
+
+// WasmLimits cap every plugin call. Zero values use the defaults. Memory is
+// rounded down to whole 64 KiB pages.
+type WasmLimits struct {
+	MemoryBytes int64
+	Timeout     time.Duration
+	OutputBytes int64
+}
+
+// WasmPlugin is a WASI command module that converts images in-process. It is
+// run with "pairs" to list the "source target" pairs it handles, one per
+// line, and with "convert <source> <target>" to convert stdin to stdout.
+// Plugins get no filesystem, environment or network access.
+type WasmPlugin struct {
+	Name  string
+	Pairs [][2]string
+
+	limits   WasmLimits
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// LoadWasmPlugins compiles every .wasm file in dir and asks it for its pairs.
+// Plugins are named after their file, without the extension.
+func LoadWasmPlugins(dir string, limits WasmLimits) ([]*WasmPlugin, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+wasmPluginExtension))
+	if err != nil {
+		return nil, fmt.Errorf("list wasm plugins: %w", err)
+	}
+
+	var plugins []*WasmPlugin
+	for _, path := range paths {
+		plugin, err := LoadWasmPlugin(path, limits)
+		if err != nil {
+			CloseWasmPlugins(plugins)
+			return nil, err
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins, nil
+}
+
+// LoadWasmPlugin compiles one plugin and asks it for its pairs.
+func LoadWasmPlugin(path string, limits WasmLimits) (*WasmPlugin, error) {
+	name := strings.TrimSuffix(filepath.Base(path), wasmPluginExtension)
+	if name == BackendVips || name == BackendGo {
+		return nil, fmt.Errorf("wasm plugin %s: name %q is already in use", path, name)
+	}
+
+	binary, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read wasm plugin: %w", err)
+	}
+
+	limits = limits.withDefaults()
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(limits.MemoryBytes/wasmPageSize)).
+		WithCloseOnContextDone(true))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("wasm plugin %s: %w", name, err)
+	}
+	compiled, err := runtime.CompileModule(ctx, binary)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("compile wasm plugin %s: %w", name, err)
+	}
+
+	plugin := &WasmPlugin{Name: name, limits: limits, runtime: runtime, compiled: compiled}
+	if err := plugin.loadPairs(); err != nil {
+		plugin.Close()
+		return nil, fmt.Errorf("wasm plugin %s: %w", name, err)
+	}
+
+	return plugin, nil
+}
+
+// RegisterWasmPlugins registers every plugin as its own backend, tried
+// before libvips and after external tools.
+func RegisterWasmPlugins(plugins []*WasmPlugin) {
+	for _, plugin := range plugins {
+		RegisterBackend(Backend{
+			Name:     plugin.Name,
+			Priority: defaultWasmPluginPriority,
+			Converters: func() []Converter {
+				output := make([]Converter, 0, len(plugin.Pairs))
+				for _, pair := range plugin.Pairs {
+					output = append(output, &wasmConverter{plugin: plugin, source: pair[0], target: pair[1]})
+				}
+				return output
+			},
+		})
+	}
+}
+
+// CloseWasmPlugins releases the compiled modules of every plugin.
+func CloseWasmPlugins(plugins []*WasmPlugin) {
+	for _, plugin := range plugins {
+		plugin.Close()
+	}
+}
+
+func (p *WasmPlugin) Close() {
+	p.runtime.Close(context.Background())
+}
+
+func (l WasmLimits) withDefaults() WasmLimits {
+	if l.MemoryBytes <= 0 {
+		l.MemoryBytes = defaultWasmMemoryBytes
+	}
+	if l.Timeout <= 0 {
+		l.Timeout = defaultWasmTimeout
+	}
+	if l.OutputBytes <= 0 {
+		l.OutputBytes = defaultWasmOutputBytes
+	}
+
+	return l
+}
+
+func (p *WasmPlugin) loadPairs() error {
+	output, err := p.run(context.Background(), nil, wasmCommandPairs)
+	if err != nil {
+		return err
+	}
+
+	seen := map[[2]string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return fmt.Errorf("pair %q must be \"<source> <target>\"", scanner.Text())
+		}
+		pair := [2]string{fields[0], fields[1]}
+		if _, ok := LookupFormat(pair[0]); !ok {
+			return fmt.Errorf("unknown source format %q", pair[0])
+		}
+		if _, ok := LookupFormat(pair[1]); !ok {
+			return fmt.Errorf("unknown target format %q", pair[1])
+		}
+		if pair[0] == pair[1] {
+			return fmt.Errorf("pair %s to %s must use different formats", pair[0], pair[1])
+		}
+		if !seen[pair] {
+			seen[pair] = true
+			p.Pairs = append(p.Pairs, pair)
+		}
+	}
+	if len(p.Pairs) == 0 {
+		return errors.New("declares no pairs")
+	}
+
+	return nil
+}
+
+// run instantiates a fresh module for one call, so no state leaks between
+// conversions, and closes it when the call returns, times out or the parent
+// context is done.
+func (p *WasmPlugin) run(parent context.Context, input []byte, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(parent, p.limits.Timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: p.limits.OutputBytes}
+	stderr := &limitedBuffer{limit: toolStderrLimit}
+	config := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(append([]string{p.Name}, args...)...).
+		WithStdin(bytes.NewReader(input)).
+		WithStdout(stdout).
+		WithStderr(stderr)
+
+	module, err := p.runtime.InstantiateModule(ctx, p.compiled, config)
+	if module != nil {
+		module.Close(ctx)
+	}
+	switch {
+	case parent.Err() != nil:
+		return nil, parent.Err()
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, RetryableError(fmt.Errorf("%s timed out after %s", p.Name, p.limits.Timeout))
+	case err != nil:
+		return nil, RetryableError(fmt.Errorf("%s failed: %w: %s", p.Name, err, strings.TrimSpace(stderr.String())))
+	case stdout.truncated:
+		return nil, RetryableError(fmt.Errorf("%s output exceeds %d bytes", p.Name, p.limits.OutputBytes))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// wasmConverter runs one pair of a plugin.
+type wasmConverter struct {
+	plugin *WasmPlugin
+	source string
+	target string
+}
+
+var _ Converter = (*wasmConverter)(nil)
+
+func (c *wasmConverter) SourceFormat() string {
+	return c.source
+}
+
+func (c *wasmConverter) TargetFormat() string {
+	return c.target
+}
+
+func (c *wasmConverter) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := c.ConvertWithOptions(ctx, input, Options{})
+	return result.Output, err
+}
+
+func (c *wasmConverter) ConvertWithOptions(ctx context.Context, input []byte, options Options) (Result, error) {
+	output, err := c.convert(ctx, input, options)
+	if err != nil {
+		return Result{}, fmt.Errorf("convert %s to %s: %w", c.source, c.target, err)
+	}
+
+	return Result{Output: output}, nil
+}
+
+func (c *wasmConverter) checkOptions(options Options) error {
+	return checkPassThroughOptions(options, c.plugin.Name)
+}
+
+func (c *wasmConverter) convert(ctx context.Context, input []byte, options Options) ([]byte, error) {
+	if err := c.checkOptions(options); err != nil {
+		return nil, err
+	}
+
+	output, err := c.plugin.run(ctx, input, wasmCommandConvert, c.source, c.target)
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, RetryableError(fmt.Errorf("%s wrote no output", c.plugin.Name))
+	}
+
+	return output, nil
+}
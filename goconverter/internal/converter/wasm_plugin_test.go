@@ -0,0 +1,153 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	wasmPluginOnce   sync.Once
+	wasmPluginBinary []byte
+	wasmPluginErr    error
+)
+
+// buildWasmPlugin compiles testdata/wasmplugin once per test run and copies
+// it into a fresh directory as <name>.wasm.
+func buildWasmPlugin(t *testing.T, name string) string {
+	t.Helper()
+
+	wasmPluginOnce.Do(func() {
+		output := filepath.Join(os.TempDir(), "goconverter-wasmplugin-test.wasm")
+		cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "build", "-o", output, "./testdata/wasmplugin")
+		cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+		if combined, err := cmd.CombinedOutput(); err != nil {
+			wasmPluginErr = errors.New(strings.TrimSpace(string(combined)))
+			return
+		}
+		wasmPluginBinary, wasmPluginErr = os.ReadFile(output)
+		os.Remove(output)
+	})
+	if wasmPluginErr != nil {
+		t.Skipf("cannot build wasm test plugin: %v", wasmPluginErr)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name+wasmPluginExtension), wasmPluginBinary, 0o600); err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+
+	return dir
+}
+
+func loadTestWasmPlugin(t *testing.T, limits WasmLimits) *WasmPlugin {
+	t.Helper()
+
+	plugins, err := LoadWasmPlugins(buildWasmPlugin(t, "upper"), limits)
+	if err != nil {
+		t.Fatalf("expected plugin to load, got error: %v", err)
+	}
+	if len(plugins) != 1 {
+		t.Fatalf("expected one plugin, got %d", len(plugins))
+	}
+	t.Cleanup(func() { CloseWasmPlugins(plugins) })
+
+	return plugins[0]
+}
+
+func TestWasmPluginConverts(t *testing.T) {
+	plugin := loadTestWasmPlugin(t, WasmLimits{})
+	if plugin.Name != "upper" || !slices.Equal(plugin.Pairs, [][2]string{{"svg", "pdf"}}) {
+		t.Fatalf("unexpected plugin %s with pairs %v", plugin.Name, plugin.Pairs)
+	}
+
+	c := &wasmConverter{plugin: plugin, source: "svg", target: "pdf"}
+	output, err := c.Convert(context.Background(), []byte("<svg/>"))
+	if err != nil {
+		t.Fatalf("expected plugin to succeed, got error: %v", err)
+	}
+	if string(output) != "<SVG/>" {
+		t.Fatalf("expected plugin output, got %q", output)
+	}
+}
+
+func TestWasmPluginEnforcesLimits(t *testing.T) {
+	plugin := loadTestWasmPlugin(t, WasmLimits{MemoryBytes: 64 << 20, Timeout: 2 * time.Second})
+	c := &wasmConverter{plugin: plugin, source: "svg", target: "pdf"}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "loop", expected: "timed out"},
+		{input: "alloc", expected: "upper failed"},
+	}
+	for _, tt := range tests {
+		started := time.Now()
+		_, err := c.Convert(context.Background(), []byte(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Fatalf("%s: expected error containing %q, got %v", tt.input, tt.expected, err)
+		}
+		if !errors.Is(err, ErrRetryable) {
+			t.Fatalf("%s: expected a retryable error, got %v", tt.input, err)
+		}
+		if elapsed := time.Since(started); elapsed > 10*time.Second {
+			t.Fatalf("%s: expected the plugin to be stopped promptly, took %s", tt.input, elapsed)
+		}
+	}
+
+	if output, err := c.Convert(context.Background(), []byte("ok")); err != nil || string(output) != "OK" {
+		t.Fatalf("expected the plugin to keep working after a failure, got %q, %v", output, err)
+	}
+}
+
+func TestWasmPluginRejectsOptions(t *testing.T) {
+	plugin := loadTestWasmPlugin(t, WasmLimits{})
+	c := &wasmConverter{plugin: plugin, source: "svg", target: "pdf"}
+
+	_, err := c.ConvertWithOptions(context.Background(), []byte("<svg/>"), Options{Metadata: MetadataStrip})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("expected invalid options error, got %v", err)
+	}
+}
+
+func TestLoadWasmPluginsRejectsReservedNames(t *testing.T) {
+	_, err := LoadWasmPlugins(buildWasmPlugin(t, BackendGo), WasmLimits{})
+	if err == nil || !strings.Contains(err.Error(), "already in use") {
+		t.Fatalf("expected reserved name error, got %v", err)
+	}
+}
+
+func TestRegisterWasmPluginsExposesPairs(t *testing.T) {
+	savedBackends := backends
+	t.Cleanup(func() {
+		backends = savedBackends
+		converters = buildConverters()
+	})
+
+	RegisterWasmPlugins([]*WasmPlugin{loadTestWasmPlugin(t, WasmLimits{})})
+
+	if backends := ConversionBackends()["svg"]["pdf"]; !slices.Equal(backends, []string{"upper"}) {
+		t.Fatalf("expected svg to pdf to run on the plugin, got %v", backends)
+	}
+
+	c, ok := FindConverter("svg", "pdf")
+	if !ok {
+		t.Fatal("expected an svg to pdf converter")
+	}
+	result, err := c.ConvertWithOptions(context.Background(), []byte("<svg/>"), Options{})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if string(result.Output) != "<SVG/>" || !slices.Equal(result.Backends, []string{"upper"}) {
+		t.Fatalf("unexpected result %q from %v", result.Output, result.Backends)
+	}
+}
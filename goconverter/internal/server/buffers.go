@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPooledBufferBytes keeps buffers grown by unusually large files out of
+// the pool, so they are freed instead of pinned between requests.
+const maxPooledBufferBytes = 64 * 1024 * 1024
+
+// bufferPool holds the buffers that decoded inputs and converted outputs
+// are written to.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns buffer to the pool. Nothing may use its bytes afterwards.
+func putBuffer(buffer *bytes.Buffer) {
+	if buffer == nil || buffer.Cap() > maxPooledBufferBytes {
+		return
+	}
+
+	buffer.Reset()
+	bufferPool.Put(buffer)
+}
+
+// growBuffer makes room for size bytes up front, so a buffer filled from a
+// stream of known length is not copied each time it would double. ReadFrom
+// wants bytes.MinRead spare bytes to see the end of the stream.
+func growBuffer(buffer *bytes.Buffer, size int64) {
+	if size > 0 {
+		buffer.Grow(int(min(size, int64(maxDecodedFileSizeBytes)+1)) + bytes.MinRead)
+	}
+}
+
+// contentField is the response field writeContentResponse fills in.
+var contentField = []byte(`"contentBase64":""`)
+
+// writeContentResponse writes response as JSON with content base64 encoded
+// into its empty contentBase64 field on the way to the client, so the
+// encoded copy of the file is never held in memory.
+func writeContentResponse(c *gin.Context, response any, content []byte) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, "conversion_failed", "failed to encode the response")
+		return
+	}
+	// Strings escape their quotes, so the first match is the field itself.
+	split := bytes.Index(body, contentField)
+	if split < 0 {
+		writeError(c, http.StatusInternalServerError, "conversion_failed", "failed to encode the response")
+		return
+	}
+	split += len(contentField) - 1
+
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := writeContent(c.Writer, body[:split], content, body[split:]); err != nil {
+		// The status is sent, so the client only sees the body end early.
+		c.Set(errorCodeContextKey, "response_write_failed")
+		c.Error(err)
+	}
+}
+
+func writeContent(w io.Writer, prefix []byte, content []byte, suffix []byte) error {
+	if _, err := w.Write(prefix); err != nil {
+		return err
+	}
+	encoder := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err := encoder.Write(content); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	_, err := w.Write(suffix)
+
+	return err
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWriteContentResponseRejectsResponsesWithoutTheField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	writeContentResponse(c, struct {
+		Content string `json:"content"`
+	}{}, []byte("ABC"))
+
+	if w.Code != http.StatusInternalServerError || c.GetString(errorCodeContextKey) != "conversion_failed" {
+		t.Fatalf("expected conversion_failed, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWriteContentResponseRecordsWriteErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(failingResponseWriter{header: http.Header{}})
+
+	writeContentResponse(c, ConvertResponse{}, []byte("ABC"))
+
+	if c.GetString(errorCodeContextKey) != "response_write_failed" || len(c.Errors) != 1 {
+		t.Fatalf("expected the write error to be recorded, got %q and %v", c.GetString(errorCodeContextKey), c.Errors)
+	}
+}
+
+// failingResponseWriter stands in for a client that went away.
+type failingResponseWriter struct {
+	header http.Header
+}
+
+func (w failingResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (failingResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func (failingResponseWriter) WriteHeader(int) {}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"goconverter/internal/converter"
+
+	"github.com/gin-gonic/gin"
+)
+
+// combineHandler godoc
+// @Summary Combine images into one document
+// @Description Decodes the inputs in order, expanding multi-page ones, and writes every page to one PDF or multi-page TIFF.
+// @Tags conversions
+// @Accept json
+// @Produce json
+// @Param X-Request-Timeout header int false "Conversion deadline in milliseconds; the shorter of this and timeoutMs applies"
+// @Param request body CombineRequest true "Combine request"
+// @Success 200 {object} CombineResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/combine [post]
+func combineHandler(c *gin.Context) {
+	var request CombineRequest
+	var contents []*requestContent
+	defer func() {
+		for _, content := range contents {
+			content.release()
+		}
+	}()
+	if err := decodeCombineRequest(c.Request.Body, &request, &contents); err != nil {
+		writeBodyReadError(c, err, "invalid JSON request body")
+		return
+	}
+	timeout, ok := conversionTimeout(c, request.TimeoutMs, maxConversionTimeout)
+	if !ok {
+		return
+	}
+
+	to := normalizeFormat(request.To)
+	markConversionFormats(c, "", to)
+	if to == "" || len(request.Inputs) == 0 {
+		writeError(c, http.StatusBadRequest, "invalid_request", "to and at least one input are required")
+		return
+	}
+	if !converter.CanCombineInto(to) {
+		writeError(c, http.StatusUnsupportedMediaType, "unsupported_target_format", fmt.Sprintf("combining into %s is not supported; use pdf or tiff", to))
+		return
+	}
+	if len(request.Inputs) > maxCombineInputs {
+		writeError(c, http.StatusBadRequest, "invalid_request", fmt.Sprintf("at most %d inputs can be combined", maxCombineInputs))
+		return
+	}
+
+	options := combineOptions(request)
+	if err := converter.ValidateCombineOptions(to, options); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_options", err.Error())
+		return
+	}
+
+	// The size limit applies to all inputs together, since they are decoded
+	// into one document.
+	inputs := make([][]byte, 0, len(request.Inputs))
+	for index, input := range request.Inputs {
+		content := combineInputContent(input, contents)
+		switch {
+		case content == nil || !content.present:
+			writeError(c, http.StatusBadRequest, "invalid_request", fmt.Sprintf("input %d: contentBase64 is required", index+1))
+			return
+		case content.tooLarge:
+			writeError(c, http.StatusRequestEntityTooLarge, "payload_too_large", sizeLimitExceeded("decoded input files exceed"))
+			return
+		case content.invalid:
+			writeError(c, http.StatusBadRequest, "invalid_base64", fmt.Sprintf("input %d: contentBase64 must be valid base64", index+1))
+			return
+		}
+		inputs = append(inputs, content.bytes())
+	}
+
+	if !tryAcquireConversionSlot() {
+		writeError(c, http.StatusServiceUnavailable, "converter_busy", "converter is busy, retry shortly")
+		return
+	}
+	defer releaseConversionSlot()
+
+	// The request context is cancelled when the client disconnects.
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	result, err := runCombine(ctx, inputs, to, options)
+	if err != nil {
+		if writeInterruptedError(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, converter.ErrUnsupportedInput):
+			writeError(c, http.StatusUnsupportedMediaType, "unsupported_source_format", err.Error())
+		case errors.Is(err, converter.ErrInvalidOptions):
+			writeError(c, http.StatusBadRequest, "invalid_options", err.Error())
+		case errors.Is(err, converter.ErrInputTooComplex):
+			writeError(c, http.StatusUnprocessableEntity, "input_too_complex", err.Error())
+		default:
+			writeError(c, http.StatusInternalServerError, "conversion_failed", "failed to combine files")
+		}
+		return
+	}
+
+	sources := make([]CombineSource, 0, len(result.Sources))
+	for index, source := range result.Sources {
+		sources = append(sources, CombineSource{
+			FileName:       strings.TrimSpace(request.Inputs[index].FileName),
+			DetectedFormat: source.Format,
+			Pages:          source.Pages,
+		})
+	}
+
+	fileName := strings.TrimSpace(request.FileName)
+	if fileName == "" {
+		fileName = "combined"
+	}
+
+	writeContentResponse(c, CombineResponse{
+		To:       to,
+		FileName: outputFileName(fileName, to),
+		MimeType: mimeTypeByFormat(to),
+		Pages:    result.Pages,
+		Sources:  sources,
+	}, result.Output)
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
 
 	"goconverter/internal/converter"
@@ -17,7 +18,57 @@ import (
 func listConversionsHandler(c *gin.Context) {
 	canonicalFormats := converter.ConversionTargetsBySource()
 
+	var deprecatedFormats []string
+	for _, format := range converter.Formats() {
+		if _, ok := canonicalFormats[format.Name]; ok && format.Deprecated {
+			deprecatedFormats = append(deprecatedFormats, format.Name)
+		}
+	}
+
 	c.JSON(http.StatusOK, ConversionsResponse{
-		Formats: expandConversionFormatsWithAliases(canonicalFormats),
+		Formats:           expandConversionFormatsWithAliases(canonicalFormats),
+		Routes:            converter.ConversionRoutes(),
+		Backends:          converter.ConversionBackends(),
+		DeprecatedFormats: deprecatedFormats,
+	})
+}
+
+// conversionSchemaHandler godoc
+// @Summary Describe the options of a conversion pair
+// @Tags conversions
+// @Produce json
+// @Param from path string true "Source format"
+// @Param to path string true "Target format"
+// @Success 200 {object} ConversionSchemaResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /v1/conversions/{from}/{to} [get]
+func conversionSchemaHandler(c *gin.Context) {
+	from := normalizeFormat(c.Param("from"))
+	to := normalizeFormat(c.Param("to"))
+
+	schema, ok := converter.SchemaFor(from, to)
+	if !ok {
+		writeError(c, http.StatusNotFound, "unsupported_conversion_pair", fmt.Sprintf("conversion from %s to %s is not supported", from, to))
+		return
+	}
+
+	options := make([]ConversionOptionSchema, 0, len(schema.Options))
+	for _, option := range schema.Options {
+		options = append(options, ConversionOptionSchema{
+			Name:    option.Name,
+			Type:    option.Type,
+			Minimum: option.Minimum,
+			Maximum: option.Maximum,
+			Enum:    option.Enum,
+			Default: option.Default,
+		})
+	}
+
+	c.JSON(http.StatusOK, ConversionSchemaResponse{
+		From:    from,
+		To:      to,
+		Route:   schema.Route,
+		Lossy:   schema.Lossy,
+		Options: options,
 	})
 }
@@ -0,0 +1,219 @@
+//go:build linux
+
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"goconverter/internal/converter"
+
+	"github.com/gin-gonic/gin"
+)
+
+// peakRSSEndpointEnv makes the test binary serve one benchmark request on
+// the named endpoint and exit, so its peak RSS covers that request alone.
+// peakRSSInputEnv names the PNG file it converts.
+const (
+	peakRSSEndpointEnv = "GOCONVERTER_PEAK_RSS_ENDPOINT"
+	peakRSSInputEnv    = "GOCONVERTER_PEAK_RSS_INPUT"
+)
+
+// peakRSSImageSide makes an uncompressed RGB PNG of about 40MB.
+const peakRSSImageSide = 3650
+
+const (
+	peakRSSBuffered = "buffered"
+	peakRSSJSON     = "json"
+	peakRSSUpload   = "upload"
+)
+
+func TestMain(m *testing.M) {
+	if endpoint := os.Getenv(peakRSSEndpointEnv); endpoint != "" {
+		if err := servePeakRSSRequest(endpoint, os.Getenv(peakRSSInputEnv)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// BenchmarkConvertPeakRSS reports the peak RSS of a process converting one
+// 40MB PNG to JPEG through libvips on each endpoint. buffered is the JSON
+// endpoint as it was before it streamed, the baseline json compares to.
+func BenchmarkConvertPeakRSS(b *testing.B) {
+	input := filepath.Join(b.TempDir(), "input.png")
+	if err := writePeakRSSInput(input); err != nil {
+		b.Fatalf("failed to write the benchmark input: %v", err)
+	}
+
+	for _, endpoint := range []string{peakRSSBuffered, peakRSSJSON, peakRSSUpload} {
+		b.Run(endpoint, func(b *testing.B) {
+			var peakKB int64
+			for range b.N {
+				cmd := exec.Command(os.Args[0])
+				cmd.Env = append(os.Environ(), peakRSSEndpointEnv+"="+endpoint, peakRSSInputEnv+"="+input)
+				if output, err := cmd.CombinedOutput(); err != nil {
+					b.Fatalf("benchmark request failed: %v\n%s", err, output)
+				}
+				peakKB = max(peakKB, cmd.ProcessState.SysUsage().(*syscall.Rusage).Maxrss)
+			}
+			b.ReportMetric(float64(peakKB)/1024, "peak-MB")
+		})
+	}
+}
+
+// writePeakRSSInput writes noise, which libvips cannot skip over while
+// decoding, stored without compression so the file size is known.
+func writePeakRSSInput(path string) error {
+	img := image.NewRGBA(image.Rect(0, 0, peakRSSImageSide, peakRSSImageSide))
+	random := rand.New(rand.NewSource(1))
+	random.Read(img.Pix)
+	for index := 3; index < len(img.Pix); index += 4 {
+		img.Pix[index] = 255
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	encoder := png.Encoder{CompressionLevel: png.NoCompression}
+	if err := encoder.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func servePeakRSSRequest(endpoint string, inputPath string) error {
+	if backends := converter.ConversionBackends()["png"]["jpeg"]; len(backends) == 0 || backends[0] != converter.BackendVips {
+		return fmt.Errorf("expected libvips to convert png to jpeg first, got %v", backends)
+	}
+	input, err := os.Open(inputPath)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	info, err := input.Stat()
+	if err != nil {
+		return err
+	}
+	router := newTestRouter()
+	router.POST("/buffered", requestBodyLimitMiddleware(), bufferedConvertHandler)
+
+	var req *http.Request
+	switch endpoint {
+	case peakRSSBuffered, peakRSSJSON:
+		prefix := `{"from":"png","to":"jpeg","fileName":"input.png","contentBase64":"`
+		body, w := io.Pipe()
+		go func() {
+			io.WriteString(w, prefix)
+			encoder := base64.NewEncoder(base64.StdEncoding, w)
+			io.Copy(encoder, input)
+			encoder.Close()
+			w.CloseWithError(writeString(w, `"}`))
+		}()
+		path := "/v1/convert"
+		if endpoint == peakRSSBuffered {
+			path = "/buffered"
+		}
+		req = httptest.NewRequest(http.MethodPost, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = int64(len(prefix)+base64.StdEncoding.EncodedLen(int(info.Size()))) + 2
+	case peakRSSUpload:
+		req = httptest.NewRequest(http.MethodPost, "/v1/convert/png/jpeg", input)
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.ContentLength = info.Size()
+	default:
+		return fmt.Errorf("unknown endpoint %q", endpoint)
+	}
+
+	w := &discardResponseWriter{header: http.Header{}}
+	router.ServeHTTP(w, req)
+	if w.status != http.StatusOK {
+		return fmt.Errorf("expected status 200, got %d", w.status)
+	}
+
+	return nil
+}
+
+func writeString(w io.Writer, s string) error {
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+// bufferedConvertHandler holds what the JSON handler held before it
+// streamed: the bound request with its base64 content, the input decoded
+// with DecodeString, the output of ConvertWithOptions and the response
+// marshalled around EncodeToString.
+func bufferedConvertHandler(c *gin.Context) {
+	var request ConvertRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "invalid JSON request body")
+		return
+	}
+	input, err := base64.StdEncoding.DecodeString(strings.TrimSpace(request.ContentBase64))
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_base64", "contentBase64 must be valid base64")
+		return
+	}
+	plan, ok := converter.PlanConversion(request.From, request.To)
+	if !ok {
+		writeError(c, http.StatusUnsupportedMediaType, "unsupported_conversion_pair", "conversion is not supported")
+		return
+	}
+	result, err := plan.ConvertWithOptions(c.Request.Context(), input, converter.Options{})
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, "conversion_failed", "failed to convert file")
+		return
+	}
+
+	c.JSON(http.StatusOK, ConvertResponse{
+		From:          request.From,
+		To:            request.To,
+		FileName:      outputFileName(request.FileName, request.To),
+		MimeType:      mimeTypeByFormat(request.To),
+		ContentBase64: base64.StdEncoding.EncodeToString(result.Output),
+		Route:         plan.Route(),
+		Backends:      result.Backends,
+	})
+}
+
+// discardResponseWriter drops the response body, which a real server hands
+// to the connection.
+type discardResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return len(p), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"goconverter/internal/converter"
 
@@ -16,73 +19,246 @@ import (
 // @Tags conversions
 // @Accept json
 // @Produce json
+// @Param X-Request-Timeout header int false "Conversion deadline in milliseconds; the shorter of this and timeoutMs applies"
 // @Param request body ConvertRequest true "Conversion request"
-// @Success 200 {object} ConvertResponse
+// @Success 200 {object} ConvertResponse "converted file, or ConvertExplainResponse when explain is set"
 // @Failure 400 {object} ErrorResponse
 // @Failure 413 {object} ErrorResponse
 // @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 503 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /v1/convert [post]
 func convertHandler(c *gin.Context) {
 	var request ConvertRequest
-	if err := c.ShouldBindJSON(&request); err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "request body too large") {
-			writeError(c, http.StatusRequestEntityTooLarge, "payload_too_large", "request body exceeds configured size limit")
-			return
-		}
-		writeError(c, http.StatusBadRequest, "invalid_request", "invalid JSON request body")
+	var content requestContent
+	defer content.release()
+	if err := decodeContentRequest(c.Request.Body, c.Request.ContentLength, &request, &content); err != nil {
+		writeBodyReadError(c, err, "invalid JSON request body")
+		return
+	}
+	timeout, ok := conversionTimeout(c, request.TimeoutMs, maxConversionTimeout)
+	if !ok {
 		return
 	}
 
 	from := normalizeFormat(request.From)
 	to := normalizeFormat(request.To)
+	detectSource := from == "" || from == sourceFormatAuto
 	markConversionFormats(c, from, to)
 	fileName := strings.TrimSpace(request.FileName)
-	contentBase64 := strings.TrimSpace(request.ContentBase64)
-	if from == "" || to == "" || fileName == "" || contentBase64 == "" {
-		writeError(c, http.StatusBadRequest, "invalid_request", "from, to, fileName, and contentBase64 are required")
+	switch {
+	case request.Explain && detectSource:
+		if to == "" || !content.present {
+			writeError(c, http.StatusBadRequest, "invalid_request", "to and contentBase64 are required to explain a detected source")
+			return
+		}
+	case request.Explain:
+		if to == "" {
+			writeError(c, http.StatusBadRequest, "invalid_request", "to is required")
+			return
+		}
+	case to == "" || fileName == "" || !content.present:
+		writeError(c, http.StatusBadRequest, "invalid_request", "to, fileName, and contentBase64 are required")
 		return
 	}
 
-	converterImplementation, ok := converter.FindConverter(from, to)
-	if !ok {
-		writeError(c, http.StatusUnsupportedMediaType, "unsupported_conversion_pair", fmt.Sprintf("conversion from %s to %s is not supported", from, to))
+	pageOutput := strings.ToLower(strings.TrimSpace(request.PageOutput))
+	if pageOutput == "" {
+		pageOutput = pageOutputArray
+	}
+	if pageOutput != pageOutputArray && pageOutput != pageOutputZip {
+		writeError(c, http.StatusBadRequest, "invalid_options", "pageOutput must be one of array, zip")
 		return
 	}
 
-	if len(contentBase64) > base64.StdEncoding.EncodedLen(maxDecodedFileSizeBytes) {
-		writeError(c, http.StatusRequestEntityTooLarge, "payload_too_large", "decoded input file exceeds 50MB limit")
-		return
+	options := converterOptions(request)
+	var plan converter.Plan
+	if !detectSource {
+		var ok bool
+		plan, ok = findConverterWithOptions(c, from, to, options)
+		if !ok {
+			return
+		}
+		if request.Explain {
+			writeExplainResponse(c, plan, "")
+			return
+		}
 	}
 
-	inputBytes, err := base64.StdEncoding.DecodeString(contentBase64)
-	if err != nil {
+	if content.tooLarge {
+		writeError(c, http.StatusRequestEntityTooLarge, "payload_too_large", sizeLimitExceeded("decoded input file exceeds"))
+		return
+	}
+	if content.invalid {
 		writeError(c, http.StatusBadRequest, "invalid_base64", "contentBase64 must be valid base64")
 		return
 	}
-	if len(inputBytes) > maxDecodedFileSizeBytes {
-		writeError(c, http.StatusRequestEntityTooLarge, "payload_too_large", "decoded input file exceeds 50MB limit")
+	inputBytes := content.bytes()
+
+	detection, detected := converter.DetectFormat(inputBytes)
+	if detectSource {
+		if !detected {
+			writeError(c, http.StatusUnsupportedMediaType, "unsupported_source_format", "could not detect the source format; set from explicitly")
+			return
+		}
+		from = detection.Format
+		markConversionFormats(c, from, to)
+
+		var ok bool
+		plan, ok = findConverterWithOptions(c, from, to, options)
+		if !ok {
+			return
+		}
+		if request.Explain {
+			writeExplainResponse(c, plan, detection.Format)
+			return
+		}
+	} else if !checkDeclaredFormat(c, from, detection, detected) {
+		return
+	}
+
+	output := getBuffer()
+	defer putBuffer(output)
+	result, ok := convertWithinLimits(c, plan, inputBytes, options, timeout, output)
+	if !ok {
+		return
+	}
+	if len(result.Pages) > 0 {
+		writePagesResponse(c, plan, fileName, pageOutput, detection.Format, result)
 		return
 	}
 
+	writeContentResponse(c, ConvertResponse{
+		From:            from,
+		To:              to,
+		FileName:        outputFileName(fileName, to),
+		MimeType:        mimeTypeByFormat(to),
+		Geometry:        responseGeometry(result.Geometry),
+		Frames:          result.Frames,
+		MetadataRemoved: result.MetadataRemoved,
+		DetectedFormat:  detection.Format,
+		Route:           plan.Route(),
+		Backends:        result.Backends,
+	}, result.Output)
+}
+
+// convertWithinLimits runs the conversion in a conversion slot and under the
+// request deadline, encoding the output into output when it can. It writes
+// the error response and returns false when the conversion does not succeed.
+func convertWithinLimits(c *gin.Context, plan converter.Plan, input []byte, options converter.Options, timeout time.Duration, output *bytes.Buffer) (converter.Result, bool) {
 	if !tryAcquireConversionSlot() {
 		writeError(c, http.StatusServiceUnavailable, "converter_busy", "converter is busy, retry shortly")
-		return
+		return converter.Result{}, false
 	}
 	defer releaseConversionSlot()
 
-	outputBytes, err := converterImplementation.Convert(inputBytes)
+	// The request context is cancelled when the client disconnects.
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	result, err := runConversion(ctx, plan, input, options, output)
 	if err != nil {
-		writeError(c, http.StatusInternalServerError, "conversion_failed", "failed to convert file")
+		statusCode, code, message := conversionError(err)
+		writeError(c, statusCode, code, message)
+		return converter.Result{}, false
+	}
+	markConversionBackends(c, result.Backends)
+
+	return result, true
+}
+
+// checkDeclaredFormat rejects inputs detected as another format than the one
+// declared. Undetected inputs are left to the converter.
+func checkDeclaredFormat(c *gin.Context, from string, detection converter.Detection, detected bool) bool {
+	if detected && !detection.Matches(from) {
+		writeError(
+			c,
+			http.StatusUnprocessableEntity,
+			"source_format_mismatch",
+			fmt.Sprintf("declared source format %s does not match detected format %s", from, detection.Format),
+		)
+		return false
+	}
+
+	return true
+}
+
+// findConverterWithOptions plans the route between two formats. It writes
+// the error response and returns false when no route exists or the options do
+// not apply to the pair.
+func findConverterWithOptions(c *gin.Context, from string, to string, options converter.Options) (converter.Plan, bool) {
+	plan, ok := converter.PlanConversion(from, to)
+	if !ok {
+		writeError(c, http.StatusUnsupportedMediaType, "unsupported_conversion_pair", fmt.Sprintf("conversion from %s to %s is not supported", from, to))
+		return converter.Plan{}, false
+	}
+
+	if err := converter.ValidateOptions(from, to, options); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_options", err.Error())
+		return converter.Plan{}, false
+	}
+
+	return plan, true
+}
+
+func writeExplainResponse(c *gin.Context, plan converter.Plan, detectedFormat string) {
+	c.JSON(http.StatusOK, ConvertExplainResponse{
+		From:           plan.SourceFormat(),
+		To:             plan.TargetFormat(),
+		Route:          plan.Route(),
+		Cost:           plan.Cost(),
+		Backends:       plan.Backends(),
+		DetectedFormat: detectedFormat,
+	})
+}
+
+func writePagesResponse(c *gin.Context, plan converter.Plan, fileName string, pageOutput string, detectedFormat string, result converter.Result) {
+	from, to := plan.SourceFormat(), plan.TargetFormat()
+	pages := result.Pages
+	if pageOutput == pageOutputZip {
+		archive := getBuffer()
+		defer putBuffer(archive)
+		if err := zipPages(archive, fileName, to, pages); err != nil {
+			writeError(c, http.StatusInternalServerError, "conversion_failed", "failed to archive converted pages")
+			return
+		}
+
+		writeContentResponse(c, ConvertResponse{
+			From:            from,
+			To:              to,
+			FileName:        outputFileName(fileName, "zip"),
+			MimeType:        "application/zip",
+			MetadataRemoved: result.MetadataRemoved,
+			DetectedFormat:  detectedFormat,
+			Route:           plan.Route(),
+			Backends:        result.Backends,
+		}, archive.Bytes())
 		return
 	}
 
+	responsePages := make([]ConvertPage, 0, len(pages))
+	for _, page := range pages {
+		responsePages = append(responsePages, ConvertPage{
+			Page:          page.Page,
+			FileName:      pageFileName(fileName, to, page.Page),
+			MimeType:      mimeTypeByFormat(to),
+			ContentBase64: base64.StdEncoding.EncodeToString(page.Output),
+			Geometry:      *responseGeometry(page.Geometry),
+			Backends:      page.Backends,
+		})
+	}
+
 	c.JSON(http.StatusOK, ConvertResponse{
-		From:          from,
-		To:            to,
-		FileName:      outputFileName(fileName, to),
-		MimeType:      mimeTypeByFormat(to),
-		ContentBase64: base64.StdEncoding.EncodeToString(outputBytes),
+		From:            from,
+		To:              to,
+		FileName:        outputFileName(fileName, to),
+		MimeType:        mimeTypeByFormat(to),
+		Pages:           responsePages,
+		MetadataRemoved: result.MetadataRemoved,
+		DetectedFormat:  detectedFormat,
+		Route:           plan.Route(),
+		Backends:        result.Backends,
 	})
 }
@@ -0,0 +1,337 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"goconverter/internal/converter"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	uploadFileField    = "file"
+	uploadOptionsField = "options"
+	fileNameHeader     = "X-File-Name"
+	optionsHeader      = "X-Convert-Options"
+)
+
+// maxUploadOptionsBytes bounds the JSON options sent next to an upload.
+const maxUploadOptionsBytes = 64 * 1024
+
+// upload is a file sent as multipart/form-data or as the raw request body,
+// with its conversion options.
+type upload struct {
+	fileName string
+	// content is held in buffer, which goes back to the pool with release.
+	content []byte
+	buffer  *bytes.Buffer
+	options ConvertUploadOptions
+}
+
+func (u *upload) release() {
+	putBuffer(u.buffer)
+	u.buffer, u.content = nil, nil
+}
+
+// convertUploadHandler godoc
+// @Summary Convert uploaded file
+// @Description Takes the file as multipart/form-data, in the file field, or as the raw request body, and responds with the converted bytes.
+// @Description Options are ConvertUploadOptions as JSON, the fields of ConvertRequest other than from, to, fileName, contentBase64, pageOutput and explain, which are rejected. They go in the options field of multipart uploads and in the X-Convert-Options header of raw ones.
+// @Description Selecting several pages responds with a zip archive of them.
+// @Tags conversions
+// @Accept multipart/form-data,application/octet-stream
+// @Produce application/octet-stream
+// @Param from path string true "Source format, or auto to detect it"
+// @Param to path string true "Target format"
+// @Param file formData file false "File to convert, for multipart uploads"
+// @Param options formData string false "Conversion options as JSON, for multipart uploads"
+// @Param X-Convert-Options header string false "Conversion options as JSON, for raw uploads"
+// @Param X-File-Name header string false "Name of the uploaded file, for raw uploads"
+// @Param X-Request-Timeout header int false "Conversion deadline in milliseconds; the shorter of this and timeoutMs applies"
+// @Success 200 {file} file "converted file"
+// @Header 200 {string} Content-Disposition "attachment with the converted file name"
+// @Header 200 {string} X-Detected-Format "format detected from the file content"
+// @Header 200 {string} X-Conversion-Route "comma separated formats the file went through"
+// @Header 200 {string} X-Conversion-Backends "comma separated backends of each hop"
+// @Header 200 {integer} X-Image-Width "output width in pixels, when known"
+// @Header 200 {integer} X-Image-Height "output height in pixels, when known"
+// @Header 200 {integer} X-Frames "animation frames kept in the output"
+// @Header 200 {string} X-Metadata-Removed "comma separated metadata blocks dropped"
+// @Header 200 {integer} X-Page-Count "pages in the zip archive"
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/convert/{from}/{to} [post]
+func convertUploadHandler(c *gin.Context) {
+	from := normalizeFormat(c.Param("from"))
+	to := normalizeFormat(c.Param("to"))
+	detectSource := from == sourceFormatAuto
+	markConversionFormats(c, from, to)
+
+	file, ok := readUpload(c)
+	if !ok {
+		return
+	}
+	defer file.release()
+	timeout, ok := conversionTimeout(c, file.options.TimeoutMs, maxConversionTimeout)
+	if !ok {
+		return
+	}
+
+	detection, detected := converter.DetectFormat(file.content)
+	if detectSource {
+		if !detected {
+			writeError(c, http.StatusUnsupportedMediaType, "unsupported_source_format", "could not detect the source format; set from explicitly")
+			return
+		}
+		from = detection.Format
+		markConversionFormats(c, from, to)
+	} else if !checkDeclaredFormat(c, from, detection, detected) {
+		return
+	}
+
+	options := converterOptions(file.options.request())
+	plan, ok := findConverterWithOptions(c, from, to, options)
+	if !ok {
+		return
+	}
+	output := getBuffer()
+	result, ok := convertWithinLimits(c, plan, file.content, options, timeout, output)
+	if !ok {
+		putBuffer(output)
+		return
+	}
+
+	detectedFormat := ""
+	if detected {
+		detectedFormat = detection.Format
+	}
+	converted, err := newConvertedFile(plan, file.fileName, detectedFormat, result, output)
+	defer converted.release()
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, "conversion_failed", "failed to archive converted pages")
+		return
+	}
+	converted.write(c)
+}
+
+// convertedFile is a conversion result as the binary endpoints send it:
+// the file, with the headers describing the conversion.
+type convertedFile struct {
+	fileName string
+	mimeType string
+	header   http.Header
+	content  []byte
+	// buffer holds content, and goes back to the pool with release.
+	buffer *bytes.Buffer
+}
+
+// newConvertedFile takes over output, the buffer the conversion wrote to.
+// Several pages are archived into a zip file.
+func newConvertedFile(plan converter.Plan, fileName string, detectedFormat string, result converter.Result, output *bytes.Buffer) (convertedFile, error) {
+	to := plan.TargetFormat()
+	header := http.Header{}
+	if detectedFormat != "" {
+		header.Set("X-Detected-Format", detectedFormat)
+	}
+	header.Set("X-Conversion-Route", strings.Join(plan.Route(), ","))
+	header.Set("X-Conversion-Backends", strings.Join(result.Backends, ","))
+	if len(result.MetadataRemoved) > 0 {
+		header.Set("X-Metadata-Removed", strings.Join(result.MetadataRemoved, ","))
+	}
+
+	if len(result.Pages) > 0 {
+		output.Reset()
+		if err := zipPages(output, fileName, to, result.Pages); err != nil {
+			return convertedFile{buffer: output}, err
+		}
+		header.Set("X-Page-Count", strconv.Itoa(len(result.Pages)))
+		return convertedFile{
+			fileName: outputFileName(fileName, "zip"),
+			mimeType: "application/zip",
+			header:   header,
+			content:  output.Bytes(),
+			buffer:   output,
+		}, nil
+	}
+
+	if result.Geometry.Width > 0 && result.Geometry.Height > 0 {
+		header.Set("X-Image-Width", strconv.Itoa(result.Geometry.Width))
+		header.Set("X-Image-Height", strconv.Itoa(result.Geometry.Height))
+	}
+	if result.Frames > 0 {
+		header.Set("X-Frames", strconv.Itoa(result.Frames))
+	}
+	return convertedFile{
+		fileName: outputFileName(fileName, to),
+		mimeType: mimeTypeByFormat(to),
+		header:   header,
+		content:  result.Output,
+		buffer:   output,
+	}, nil
+}
+
+func (f *convertedFile) write(c *gin.Context) {
+	for name, values := range f.header {
+		c.Writer.Header()[name] = values
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": f.fileName}))
+	c.Data(http.StatusOK, f.mimeType, f.content)
+}
+
+func (f *convertedFile) release() {
+	putBuffer(f.buffer)
+	f.buffer, f.content = nil, nil
+}
+
+// readUpload reads the file and its options. The decoded size limit applies
+// to the file itself, since nothing is encoded. It writes the error response
+// and returns false for invalid uploads.
+func readUpload(c *gin.Context) (upload, bool) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		return readMultipartUpload(c)
+	}
+
+	file := upload{fileName: strings.TrimSpace(c.GetHeader(fileNameHeader))}
+	if !decodeUploadOptions(c, []byte(c.GetHeader(optionsHeader)), &file.options) {
+		return upload{}, false
+	}
+	buffer, ok := readUploadedFile(c, c.Request.Body)
+	if !ok {
+		return upload{}, false
+	}
+	if buffer.Len() == 0 {
+		putBuffer(buffer)
+		writeError(c, http.StatusBadRequest, "invalid_request", "request body is empty")
+		return upload{}, false
+	}
+	file.buffer, file.content = buffer, buffer.Bytes()
+
+	return file, true
+}
+
+func readMultipartUpload(c *gin.Context) (upload, bool) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "invalid multipart request body")
+		return upload{}, false
+	}
+
+	var file upload
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			writeBodyReadError(c, err, "invalid multipart request body")
+			return upload{}, false
+		}
+
+		switch part.FormName() {
+		case uploadFileField:
+			file.release()
+			buffer, ok := readUploadedFile(c, part)
+			if !ok {
+				return upload{}, false
+			}
+			file.fileName = strings.TrimSpace(part.FileName())
+			file.buffer, file.content = buffer, buffer.Bytes()
+		case uploadOptionsField:
+			options, err := io.ReadAll(io.LimitReader(part, maxUploadOptionsBytes+1))
+			if err != nil {
+				writeBodyReadError(c, err, "invalid multipart request body")
+				return upload{}, false
+			}
+			if !decodeUploadOptions(c, options, &file.options) {
+				return upload{}, false
+			}
+		}
+		part.Close()
+	}
+
+	if len(file.content) == 0 {
+		file.release()
+		writeError(c, http.StatusBadRequest, "invalid_request", fmt.Sprintf("%s is required", uploadFileField))
+		return upload{}, false
+	}
+
+	return file, true
+}
+
+// readUploadedFile reads the file into a pooled buffer sized from the
+// request length, so it is not copied while it grows.
+func readUploadedFile(c *gin.Context, r io.Reader) (*bytes.Buffer, bool) {
+	buffer := getBuffer()
+	growBuffer(buffer, c.Request.ContentLength)
+	if _, err := buffer.ReadFrom(io.LimitReader(r, int64(maxDecodedFileSizeBytes)+1)); err != nil {
+		putBuffer(buffer)
+		writeBodyReadError(c, err, "failed to read the uploaded file")
+		return nil, false
+	}
+	if buffer.Len() > maxDecodedFileSizeBytes {
+		putBuffer(buffer)
+		writeError(c, http.StatusRequestEntityTooLarge, "payload_too_large", sizeLimitExceeded("uploaded file exceeds"))
+		return nil, false
+	}
+
+	return buffer, true
+}
+
+// request places the options in the request they are a part of.
+func (o ConvertUploadOptions) request() ConvertRequest {
+	return ConvertRequest{
+		Options:    o.Options,
+		Transform:  o.Transform,
+		Rasterize:  o.Rasterize,
+		Pages:      o.Pages,
+		Frame:      o.Frame,
+		Metadata:   o.Metadata,
+		ColorSpace: o.ColorSpace,
+		ICCProfile: o.ICCProfile,
+		AutoRotate: o.AutoRotate,
+		TimeoutMs:  o.TimeoutMs,
+	}
+}
+
+// decodeUploadOptions rejects fields other than conversion options, so the
+// path and file cannot be contradicted by fields that would be ignored.
+func decodeUploadOptions(c *gin.Context, options []byte, output *ConvertUploadOptions) bool {
+	if len(strings.TrimSpace(string(options))) == 0 {
+		return true
+	}
+	if len(options) > maxUploadOptionsBytes {
+		writeError(c, http.StatusBadRequest, "invalid_request", "options must be a JSON object")
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(options))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(output)
+	if err == nil && decoder.More() {
+		err = errors.New("trailing data after the options object")
+	}
+	if field, ok := strings.CutPrefix(fmt.Sprint(err), "json: unknown field "); ok {
+		writeError(c, http.StatusBadRequest, "invalid_request", fmt.Sprintf("options field %s is not a conversion option", field))
+		return false
+	}
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "options must be a JSON object")
+		return false
+	}
+
+	return true
+}
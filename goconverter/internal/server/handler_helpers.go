@@ -1,10 +1,22 @@
 package server
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"goconverter/internal/converter"
+	"goconverter/internal/worker"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,16 +26,17 @@ const (
 	errorCodeContextKey  = "error_code"
 	fromFormatContextKey = "from"
 	toFormatContextKey   = "to"
+	backendsContextKey   = "backends"
+	jobIDContextKey      = "job_id"
 )
 
 var conversionConcurrencyMu sync.Mutex
 var currentConcurrentConversions int
 
-var canonicalFormatAliases = map[string][]string{
-	"heif": {"heic"},
-	"jpeg": {"jpg"},
-	"tiff": {"tif"},
-}
+// conversionSlotReleased is closed, and replaced, whenever a slot frees up.
+var conversionSlotReleased = make(chan struct{})
+
+var canonicalFormatAliases = buildCanonicalFormatAliases()
 
 var aliasToCanonicalFormat = buildAliasToCanonicalFormat()
 
@@ -97,6 +110,16 @@ func expandConversionFormatsWithAliases(canonicalFormats map[string][]string) ma
 	return output
 }
 
+func buildCanonicalFormatAliases() map[string][]string {
+	output := map[string][]string{}
+	for _, format := range converter.Formats() {
+		if len(format.Aliases) > 0 {
+			output[format.Name] = format.Aliases
+		}
+	}
+	return output
+}
+
 func buildAliasToCanonicalFormat() map[string]string {
 	output := map[string]string{}
 	for canonical, aliases := range canonicalFormatAliases {
@@ -123,24 +146,107 @@ func outputFileName(inputFileName string, targetFormat string) string {
 	return base + "." + targetFormat
 }
 
+func pageFileName(inputFileName string, targetFormat string, page int) string {
+	name := outputFileName(inputFileName, targetFormat)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-page-" + strconv.Itoa(page) + ext
+}
+
+func zipPages(w io.Writer, inputFileName string, targetFormat string, pages []converter.PageResult) error {
+	archive := zip.NewWriter(w)
+	for _, page := range pages {
+		// Encoded images are already compressed, so store them as-is.
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:   pageFileName(inputFileName, targetFormat, page.Page),
+			Method: zip.Store,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(page.Output); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
 func mimeTypeByFormat(format string) string {
-	switch canonicalFormat(format) {
-	case "jpeg":
-		return "image/jpeg"
-	case "avif":
-		return "image/avif"
-	case "gif":
-		return "image/gif"
-	case "heif":
-		return "image/heif"
-	case "png":
-		return "image/png"
-	case "tiff":
-		return "image/tiff"
-	case "webp":
-		return "image/webp"
-	default:
-		return "application/octet-stream"
+	if details, ok := converter.LookupFormat(canonicalFormat(format)); ok {
+		return details.MimeType
+	}
+
+	return "application/octet-stream"
+}
+
+func converterOptions(request ConvertRequest) converter.Options {
+	output := converter.Options{
+		Pages:      strings.TrimSpace(request.Pages),
+		Frame:      request.Frame,
+		Metadata:   strings.ToLower(strings.TrimSpace(request.Metadata)),
+		ColorSpace: strings.ToLower(strings.TrimSpace(request.ColorSpace)),
+		ICCProfile: strings.ToLower(strings.TrimSpace(request.ICCProfile)),
+	}
+	if request.AutoRotate != nil {
+		output.DisableAutoRotate = !*request.AutoRotate
+	}
+	if options := request.Options; options != nil {
+		output.Quality = options.Quality
+		output.Lossless = options.Lossless
+		output.Effort = options.Effort
+		output.Progressive = options.Progressive
+		output.ChromaSubsampling = strings.TrimSpace(options.ChromaSubsampling)
+		output.CompressionLevel = options.CompressionLevel
+		output.DPI = options.DPI
+		output.PageSize = strings.ToLower(strings.TrimSpace(options.PageSize))
+	}
+
+	if transform := request.Transform; transform != nil {
+		output.Transform = converter.Transform{
+			Width:              transform.Width,
+			Height:             transform.Height,
+			Fit:                strings.ToLower(strings.TrimSpace(transform.Fit)),
+			Gravity:            strings.ToLower(strings.TrimSpace(transform.Gravity)),
+			WithoutEnlargement: transform.WithoutEnlargement,
+			Background:         strings.TrimSpace(transform.Background),
+		}
+	}
+
+	if rasterize := request.Rasterize; rasterize != nil {
+		output.Rasterize = converter.Rasterization{
+			DPI:        rasterize.DPI,
+			Scale:      rasterize.Scale,
+			Width:      rasterize.Width,
+			Height:     rasterize.Height,
+			Background: strings.TrimSpace(rasterize.Background),
+			PDFBox:     strings.ToLower(strings.TrimSpace(rasterize.PDFBox)),
+		}
+	}
+
+	return output
+}
+
+func combineOptions(request CombineRequest) converter.CombineOptions {
+	return converter.CombineOptions{
+		PageSize:    strings.ToLower(strings.TrimSpace(request.PageSize)),
+		Orientation: strings.ToLower(strings.TrimSpace(request.Orientation)),
+		MarginMm:    request.MarginMm,
+		Fit:         strings.ToLower(strings.TrimSpace(request.Fit)),
+		DPI:         request.DPI,
+		JPEGQuality: request.JPEGQuality,
+	}
+}
+
+func responseGeometry(geometry converter.Geometry) *ConvertGeometry {
+	return &ConvertGeometry{
+		SourceWidth:  geometry.SourceWidth,
+		SourceHeight: geometry.SourceHeight,
+		ScaledWidth:  geometry.ScaledWidth,
+		ScaledHeight: geometry.ScaledHeight,
+		Width:        geometry.Width,
+		Height:       geometry.Height,
+		Fit:          geometry.Fit,
+		Gravity:      geometry.Gravity,
 	}
 }
 
@@ -163,6 +269,16 @@ func markConversionFormats(c *gin.Context, from string, to string) {
 	c.Set(toFormatContextKey, to)
 }
 
+func markConversionBackends(c *gin.Context, backends []string) {
+	if len(backends) > 0 {
+		c.Set(backendsContextKey, backends)
+	}
+}
+
+func markJob(c *gin.Context, id string) {
+	c.Set(jobIDContextKey, id)
+}
+
 func conversionFormatsFromContext(c *gin.Context) (string, string) {
 	from := ""
 	if fromAny, ok := c.Get(fromFormatContextKey); ok {
@@ -194,6 +310,24 @@ func tryAcquireConversionSlot() bool {
 	return true
 }
 
+// acquireConversionSlot waits for a free slot until ctx is done.
+func acquireConversionSlot(ctx context.Context) error {
+	for {
+		conversionConcurrencyMu.Lock()
+		released := conversionSlotReleased
+		conversionConcurrencyMu.Unlock()
+		if tryAcquireConversionSlot() {
+			return nil
+		}
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func releaseConversionSlot() {
 	conversionConcurrencyMu.Lock()
 	defer conversionConcurrencyMu.Unlock()
@@ -201,4 +335,134 @@ func releaseConversionSlot() {
 	if currentConcurrentConversions > 0 {
 		currentConcurrentConversions--
 	}
+	close(conversionSlotReleased)
+	conversionSlotReleased = make(chan struct{})
+}
+
+// writeBodyReadError writes the response for request bodies that could not be
+// read or parsed, telling bodies over the size limit apart.
+func writeBodyReadError(c *gin.Context, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(c, http.StatusRequestEntityTooLarge, "payload_too_large", "request body exceeds configured size limit")
+		return
+	}
+	writeError(c, http.StatusBadRequest, "invalid_request", message)
+}
+
+// statusClientClosedRequest is logged for conversions stopped because the
+// client went away; nobody is left to read the response.
+const statusClientClosedRequest = 499
+
+const requestTimeoutHeader = "X-Request-Timeout"
+
+// conversionTimeout picks the shorter of the X-Request-Timeout header and the
+// timeoutMs field, both in milliseconds, capped at limit. It writes the
+// error response and returns false for invalid values.
+func conversionTimeout(c *gin.Context, timeoutMs *int, limit time.Duration) (time.Duration, bool) {
+	timeout := limit
+	if header := strings.TrimSpace(c.GetHeader(requestTimeoutHeader)); header != "" {
+		milliseconds, err := strconv.Atoi(header)
+		if err != nil || milliseconds <= 0 {
+			writeError(c, http.StatusBadRequest, "invalid_request", requestTimeoutHeader+" must be a positive number of milliseconds")
+			return 0, false
+		}
+		timeout = capTimeout(timeout, milliseconds)
+	}
+	if timeoutMs != nil {
+		if *timeoutMs <= 0 {
+			writeError(c, http.StatusBadRequest, "invalid_request", "timeoutMs must be a positive number of milliseconds")
+			return 0, false
+		}
+		timeout = capTimeout(timeout, *timeoutMs)
+	}
+
+	return timeout, true
+}
+
+// capTimeout lowers timeout to the given milliseconds, comparing before
+// converting so that large values cannot overflow into negative durations.
+func capTimeout(timeout time.Duration, milliseconds int) time.Duration {
+	if int64(milliseconds) >= int64(timeout/time.Millisecond) {
+		return timeout
+	}
+
+	return time.Duration(milliseconds) * time.Millisecond
+}
+
+// writeInterruptedError writes the response for conversions that did not
+// run to the end: stopped by their deadline, by the client disconnecting, or
+// by their worker process crashing. It reports whether err was one of them.
+func writeInterruptedError(c *gin.Context, err error) bool {
+	statusCode, code, message, ok := interruptedError(err)
+	if ok {
+		writeError(c, statusCode, code, message)
+	}
+
+	return ok
+}
+
+func interruptedError(err error) (int, string, string, bool) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, "conversion_timeout", "conversion did not finish within the request timeout", true
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest, "request_cancelled", "client closed the request before the conversion finished", true
+	case errors.Is(err, worker.ErrCrashed):
+		return http.StatusInternalServerError, "worker_crashed", "conversion worker crashed while converting the file", true
+	default:
+		return 0, "", "", false
+	}
+}
+
+// conversionError picks the status, error code and message reported for a
+// failed conversion.
+func conversionError(err error) (int, string, string) {
+	if statusCode, code, message, ok := interruptedError(err); ok {
+		return statusCode, code, message
+	}
+
+	switch {
+	case errors.Is(err, converter.ErrInvalidOptions):
+		return http.StatusBadRequest, "invalid_options", err.Error()
+	case errors.Is(err, converter.ErrInputTooComplex):
+		return http.StatusUnprocessableEntity, "input_too_complex", err.Error()
+	default:
+		return http.StatusInternalServerError, "conversion_failed", "failed to convert file"
+	}
+}
+
+// runConversion runs the plan in a worker process when a pool is configured,
+// and in this process otherwise, where the output is encoded straight into
+// output. Either way the returned Output holds the converted file.
+func runConversion(ctx context.Context, plan converter.Plan, input []byte, options converter.Options, output *bytes.Buffer) (converter.Result, error) {
+	if workerPool != nil {
+		return workerPool.Convert(ctx, plan.SourceFormat(), plan.TargetFormat(), input, options)
+	}
+
+	result, err := plan.ConvertTo(ctx, input, options, output)
+	if err != nil || len(result.Pages) > 0 {
+		return result, err
+	}
+	result.Output = output.Bytes()
+
+	return result, nil
+}
+
+func runCombine(ctx context.Context, inputs [][]byte, target string, options converter.CombineOptions) (converter.CombineResult, error) {
+	if workerPool == nil {
+		return converter.Combine(ctx, inputs, target, options)
+	}
+
+	return workerPool.Combine(ctx, inputs, target, options)
+}
+
+// sizeLimitExceeded describes the decoded size limit subject went over.
+func sizeLimitExceeded(subject string) string {
+	limit := fmt.Sprintf("%d bytes", maxDecodedFileSizeBytes)
+	if maxDecodedFileSizeBytes%(1024*1024) == 0 {
+		limit = fmt.Sprintf("%dMB", maxDecodedFileSizeBytes/(1024*1024))
+	}
+
+	return fmt.Sprintf("%s %s limit", subject, limit)
 }
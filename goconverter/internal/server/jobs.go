@@ -0,0 +1,344 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"goconverter/internal/converter"
+)
+
+const (
+	jobQueued    = "queued"
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+	jobCancelled = "cancelled"
+)
+
+var errTooManyJobs = errors.New("too many jobs")
+
+// jobsMu guards the job store: the jobs by ID, the count of active ones, and
+// the succeeded ones holding results, oldest first.
+var jobsMu sync.Mutex
+var jobs = map[string]*job{}
+var activeJobs int
+var retainedJobs []*job
+var retainedResultBytes int64
+
+// job is a conversion running in the background of the request that
+// created it, from POST /v1/jobs until its result expires.
+type job struct {
+	id        string
+	requestID string
+	from      string
+	to        string
+	fileName  string
+	createdAt time.Time
+	cancel    context.CancelFunc
+
+	// expiry and resultBytes are guarded by jobsMu, and set once the job
+	// is finished.
+	expiry      *time.Timer
+	resultBytes int64
+
+	mu         sync.Mutex
+	status     string
+	progress   int
+	startedAt  time.Time
+	finishedAt time.Time
+	expiresAt  time.Time
+	result     convertedFile
+	err        *ErrorDetail
+}
+
+// jobConversion is what a job converts. input goes back to the pool once
+// the conversion is done.
+type jobConversion struct {
+	plan           converter.Plan
+	input          *bytes.Buffer
+	options        converter.Options
+	timeout        time.Duration
+	detectedFormat string
+}
+
+// startJob registers a job and starts converting in the background, as soon
+// as a conversion slot frees up. Queued and running jobs are limited, as each
+// holds its input.
+func startJob(requestID string, fileName string, conversion jobConversion) (*job, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		id:        generateRequestID(),
+		requestID: requestID,
+		from:      conversion.plan.SourceFormat(),
+		to:        conversion.plan.TargetFormat(),
+		fileName:  fileName,
+		createdAt: time.Now().UTC(),
+		cancel:    cancel,
+		status:    jobQueued,
+	}
+
+	jobsMu.Lock()
+	if activeJobs >= maxActiveJobs {
+		jobsMu.Unlock()
+		cancel()
+		return nil, errTooManyJobs
+	}
+	activeJobs++
+	jobs[j.id] = j
+	jobsMu.Unlock()
+
+	j.log("job_queued", nil)
+	go j.run(ctx, conversion)
+
+	return j, nil
+}
+
+func findJob(id string) (*job, bool) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	j, ok := jobs[id]
+	return j, ok
+}
+
+// deleteJob drops a finished job before it expires. It reports false for
+// jobs still queued or running.
+func deleteJob(j *job) bool {
+	j.mu.Lock()
+	finished := j.finished()
+	j.mu.Unlock()
+	if !finished {
+		return false
+	}
+
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	removeJob(j)
+
+	return true
+}
+
+// retainJob keeps a finished job until it expires, evicting the oldest
+// results while those kept take more than the result budget. The newest
+// result is kept even when it alone does. j.mu is held, so the job is
+// counted out of the active ones before it shows as finished.
+func retainJob(j *job) {
+	resultBytes := int64(len(j.result.content))
+
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	activeJobs--
+	if jobs[j.id] != j {
+		return
+	}
+	j.expiry = time.AfterFunc(jobRetention, func() {
+		jobsMu.Lock()
+		defer jobsMu.Unlock()
+
+		if removeJob(j) {
+			j.log("job_expired", map[string]any{"reason": "retention"})
+		}
+	})
+	if resultBytes == 0 {
+		return
+	}
+
+	j.resultBytes = resultBytes
+	retainedJobs = append(retainedJobs, j)
+	retainedResultBytes += resultBytes
+	for retainedResultBytes > maxJobResultBytes && len(retainedJobs) > 1 {
+		oldest := retainedJobs[0]
+		removeJob(oldest)
+		oldest.log("job_expired", map[string]any{"reason": "result_bytes"})
+	}
+}
+
+// removeJob takes a finished job out of the store. jobsMu is held. It
+// reports false when the job was already gone.
+func removeJob(j *job) bool {
+	if jobs[j.id] != j {
+		return false
+	}
+	delete(jobs, j.id)
+	if j.expiry != nil {
+		j.expiry.Stop()
+	}
+	if index := slices.Index(retainedJobs, j); index >= 0 {
+		retainedJobs = slices.Delete(retainedJobs, index, index+1)
+		retainedResultBytes -= j.resultBytes
+	}
+
+	return true
+}
+
+func (j *job) run(ctx context.Context, conversion jobConversion) {
+	defer j.cancel()
+	defer putBuffer(conversion.input)
+
+	if err := acquireConversionSlot(ctx); err != nil {
+		j.fail(err)
+		return
+	}
+	defer releaseConversionSlot()
+	j.start()
+
+	ctx, cancel := context.WithTimeout(ctx, conversion.timeout)
+	defer cancel()
+
+	output := getBuffer()
+	result, err := runConversion(converter.WithProgress(ctx, j.reportProgress), conversion.plan, conversion.input.Bytes(), conversion.options, output)
+	if err != nil {
+		putBuffer(output)
+		j.fail(err)
+		return
+	}
+
+	file, err := newConvertedFile(conversion.plan, j.fileName, conversion.detectedFormat, result, output)
+	if err != nil {
+		file.release()
+		j.fail(err)
+		return
+	}
+	// The result is not pooled again: downloads may still be sending it
+	// when the job expires.
+	file.buffer = nil
+	j.succeed(file)
+}
+
+func (j *job) start() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status = jobRunning
+	j.startedAt = time.Now().UTC()
+	j.log("job_started", nil)
+}
+
+// reportProgress keeps the progress from going back, as reports of pages
+// and libvips threads may arrive out of order. 100 is left to success.
+func (j *job) reportProgress(percent int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.status == jobRunning {
+		j.progress = max(j.progress, min(percent, 99))
+	}
+}
+
+func (j *job) succeed(file convertedFile) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.result = file
+	j.progress = 100
+	j.finish(jobSucceeded)
+	j.log("job_succeeded", nil)
+}
+
+func (j *job) fail(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if errors.Is(err, context.Canceled) {
+		j.err = &ErrorDetail{Code: "job_cancelled", Message: "job was cancelled before the conversion finished", RequestID: j.requestID}
+		j.finish(jobCancelled)
+		j.log("job_cancelled", nil)
+		return
+	}
+
+	_, code, message := conversionError(err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		message = "conversion did not finish within the job timeout"
+	}
+	j.err = &ErrorDetail{Code: code, Message: message, RequestID: j.requestID}
+	j.finish(jobFailed)
+	j.log("job_failed", map[string]any{"error_code": code})
+}
+
+// finish records the end of the job and schedules its expiry. j.mu is held.
+func (j *job) finish(status string) {
+	j.status = status
+	j.finishedAt = time.Now().UTC()
+	j.expiresAt = j.finishedAt.Add(jobRetention)
+	retainJob(j)
+}
+
+func (j *job) finished() bool {
+	return j.status != jobQueued && j.status != jobRunning
+}
+
+// response describes the job as GET /v1/jobs/{id} returns it.
+func (j *job) response() JobResponse {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	response := JobResponse{
+		ID:        j.id,
+		Status:    j.status,
+		Progress:  j.progress,
+		From:      j.from,
+		To:        j.to,
+		FileName:  outputFileName(j.fileName, j.to),
+		CreatedAt: j.createdAt,
+		StartedAt: optionalTime(j.startedAt),
+		Error:     j.err,
+	}
+	if j.finished() {
+		response.FinishedAt = optionalTime(j.finishedAt)
+		response.ExpiresAt = optionalTime(j.expiresAt)
+	}
+	if j.status == jobSucceeded {
+		response.FileName = j.result.fileName
+		response.ResultURL = jobPath(j.id) + "/result"
+	}
+
+	return response
+}
+
+// log writes a lifecycle event of the job next to the request logs. j.mu
+// is held, the job is not shared yet, or it is finished and no longer
+// changes.
+func (j *job) log(event string, fields map[string]any) {
+	payload := map[string]any{
+		"level":      "info",
+		"event":      event,
+		"job_id":     j.id,
+		"request_id": j.requestID,
+		"from":       j.from,
+		"to":         j.to,
+		"status":     j.status,
+	}
+	if !j.finishedAt.IsZero() {
+		payload["duration_ms"] = j.finishedAt.Sub(j.createdAt).Milliseconds()
+	}
+	for name, value := range fields {
+		payload[name] = value
+	}
+
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf(`{"level":"error","message":"failed to encode job log payload","event":%q}`, event)
+		return
+	}
+
+	log.Print(string(encodedPayload))
+}
+
+func jobPath(id string) string {
+	return "/v1/jobs/" + id
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
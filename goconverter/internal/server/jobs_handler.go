@@ -0,0 +1,193 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"goconverter/internal/converter"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createJobHandler godoc
+// @Summary Start conversion job
+// @Description Takes the request of /v1/convert and converts the file in the background, in the conversion slots /v1/convert uses. Poll the job for its status and progress, then download its result.
+// @Description Several pages are converted into a zip archive; pageOutput and explain do not apply.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param X-Request-Timeout header int false "Conversion deadline in milliseconds, counted from when the job starts; the shorter of this and timeoutMs applies"
+// @Param request body ConvertRequest true "Conversion request"
+// @Success 202 {object} JobResponse
+// @Header 202 {string} Location "URL of the job"
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /v1/jobs [post]
+func createJobHandler(c *gin.Context) {
+	var request ConvertRequest
+	var content requestContent
+	defer content.release()
+	if err := decodeContentRequest(c.Request.Body, c.Request.ContentLength, &request, &content); err != nil {
+		writeBodyReadError(c, err, "invalid JSON request body")
+		return
+	}
+	timeout, ok := conversionTimeout(c, request.TimeoutMs, maxJobTimeout)
+	if !ok {
+		return
+	}
+
+	from := normalizeFormat(request.From)
+	to := normalizeFormat(request.To)
+	detectSource := from == "" || from == sourceFormatAuto
+	markConversionFormats(c, from, to)
+	fileName := strings.TrimSpace(request.FileName)
+	if request.Explain {
+		writeError(c, http.StatusBadRequest, "invalid_request", "explain is not supported for jobs")
+		return
+	}
+	if to == "" || fileName == "" || !content.present {
+		writeError(c, http.StatusBadRequest, "invalid_request", "to, fileName, and contentBase64 are required")
+		return
+	}
+
+	options := converterOptions(request)
+	if !detectSource {
+		if _, ok := findConverterWithOptions(c, from, to, options); !ok {
+			return
+		}
+	}
+
+	if content.tooLarge {
+		writeError(c, http.StatusRequestEntityTooLarge, "payload_too_large", sizeLimitExceeded("decoded input file exceeds"))
+		return
+	}
+	if content.invalid {
+		writeError(c, http.StatusBadRequest, "invalid_base64", "contentBase64 must be valid base64")
+		return
+	}
+
+	detection, detected := converter.DetectFormat(content.bytes())
+	if detectSource {
+		if !detected {
+			writeError(c, http.StatusUnsupportedMediaType, "unsupported_source_format", "could not detect the source format; set from explicitly")
+			return
+		}
+		from = detection.Format
+		markConversionFormats(c, from, to)
+	} else if !checkDeclaredFormat(c, from, detection, detected) {
+		return
+	}
+	plan, ok := findConverterWithOptions(c, from, to, options)
+	if !ok {
+		return
+	}
+
+	j, err := startJob(requestIDFromContext(c), fileName, jobConversion{
+		plan:           plan,
+		input:          content.buffer,
+		options:        options,
+		timeout:        timeout,
+		detectedFormat: detection.Format,
+	})
+	if err != nil {
+		writeError(c, http.StatusServiceUnavailable, "too_many_jobs", "too many jobs are queued or running, retry shortly")
+		return
+	}
+	// The job owns the input from now on.
+	content.buffer = nil
+	markJob(c, j.id)
+
+	c.Header("Location", jobPath(j.id))
+	c.JSON(http.StatusAccepted, j.response())
+}
+
+// getJobHandler godoc
+// @Summary Get conversion job
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} JobResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /v1/jobs/{id} [get]
+func getJobHandler(c *gin.Context) {
+	j, ok := jobFromPath(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, j.response())
+}
+
+// jobResultHandler godoc
+// @Summary Download conversion job result
+// @Description Responds with the converted file and the headers of /v1/convert/{from}/{to}, until the job expires.
+// @Tags jobs
+// @Produce application/octet-stream
+// @Param id path string true "Job ID"
+// @Success 200 {file} file "converted file"
+// @Header 200 {string} Content-Disposition "attachment with the converted file name"
+// @Header 200 {string} X-Conversion-Route "comma separated formats the file went through"
+// @Header 200 {string} X-Conversion-Backends "comma separated backends of each hop"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /v1/jobs/{id}/result [get]
+func jobResultHandler(c *gin.Context) {
+	j, ok := jobFromPath(c)
+	if !ok {
+		return
+	}
+
+	j.mu.Lock()
+	status, file := j.status, j.result
+	j.mu.Unlock()
+	switch status {
+	case jobSucceeded:
+		file.write(c)
+	case jobQueued, jobRunning:
+		writeError(c, http.StatusConflict, "job_not_finished", "job has not finished yet")
+	default:
+		writeError(c, http.StatusConflict, "job_not_succeeded", "job did not succeed and has no result")
+	}
+}
+
+// cancelJobHandler godoc
+// @Summary Cancel conversion job
+// @Description Cancels a queued or running job, which is kept as cancelled until it expires, or deletes a finished job and its result.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 202 {object} JobResponse "cancellation requested"
+// @Success 204 "finished job deleted"
+// @Failure 404 {object} ErrorResponse
+// @Router /v1/jobs/{id} [delete]
+func cancelJobHandler(c *gin.Context) {
+	j, ok := jobFromPath(c)
+	if !ok {
+		return
+	}
+
+	if deleteJob(j) {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	j.cancel()
+
+	c.JSON(http.StatusAccepted, j.response())
+}
+
+// jobFromPath finds the job named in the path. It writes the error response
+// and returns false when there is no such job.
+func jobFromPath(c *gin.Context) (*job, bool) {
+	j, ok := findJob(c.Param("id"))
+	if !ok {
+		writeError(c, http.StatusNotFound, "job_not_found", "job does not exist or has expired")
+		return nil, false
+	}
+	markJob(c, j.id)
+	markConversionFormats(c, j.from, j.to)
+
+	return j, true
+}
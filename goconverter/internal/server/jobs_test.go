@@ -0,0 +1,304 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image/jpeg"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestJobEndpointsConvertInTheBackground(t *testing.T) {
+	router := newTestRouter()
+	logs := captureLogs(t)
+
+	w := createTestJob(t, router)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	created := decodeJobResponse(t, w)
+	if created.ID == "" || w.Header().Get("Location") != "/v1/jobs/"+created.ID {
+		t.Fatalf("expected a job ID and its location, got %+v and %q", created, w.Header().Get("Location"))
+	}
+
+	job := waitForJob(t, router, created.ID)
+	if job.Status != jobSucceeded || job.Progress != 100 || job.ResultURL != "/v1/jobs/"+created.ID+"/result" {
+		t.Fatalf("expected a succeeded job with its result, got %+v", job)
+	}
+	if job.FileName != "photo.jpeg" || job.StartedAt == nil || job.FinishedAt == nil || job.ExpiresAt == nil {
+		t.Fatalf("expected the file name and timestamps, got %+v", job)
+	}
+
+	w = serveJobRequest(router, http.MethodGet, job.ResultURL)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Disposition") != "attachment; filename=photo.jpeg" || w.Header().Get("X-Conversion-Route") != "png,jpeg" {
+		t.Fatalf("expected the converted file headers, got %v", w.Header())
+	}
+	if _, err := jpeg.Decode(w.Body); err != nil {
+		t.Fatalf("expected a jpeg body, got error: %v", err)
+	}
+
+	w = serveJobRequest(router, http.MethodDelete, "/v1/jobs/"+created.ID)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w = serveJobRequest(router, http.MethodGet, "/v1/jobs/"+created.ID); w.Code != http.StatusNotFound {
+		t.Fatalf("expected the deleted job to be gone, got %d", w.Code)
+	}
+
+	for _, event := range []string{"job_queued", "job_started", "job_succeeded"} {
+		if !strings.Contains(logs.String(), `"event":"`+event+`"`) {
+			t.Fatalf("expected a %s log line, got:\n%s", event, logs.String())
+		}
+	}
+	if !strings.Contains(logs.String(), `"job_id":"`+created.ID+`"`) {
+		t.Fatalf("expected request logs with the job ID, got:\n%s", logs.String())
+	}
+}
+
+func TestJobEndpointsCancelAJobWaitingForASlot(t *testing.T) {
+	router := newTestRouter()
+	captureLogs(t)
+
+	oldMaxConcurrentConversions := maxConcurrentConversions
+	maxConcurrentConversions = 0
+	t.Cleanup(func() {
+		maxConcurrentConversions = oldMaxConcurrentConversions
+	})
+
+	created := decodeJobResponse(t, createTestJob(t, router))
+	if created.Status != jobQueued {
+		t.Fatalf("expected a queued job, got %+v", created)
+	}
+	w := serveJobRequest(router, http.MethodGet, "/v1/jobs/"+created.ID+"/result")
+	if w.Code != http.StatusConflict || decodeErrorCode(t, w) != "job_not_finished" {
+		t.Fatalf("expected job_not_finished, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = serveJobRequest(router, http.MethodDelete, "/v1/jobs/"+created.ID)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+	job := waitForJob(t, router, created.ID)
+	if job.Status != jobCancelled || job.Error == nil || job.Error.Code != "job_cancelled" {
+		t.Fatalf("expected a cancelled job, got %+v", job)
+	}
+
+	w = serveJobRequest(router, http.MethodGet, "/v1/jobs/"+created.ID+"/result")
+	if w.Code != http.StatusConflict || decodeErrorCode(t, w) != "job_not_succeeded" {
+		t.Fatalf("expected job_not_succeeded, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestJobEndpointsExpireFinishedJobs(t *testing.T) {
+	router := newTestRouter()
+	logs := captureLogs(t)
+
+	oldRetention := jobRetention
+	jobRetention = 10 * time.Millisecond
+	t.Cleanup(func() {
+		jobRetention = oldRetention
+	})
+
+	created := decodeJobResponse(t, createTestJob(t, router))
+	waitForJob(t, router, created.ID)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for serveJobRequest(router, http.MethodGet, "/v1/jobs/"+created.ID).Code != http.StatusNotFound {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the job to expire")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(logs.String(), `"event":"job_expired"`) {
+		t.Fatalf("expected a job_expired log line, got:\n%s", logs.String())
+	}
+}
+
+func TestJobEndpointsRefuseJobsOverTheLimit(t *testing.T) {
+	router := newTestRouter()
+	captureLogs(t)
+
+	oldMaxActiveJobs := maxActiveJobs
+	maxActiveJobs = 0
+	t.Cleanup(func() {
+		maxActiveJobs = oldMaxActiveJobs
+	})
+
+	w := createTestJob(t, router)
+	if w.Code != http.StatusServiceUnavailable || decodeErrorCode(t, w) != "too_many_jobs" {
+		t.Fatalf("expected too_many_jobs, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestJobEndpointsKeepFinishedJobsOutOfTheLimit(t *testing.T) {
+	router := newTestRouter()
+	captureLogs(t)
+
+	oldMaxActiveJobs := maxActiveJobs
+	maxActiveJobs = 1
+	t.Cleanup(func() {
+		maxActiveJobs = oldMaxActiveJobs
+	})
+
+	for range 3 {
+		w := createTestJob(t, router)
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+		}
+		if job := waitForJob(t, router, decodeJobResponse(t, w).ID); job.Status != jobSucceeded {
+			t.Fatalf("expected a succeeded job, got %+v", job)
+		}
+	}
+}
+
+func TestJobEndpointsEvictTheOldestResultsOverTheBudget(t *testing.T) {
+	router := newTestRouter()
+	logs := captureLogs(t)
+
+	oldMaxJobResultBytes := maxJobResultBytes
+	maxJobResultBytes = 1
+	t.Cleanup(func() {
+		maxJobResultBytes = oldMaxJobResultBytes
+	})
+
+	first := decodeJobResponse(t, createTestJob(t, router))
+	waitForJob(t, router, first.ID)
+	second := decodeJobResponse(t, createTestJob(t, router))
+	waitForJob(t, router, second.ID)
+
+	if w := serveJobRequest(router, http.MethodGet, "/v1/jobs/"+first.ID); w.Code != http.StatusNotFound {
+		t.Fatalf("expected the oldest job to be evicted, got %d", w.Code)
+	}
+	if w := serveJobRequest(router, http.MethodGet, "/v1/jobs/"+second.ID+"/result"); w.Code != http.StatusOK {
+		t.Fatalf("expected the newest result to be kept, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(logs.String(), `"reason":"result_bytes"`) {
+		t.Fatalf("expected a job_expired log line for the result budget, got:\n%s", logs.String())
+	}
+}
+
+func TestJobEndpointsRejectUnknownJobs(t *testing.T) {
+	router := newTestRouter()
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		w := serveJobRequest(router, method, "/v1/jobs/missing")
+		if w.Code != http.StatusNotFound || decodeErrorCode(t, w) != "job_not_found" {
+			t.Fatalf("%s: expected job_not_found, got %d: %s", method, w.Code, w.Body.String())
+		}
+	}
+}
+
+func createTestJob(t *testing.T, router *gin.Engine) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]string{
+		"from":          "png",
+		"to":            "jpg",
+		"fileName":      "photo.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/jobs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func serveJobRequest(router *gin.Engine, method string, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+	return w
+}
+
+// waitForJob polls the job until it finishes.
+func waitForJob(t *testing.T, router *gin.Engine, id string) JobResponse {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w := serveJobRequest(router, http.MethodGet, "/v1/jobs/"+id)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		job := decodeJobResponse(t, w)
+		if job.Status != jobQueued && job.Status != jobRunning {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the job to finish, got %+v", job)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func decodeJobResponse(t *testing.T, w *httptest.ResponseRecorder) JobResponse {
+	t.Helper()
+
+	var job JobResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+
+	return job
+}
+
+func decodeErrorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var response ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+
+	return response.Error.Code
+}
+
+// captureLogs collects what the request logs and job events print.
+func captureLogs(t *testing.T) *syncBuffer {
+	t.Helper()
+
+	logs := &syncBuffer{}
+	log.SetOutput(logs)
+	t.Cleanup(func() {
+		log.SetOutput(os.Stderr)
+	})
+
+	return logs
+}
+
+type syncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buffer.String()
+}
@@ -54,6 +54,12 @@ func requestLoggingMiddleware(logger *log.Logger) gin.HandlerFunc {
 		if errorCode != "" {
 			payload["error_code"] = errorCode
 		}
+		if backends := c.GetStringSlice(backendsContextKey); len(backends) > 0 {
+			payload["backends"] = backends
+		}
+		if jobID := c.GetString(jobIDContextKey); jobID != "" {
+			payload["job_id"] = jobID
+		}
 
 		encodedPayload, err := json.Marshal(payload)
 		if err != nil {
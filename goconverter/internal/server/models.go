@@ -1,6 +1,11 @@
 package server
 
-import "encoding/base64"
+import (
+	"encoding/base64"
+	"time"
+
+	"goconverter/internal/worker"
+)
 
 type HealthResponse struct {
 	Status string `json:"status" example:"ok"`
@@ -8,33 +13,232 @@ type HealthResponse struct {
 
 type ConversionsResponse struct {
 	Formats map[string][]string `json:"formats"`
+	// Routes maps canonical source and target formats to the planned route.
+	Routes map[string]map[string][]string `json:"routes"`
+	// Backends lists the backends registered for each direct pair, in the
+	// order they are tried.
+	Backends map[string]map[string][]string `json:"backends"`
+	// DeprecatedFormats are sources still converted that have better named
+	// formats, e.g. magick for bmp or psd.
+	DeprecatedFormats []string `json:"deprecatedFormats,omitempty" example:"magick"`
+}
+
+type ConversionSchemaResponse struct {
+	From    string                   `json:"from" example:"png"`
+	To      string                   `json:"to" example:"jpeg"`
+	Route   []string                 `json:"route" example:"png,jpeg"`
+	Lossy   bool                     `json:"lossy" example:"true"`
+	Options []ConversionOptionSchema `json:"options"`
+}
+
+type ConversionOptionSchema struct {
+	Name    string   `json:"name" example:"options.quality"`
+	Type    string   `json:"type" enums:"integer,number,boolean,string" example:"integer"`
+	Minimum *int     `json:"minimum,omitempty" example:"1"`
+	Maximum *int     `json:"maximum,omitempty" example:"100"`
+	Enum    []string `json:"enum,omitempty"`
+	Default any      `json:"default,omitempty" swaggertype:"string" example:"75"`
 }
 
 type ConvertRequest struct {
-	From          string `json:"from" example:"png"`
-	To            string `json:"to" example:"jpeg"`
-	FileName      string `json:"fileName" example:"input.png"`
-	ContentBase64 string `json:"contentBase64"`
+	From          string            `json:"from,omitempty" example:"auto"`
+	To            string            `json:"to" example:"jpeg"`
+	FileName      string            `json:"fileName" example:"input.png"`
+	ContentBase64 string            `json:"contentBase64"`
+	Options       *ConvertOptions   `json:"options,omitempty"`
+	Transform     *ConvertTransform `json:"transform,omitempty"`
+	Rasterize     *ConvertRasterize `json:"rasterize,omitempty"`
+	Pages         string            `json:"pages,omitempty" example:"1,3-5"`
+	PageOutput    string            `json:"pageOutput,omitempty" enums:"array,zip" example:"array"`
+	Frame         *int              `json:"frame,omitempty" example:"1"`
+	Metadata      string            `json:"metadata,omitempty" enums:"keep,strip,strip-private,keep-icc" example:"strip-private"`
+	ColorSpace    string            `json:"colorSpace,omitempty" enums:"srgb,p3,keep" example:"srgb"`
+	ICCProfile    string            `json:"iccProfile,omitempty" enums:"embed,omit" example:"embed"`
+	AutoRotate    *bool             `json:"autoRotate,omitempty" example:"true"`
+	Explain       bool              `json:"explain,omitempty" example:"false"`
+	TimeoutMs     *int              `json:"timeoutMs,omitempty" example:"30000"`
+}
+
+// ConvertUploadOptions are the options of the binary upload endpoint, the
+// fields of ConvertRequest that do not describe the file or the response.
+type ConvertUploadOptions struct {
+	Options    *ConvertOptions   `json:"options,omitempty"`
+	Transform  *ConvertTransform `json:"transform,omitempty"`
+	Rasterize  *ConvertRasterize `json:"rasterize,omitempty"`
+	Pages      string            `json:"pages,omitempty" example:"1,3-5"`
+	Frame      *int              `json:"frame,omitempty" example:"1"`
+	Metadata   string            `json:"metadata,omitempty" enums:"keep,strip,strip-private,keep-icc" example:"strip-private"`
+	ColorSpace string            `json:"colorSpace,omitempty" enums:"srgb,p3,keep" example:"srgb"`
+	ICCProfile string            `json:"iccProfile,omitempty" enums:"embed,omit" example:"embed"`
+	AutoRotate *bool             `json:"autoRotate,omitempty" example:"true"`
+	TimeoutMs  *int              `json:"timeoutMs,omitempty" example:"30000"`
+}
+
+type ConvertOptions struct {
+	Quality           *int   `json:"quality,omitempty" example:"80"`
+	Lossless          bool   `json:"lossless,omitempty" example:"false"`
+	Effort            *int   `json:"effort,omitempty" example:"4"`
+	Progressive       bool   `json:"progressive,omitempty" example:"false"`
+	ChromaSubsampling string `json:"chromaSubsampling,omitempty" enums:"auto,4:2:0,4:4:4" example:"4:2:0"`
+	CompressionLevel  *int   `json:"compressionLevel,omitempty" example:"6"`
+	DPI               *int   `json:"dpi,omitempty" example:"300"`
+	PageSize          string `json:"pageSize,omitempty" enums:"auto,a3,a4,a5,letter,legal" example:"a4"`
+}
+
+type ConvertTransform struct {
+	Width              int    `json:"width,omitempty" example:"320"`
+	Height             int    `json:"height,omitempty" example:"240"`
+	Fit                string `json:"fit,omitempty" enums:"contain,cover,fill,inside,outside" example:"cover"`
+	Gravity            string `json:"gravity,omitempty" enums:"centre,north,northeast,east,southeast,south,southwest,west,northwest,attention,entropy" example:"attention"`
+	WithoutEnlargement bool   `json:"withoutEnlargement,omitempty" example:"true"`
+	Background         string `json:"background,omitempty" example:"#ffffff"`
+}
+
+// ConvertRasterize renders SVG and PDF sources. Width or height replace dpi
+// and scale; the page fits inside both when both are set.
+type ConvertRasterize struct {
+	DPI        *int     `json:"dpi,omitempty" example:"300"`
+	Scale      *float64 `json:"scale,omitempty" example:"2"`
+	Width      int      `json:"width,omitempty" example:"1024"`
+	Height     int      `json:"height,omitempty" example:"768"`
+	Background string   `json:"background,omitempty" example:"#ffffff"`
+	PDFBox     string   `json:"pdfBox,omitempty" enums:"media,crop,trim" example:"crop"`
+}
+
+type ConvertGeometry struct {
+	SourceWidth  int    `json:"sourceWidth" example:"1920"`
+	SourceHeight int    `json:"sourceHeight" example:"1080"`
+	ScaledWidth  int    `json:"scaledWidth" example:"427"`
+	ScaledHeight int    `json:"scaledHeight" example:"240"`
+	Width        int    `json:"width" example:"320"`
+	Height       int    `json:"height" example:"240"`
+	Fit          string `json:"fit,omitempty" example:"cover"`
+	Gravity      string `json:"gravity,omitempty" example:"attention"`
+}
+
+type ConvertPage struct {
+	Page          int             `json:"page" example:"2"`
+	FileName      string          `json:"fileName" example:"input-page-2.png"`
+	MimeType      string          `json:"mimeType" example:"image/png"`
+	ContentBase64 string          `json:"contentBase64"`
+	Geometry      ConvertGeometry `json:"geometry"`
+	Backends      []string        `json:"backends,omitempty" example:"libvips,libvips"`
 }
 
 type ConvertResponse struct {
-	From          string `json:"from" example:"png"`
-	To            string `json:"to" example:"jpeg"`
-	FileName      string `json:"fileName" example:"input.jpeg"`
-	MimeType      string `json:"mimeType" example:"image/jpeg"`
+	From            string           `json:"from" example:"png"`
+	To              string           `json:"to" example:"jpeg"`
+	FileName        string           `json:"fileName" example:"input.jpeg"`
+	MimeType        string           `json:"mimeType" example:"image/jpeg"`
+	ContentBase64   string           `json:"contentBase64"`
+	Geometry        *ConvertGeometry `json:"geometry,omitempty"`
+	Pages           []ConvertPage    `json:"pages,omitempty"`
+	Frames          int              `json:"frames,omitempty" example:"12"`
+	MetadataRemoved []string         `json:"metadataRemoved,omitempty" example:"exif-gps,xmp"`
+	DetectedFormat  string           `json:"detectedFormat,omitempty" example:"png"`
+	Route           []string         `json:"route,omitempty" example:"pdf,png,jpeg"`
+	Backends        []string         `json:"backends,omitempty" example:"libvips,libvips"`
+}
+
+type ConvertExplainResponse struct {
+	From           string   `json:"from" example:"pdf"`
+	To             string   `json:"to" example:"jpeg"`
+	Route          []string `json:"route" example:"pdf,png,jpeg"`
+	Cost           int      `json:"cost" example:"12"`
+	Backends       []string `json:"backends" example:"libvips,libvips"`
+	DetectedFormat string   `json:"detectedFormat,omitempty" example:"pdf"`
+}
+
+type CombineRequest struct {
+	To          string         `json:"to" enums:"pdf,tiff" example:"pdf"`
+	FileName    string         `json:"fileName,omitempty" example:"scans.pdf"`
+	Inputs      []CombineInput `json:"inputs"`
+	PageSize    string         `json:"pageSize,omitempty" enums:"auto,a3,a4,a5,letter,legal" example:"a4"`
+	Orientation string         `json:"orientation,omitempty" enums:"auto,portrait,landscape" example:"auto"`
+	MarginMm    int            `json:"marginMm,omitempty" example:"10"`
+	Fit         string         `json:"fit,omitempty" enums:"shrink,contain,cover,fill" example:"contain"`
+	DPI         *int           `json:"dpi,omitempty" example:"300"`
+	JPEGQuality *int           `json:"jpegQuality,omitempty" example:"85"`
+	TimeoutMs   *int           `json:"timeoutMs,omitempty" example:"30000"`
+}
+
+type CombineInput struct {
+	FileName      string `json:"fileName,omitempty" example:"page-1.jpg"`
 	ContentBase64 string `json:"contentBase64"`
 }
 
+type CombineResponse struct {
+	To            string          `json:"to" example:"pdf"`
+	FileName      string          `json:"fileName" example:"scans.pdf"`
+	MimeType      string          `json:"mimeType" example:"application/pdf"`
+	ContentBase64 string          `json:"contentBase64"`
+	Pages         int             `json:"pages" example:"3"`
+	Sources       []CombineSource `json:"sources"`
+}
+
+type CombineSource struct {
+	FileName       string `json:"fileName,omitempty" example:"page-1.jpg"`
+	DetectedFormat string `json:"detectedFormat" example:"jpeg"`
+	Pages          int    `json:"pages" example:"1"`
+}
+
+type JobResponse struct {
+	ID         string     `json:"id" example:"4f1c2a9e8b7d4c3e9a6b5d4c3b2a1f0e"`
+	Status     string     `json:"status" enums:"queued,running,succeeded,failed,cancelled" example:"running"`
+	Progress   int        `json:"progress" example:"40"`
+	From       string     `json:"from" example:"pdf"`
+	To         string     `json:"to" example:"png"`
+	FileName   string     `json:"fileName" example:"input.png"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// ExpiresAt is when a finished job and its result are deleted.
+	ExpiresAt *time.Time   `json:"expiresAt,omitempty"`
+	ResultURL string       `json:"resultUrl,omitempty" example:"/v1/jobs/4f1c2a9e8b7d4c3e9a6b5d4c3b2a1f0e/result"`
+	Error     *ErrorDetail `json:"error,omitempty"`
+}
+
 type ErrorResponse struct {
 	Error ErrorDetail `json:"error"`
 }
 
 type ErrorDetail struct {
 	Code      string `json:"code" example:"unsupported_conversion_pair"`
-	Message   string `json:"message" example:"conversion from png to pdf is not supported"`
+	Message   string `json:"message" example:"conversion from png to svg is not supported"`
 	RequestID string `json:"requestId,omitempty" example:"req-abc123"`
 }
 
+const sourceFormatAuto = "auto"
+
+const (
+	pageOutputArray = "array"
+	pageOutputZip   = "zip"
+)
+
 var maxDecodedFileSizeBytes = 50 * 1024 * 1024
 var maxRequestBodyBytes = int64(base64.StdEncoding.EncodedLen(maxDecodedFileSizeBytes) + (2 * 1024 * 1024))
 var maxConcurrentConversions = 4
+var maxCombineInputs = 100
+
+// maxConversionTimeout bounds every conversion, and is the deadline of
+// requests that do not ask for a shorter one. It stays below the default
+// server write timeout so the timeout response can still be written.
+var maxConversionTimeout = 50 * time.Second
+
+// maxJobTimeout bounds every job, and is the deadline of jobs that do not
+// ask for a shorter one. It counts from when the job starts converting.
+var maxJobTimeout = 10 * time.Minute
+
+// jobRetention is how long finished jobs and their results are kept.
+var jobRetention = time.Hour
+
+// maxActiveJobs bounds the queued and running jobs, each holding its input
+// in memory.
+var maxActiveJobs = 16
+
+// maxJobResultBytes bounds the results of finished jobs held in memory; the
+// oldest are deleted before they expire to stay within it.
+var maxJobResultBytes int64 = 512 * 1024 * 1024
+
+// workerPool runs conversions in child processes when set.
+var workerPool *worker.Pool
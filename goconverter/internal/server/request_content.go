@@ -0,0 +1,323 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// contentBase64Field is the request field carrying a file.
+const contentBase64Field = "contentBase64"
+
+// combineInputsField is the combine request field listing its files.
+const combineInputsField = "inputs"
+
+var errControlCharacter = errors.New("control character in JSON string")
+
+// requestContent is the file of a JSON request, base64 decoded into a pooled
+// buffer while the body is read. Problems with the value are recorded rather
+// than returned, so handlers report them after checking the other fields.
+type requestContent struct {
+	buffer *bytes.Buffer
+	// present is false when the field is missing, null or blank.
+	present  bool
+	invalid  bool
+	tooLarge bool
+}
+
+func (c *requestContent) bytes() []byte {
+	if c.buffer == nil {
+		return nil
+	}
+
+	return c.buffer.Bytes()
+}
+
+func (c *requestContent) release() {
+	putBuffer(c.buffer)
+	c.buffer = nil
+}
+
+// decodeContentRequest decodes a JSON body into request, except for its
+// top-level contentBase64 string. That value is decoded into content as it
+// streams in and left empty in request, so neither the body nor the encoded
+// file is held in memory. sizeHint is the body length, or 0 when unknown.
+func decodeContentRequest(body io.Reader, sizeHint int64, request any, content *requestContent) error {
+	lifter := contentLifter{
+		in:       bufio.NewReader(body),
+		sizeHint: int64(base64.StdEncoding.DecodedLen(int(sizeHint))),
+		target: func(path []jsonFrame) (*requestContent, string) {
+			if len(path) == 1 && strings.EqualFold(path[0].key, contentBase64Field) {
+				return content, `""`
+			}
+			return nil, ""
+		},
+	}
+	if err := lifter.run(); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(lifter.out.Bytes(), request)
+}
+
+// decodeCombineRequest decodes a combine request the way decodeContentRequest
+// does, lifting each inputs[].contentBase64 string into its own content. The
+// field is left holding the index of that content, for combineInputContent.
+func decodeCombineRequest(body io.Reader, request *CombineRequest, contents *[]*requestContent) error {
+	lifter := contentLifter{
+		in: bufio.NewReader(body),
+		target: func(path []jsonFrame) (*requestContent, string) {
+			if len(path) != 3 || !strings.EqualFold(path[0].key, combineInputsField) || !path[1].array || !strings.EqualFold(path[2].key, contentBase64Field) {
+				return nil, ""
+			}
+			content := &requestContent{}
+			*contents = append(*contents, content)
+			return content, strconv.Quote(strconv.Itoa(len(*contents) - 1))
+		},
+	}
+	if err := lifter.run(); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(lifter.out.Bytes(), request)
+}
+
+// combineInputContent returns the content lifted from input, or nil when it
+// had none.
+func combineInputContent(input CombineInput, contents []*requestContent) *requestContent {
+	index, err := strconv.Atoi(input.ContentBase64)
+	if err != nil || index < 0 || index >= len(contents) {
+		return nil
+	}
+
+	return contents[index]
+}
+
+// jsonFrame is an object or array the lifter is in.
+type jsonFrame struct {
+	array bool
+	// key is the key of the current member of an object.
+	key string
+}
+
+// contentLifter copies a JSON document to out, decoding the strings target
+// picks by their path into a content each, and writing its placeholder in
+// their place. Every lifted string counts towards one decoded size limit.
+// It only tracks strings and nesting; json.Unmarshal checks the rest of the
+// syntax.
+type contentLifter struct {
+	in       *bufio.Reader
+	out      bytes.Buffer
+	target   func(path []jsonFrame) (*requestContent, string)
+	sizeHint int64
+	decoded  int64
+}
+
+func (l *contentLifter) run() error {
+	var path []jsonFrame
+	// previous is the last byte outside strings and whitespace, and tells
+	// keys from values.
+	var previous byte
+	for {
+		b, err := l.in.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch b {
+		case '"':
+			var content *requestContent
+			var placeholder string
+			inObject := len(path) > 0 && !path[len(path)-1].array
+			if inObject && previous == ':' {
+				content, placeholder = l.target(path)
+			}
+			switch {
+			case inObject && (previous == '{' || previous == ','):
+				path[len(path)-1].key, err = l.copyKey()
+			case content != nil:
+				l.out.WriteString(placeholder)
+				err = l.decodeContent(content)
+			default:
+				err = l.copyString()
+			}
+			if err != nil {
+				return err
+			}
+			previous = b
+			continue
+		case '{', '[':
+			path = append(path, jsonFrame{array: b == '['})
+		case '}', ']':
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+
+		l.out.WriteByte(b)
+		if b != ' ' && b != '\t' && b != '\n' && b != '\r' {
+			previous = b
+		}
+	}
+}
+
+// copyString copies the rest of a string whose opening quote was read.
+func (l *contentLifter) copyString() error {
+	l.out.WriteByte('"')
+	escaped := false
+	for {
+		b, err := l.in.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		l.out.WriteByte(b)
+		switch {
+		case escaped:
+			escaped = false
+		case b == '\\':
+			escaped = true
+		case b == '"':
+			return nil
+		}
+	}
+}
+
+func (l *contentLifter) copyKey() (string, error) {
+	start := l.out.Len()
+	if err := l.copyString(); err != nil {
+		return "", err
+	}
+
+	var key string
+	if err := json.Unmarshal(l.out.Bytes()[start:], &key); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// decodeContent decodes the string whose opening quote was read, stopping
+// one byte past what is left of the size limit, then skips to its closing
+// quote. A repeated field replaces the earlier value, as it does in
+// json.Unmarshal.
+func (l *contentLifter) decodeContent(content *requestContent) error {
+	if content.buffer == nil {
+		content.buffer = getBuffer()
+	}
+	l.decoded -= int64(content.buffer.Len())
+	content.buffer.Reset()
+	content.invalid, content.tooLarge = false, false
+	growBuffer(content.buffer, l.sizeHint)
+
+	remaining := max(int64(maxDecodedFileSizeBytes)-l.decoded, 0)
+	value := &base64StringReader{in: l.in}
+	decoded, err := io.CopyN(content.buffer, base64.NewDecoder(base64.StdEncoding, value), remaining+1)
+	l.decoded += decoded
+	switch {
+	case value.err != nil:
+		return value.err
+	case errors.Is(err, io.EOF):
+		content.invalid = value.invalid
+	case err != nil:
+		// The decoder fails on corrupt and on truncated text.
+		content.invalid = true
+	case decoded > remaining:
+		content.tooLarge = true
+	}
+	if _, err := io.Copy(io.Discard, value); err != nil {
+		return err
+	}
+	content.present = value.started
+
+	return nil
+}
+
+// base64StringReader reads the rest of a JSON string holding base64 text, up
+// to its closing quote. Surrounding spaces are dropped, as the text is
+// trimmed; escapes other than \/, \n and \r and spaces inside the text cannot
+// be valid base64 and mark it invalid.
+type base64StringReader struct {
+	in      *bufio.Reader
+	done    bool
+	started bool
+	spaces  bool
+	invalid bool
+	// err is the error reading the body, kept apart from decoding errors.
+	err error
+}
+
+func (r *base64StringReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.read(p)
+	if !errors.Is(err, io.EOF) {
+		r.err = err
+	}
+	return n, err
+}
+
+func (r *base64StringReader) read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) && !r.done {
+		b, err := r.in.ReadByte()
+		if err != nil {
+			return n, unexpectedEOF(err)
+		}
+
+		switch {
+		case b == '"':
+			r.done = true
+			continue
+		case b < 0x20:
+			return n, errControlCharacter
+		case b == ' ':
+			r.spaces = r.started
+			continue
+		case b == '\\':
+			if b, err = r.in.ReadByte(); err != nil {
+				return n, unexpectedEOF(err)
+			}
+			switch b {
+			case '/':
+			case 'n', 'r':
+				// Line breaks are skipped by the decoder.
+				p[n] = '\n'
+				n++
+				continue
+			default:
+				r.invalid = true
+				continue
+			}
+		}
+
+		if r.spaces {
+			r.invalid = true
+		}
+		r.started = true
+		p[n] = b
+		n++
+	}
+	if n == 0 && r.done {
+		return 0, io.EOF
+	}
+
+	return n, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeContentRequestDecodesContentWhileReading(t *testing.T) {
+	tests := []struct {
+		body     string
+		content  string
+		present  bool
+		invalid  bool
+		fileName string
+	}{
+		{body: `{"fileName":"a.png","contentBase64":"QUJD"}`, content: "ABC", present: true, fileName: "a.png"},
+		{body: `{"contentBase64":"QUJD","fileName":"a\"b.png"}`, content: "ABC", present: true, fileName: `a"b.png`},
+		{body: `{"ContentBase64":"QUJD"}`, content: "ABC", present: true},
+		{body: `{"contentBase64":"\/w=="}`, content: "\xff", present: true},
+		{body: `{"contentBase64":"QU\nJD\r\n"}`, content: "ABC", present: true},
+		{body: `{"contentBase64":"  QUJD  "}`, content: "ABC", present: true},
+		{body: `{"contentBase64":"QU JD"}`, present: true, invalid: true},
+		{body: `{"contentBase64":"%%%"}`, present: true, invalid: true},
+		{body: `{"contentBase64":"QUJ"}`, present: true, invalid: true},
+		{body: `{"contentBase64":"QU\tJD"}`, present: true, invalid: true},
+		{body: `{"contentBase64":"   "}`},
+		{body: `{"contentBase64":null}`},
+		{body: `{"options":{"contentBase64":"QUJD"}}`},
+		{body: `{"contentBase64":"QUJD","contentBase64":"REVG"}`, content: "DEF", present: true},
+	}
+
+	for _, tt := range tests {
+		var request ConvertRequest
+		var content requestContent
+		if err := decodeContentRequest(strings.NewReader(tt.body), 0, &request, &content); err != nil {
+			t.Fatalf("%s: expected the body to decode, got error: %v", tt.body, err)
+		}
+		if content.present != tt.present || content.invalid != tt.invalid {
+			t.Fatalf("%s: expected present %t and invalid %t, got %+v", tt.body, tt.present, tt.invalid, content)
+		}
+		if !tt.invalid && string(content.bytes()) != tt.content {
+			t.Fatalf("%s: expected content %q, got %q", tt.body, tt.content, content.bytes())
+		}
+		if request.ContentBase64 != "" || request.FileName != tt.fileName {
+			t.Fatalf("%s: expected the other fields only, got %+v", tt.body, request)
+		}
+		content.release()
+	}
+}
+
+func TestDecodeContentRequestStopsAtTheSizeLimit(t *testing.T) {
+	oldMax := maxDecodedFileSizeBytes
+	maxDecodedFileSizeBytes = 2
+	t.Cleanup(func() {
+		maxDecodedFileSizeBytes = oldMax
+	})
+
+	var request ConvertRequest
+	var content requestContent
+	defer content.release()
+	if err := decodeContentRequest(strings.NewReader(`{"contentBase64":"QUJD","to":"png"}`), 0, &request, &content); err != nil {
+		t.Fatalf("expected the body to decode, got error: %v", err)
+	}
+	if !content.tooLarge || request.To != "png" {
+		t.Fatalf("expected the content to be too large and the rest decoded, got %+v and %+v", content, request)
+	}
+}
+
+func TestDecodeContentRequestRejectsInvalidJSON(t *testing.T) {
+	for _, body := range []string{`{"to":`, `{"contentBase64":"QUJD`, "{\"contentBase64\":\"QU\x01JD\"}", `{"contentBase64":1}`, `{"content\qBase64":"QUJD"}`} {
+		var request ConvertRequest
+		var content requestContent
+		if err := decodeContentRequest(strings.NewReader(body), 0, &request, &content); err == nil {
+			t.Fatalf("%s: expected an error", body)
+		}
+		content.release()
+	}
+}
+
+func TestDecodeCombineRequestDecodesEachInput(t *testing.T) {
+	body := `{"to":"pdf","inputs":[{"fileName":"a.png","contentBase64":"QUJD"},{"fileName":"b.png"},{"CONTENTBASE64":"REVG"}],"fileName":"out"}`
+
+	var request CombineRequest
+	var contents []*requestContent
+	if err := decodeCombineRequest(strings.NewReader(body), &request, &contents); err != nil {
+		t.Fatalf("expected the body to decode, got error: %v", err)
+	}
+	if len(request.Inputs) != 3 || request.Inputs[0].FileName != "a.png" || request.FileName != "out" {
+		t.Fatalf("expected the other fields, got %+v", request)
+	}
+
+	expected := []string{"ABC", "", "DEF"}
+	for index, input := range request.Inputs {
+		content := combineInputContent(input, contents)
+		if expected[index] == "" {
+			if content != nil {
+				t.Fatalf("input %d: expected no content, got %q", index, content.bytes())
+			}
+			continue
+		}
+		if content == nil || !content.present || string(content.bytes()) != expected[index] {
+			t.Fatalf("input %d: expected content %q, got %+v", index, expected[index], content)
+		}
+	}
+	for _, content := range contents {
+		content.release()
+	}
+}
+
+func TestDecodeCombineRequestLimitsTheInputsTogether(t *testing.T) {
+	oldMax := maxDecodedFileSizeBytes
+	maxDecodedFileSizeBytes = 4
+	t.Cleanup(func() {
+		maxDecodedFileSizeBytes = oldMax
+	})
+
+	var request CombineRequest
+	var contents []*requestContent
+	if err := decodeCombineRequest(strings.NewReader(`{"inputs":[{"contentBase64":"QUJD"},{"contentBase64":"REVG"}]}`), &request, &contents); err != nil {
+		t.Fatalf("expected the body to decode, got error: %v", err)
+	}
+	first, second := combineInputContent(request.Inputs[0], contents), combineInputContent(request.Inputs[1], contents)
+	if first.tooLarge || !second.tooLarge {
+		t.Fatalf("expected only the second input over the limit, got %+v and %+v", first, second)
+	}
+	for _, content := range contents {
+		content.release()
+	}
+}
@@ -20,7 +20,14 @@ func NewRouter() *gin.Engine {
 
 	v1 := router.Group("/v1")
 	v1.GET("/conversions", listConversionsHandler)
+	v1.GET("/conversions/:from/:to", conversionSchemaHandler)
 	v1.POST("/convert", requestBodyLimitMiddleware(), convertHandler)
+	v1.POST("/convert/:from/:to", requestBodyLimitMiddleware(), convertUploadHandler)
+	v1.POST("/combine", requestBodyLimitMiddleware(), combineHandler)
+	v1.POST("/jobs", requestBodyLimitMiddleware(), createJobHandler)
+	v1.GET("/jobs/:id", getJobHandler)
+	v1.GET("/jobs/:id/result", jobResultHandler)
+	v1.DELETE("/jobs/:id", cancelJobHandler)
 
 	return router
 }
@@ -2,21 +2,27 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"image"
 	"image/color"
+	"image/jpeg"
 	"image/png"
+	"math"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"goconverter/internal/converter"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/image/bmp"
 )
 
 func TestHealthEndpoint(t *testing.T) {
@@ -97,13 +103,635 @@ func TestConversionsEndpointIncludesAliasesForSourcesAndTargets(t *testing.T) {
 	assertTargetAliasMirrorsCanonical(t, body.Formats, "heif", "heic")
 }
 
+func TestConversionsEndpointListsBackends(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/conversions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var body struct {
+		Backends map[string]map[string][]string `json:"backends"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected JSON response, got error: %v", err)
+	}
+	if !reflect.DeepEqual(body.Backends, converter.ConversionBackends()) {
+		t.Fatalf("unexpected backends payload: %v", body.Backends)
+	}
+	if len(body.Backends["png"]["jpeg"]) == 0 {
+		t.Fatal("expected a backend for png to jpeg")
+	}
+}
+
+func TestConversionsEndpointListsTheDeprecatedMagickSource(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/conversions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body ConversionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected JSON response, got error: %v", err)
+	}
+	if len(body.Formats["magick"]) == 0 || !reflect.DeepEqual(body.DeprecatedFormats, []string{"magick"}) {
+		t.Fatalf("expected magick as a deprecated source, got %v and %v", body.Formats["magick"], body.DeprecatedFormats)
+	}
+}
+
+func TestConversionSchemaEndpoint(t *testing.T) {
+	router := newTestRouter()
+
+	if _, ok := converter.SchemaFor("png", "jpeg"); !ok {
+		t.Skip("png to jpeg conversion is not supported by this libvips build")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/conversions/png/jpg", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var body struct {
+		To      string `json:"to"`
+		Lossy   bool   `json:"lossy"`
+		Options []struct {
+			Name    string `json:"name"`
+			Type    string `json:"type"`
+			Minimum *int   `json:"minimum"`
+			Maximum *int   `json:"maximum"`
+			Default any    `json:"default"`
+		} `json:"options"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected JSON response, got error: %v", err)
+	}
+	if body.To != "jpeg" || !body.Lossy {
+		t.Fatalf("expected lossy jpeg target, got to %q and lossy %t", body.To, body.Lossy)
+	}
+
+	for _, option := range body.Options {
+		if option.Name != "options.quality" {
+			continue
+		}
+		if option.Type != "integer" || option.Minimum == nil || *option.Minimum != 1 || option.Maximum == nil || *option.Maximum != 100 {
+			t.Fatalf("unexpected quality schema: %+v", option)
+		}
+		return
+	}
+	t.Fatalf("expected options.quality in schema, got %+v", body.Options)
+}
+
+func TestConversionSchemaEndpointRejectsUnsupportedPair(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/conversions/png/svg", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "unsupported_conversion_pair") {
+		t.Fatalf("expected unsupported_conversion_pair error, got %s", w.Body.String())
+	}
+}
+
 func TestConvertEndpoint(t *testing.T) {
 	router := newTestRouter()
 
 	payload := map[string]string{
-		"from":          "png",
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		From          string `json:"from"`
+		To            string `json:"to"`
+		FileName      string `json:"fileName"`
+		MimeType      string `json:"mimeType"`
+		ContentBase64 string `json:"contentBase64"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+
+	if response.From != "png" || response.To != "jpeg" {
+		t.Fatalf("expected from/to png->jpeg, got %s->%s", response.From, response.To)
+	}
+	if response.FileName != "input.jpeg" {
+		t.Fatalf("expected output fileName input.jpeg, got %q", response.FileName)
+	}
+	if response.MimeType != "image/jpeg" {
+		t.Fatalf("expected mime image/jpeg, got %q", response.MimeType)
+	}
+	if response.ContentBase64 == "" {
+		t.Fatalf("expected non-empty contentBase64")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(response.ContentBase64)
+	if err != nil {
+		t.Fatalf("failed to decode output base64: %v", err)
+	}
+	if len(decoded) == 0 {
+		t.Fatalf("expected non-empty converted bytes")
+	}
+
+	if w.Header().Get("X-Request-Id") == "" {
+		t.Fatalf("expected X-Request-Id response header")
+	}
+}
+
+func TestConvertEndpointSupportsAVIF(t *testing.T) {
+	router := newTestRouter()
+
+	if _, ok := converter.FindConverter("png", "avif"); !ok {
+		t.Skip("png -> avif conversion is not registered in current runtime")
+	}
+
+	payload := map[string]string{
+		"from":          "png",
+		"to":            "avif",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		To       string `json:"to"`
+		FileName string `json:"fileName"`
+		MimeType string `json:"mimeType"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+
+	if response.To != "avif" {
+		t.Fatalf("expected response to=avif, got %q", response.To)
+	}
+	if response.FileName != "input.avif" {
+		t.Fatalf("expected output fileName input.avif, got %q", response.FileName)
+	}
+	if response.MimeType != "image/avif" {
+		t.Fatalf("expected mime image/avif, got %q", response.MimeType)
+	}
+}
+
+func TestConvertEndpointNormalizesJPGAlias(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]string{
+		"from":          "png",
+		"to":            "jpg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		To       string `json:"to"`
+		FileName string `json:"fileName"`
+		MimeType string `json:"mimeType"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+
+	if response.To != "jpeg" {
+		t.Fatalf("expected response to=jpeg after alias normalization, got %q", response.To)
+	}
+	if response.FileName != "input.jpeg" {
+		t.Fatalf("expected output fileName input.jpeg, got %q", response.FileName)
+	}
+	if response.MimeType != "image/jpeg" {
+		t.Fatalf("expected mime image/jpeg, got %q", response.MimeType)
+	}
+}
+
+func TestConvertEndpointAppliesEncoderOptions(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]any{
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		"options": map[string]any{
+			"quality":           90,
+			"progressive":       true,
+			"chromaSubsampling": "4:4:4",
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestConvertEndpointWritesPDFWithPageLayout(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]any{
+		"from":          "png",
+		"to":            "pdf",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		"options": map[string]any{
+			"dpi":      150,
+			"pageSize": "A4",
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response ConvertResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.MimeType != "application/pdf" || response.FileName != "input.pdf" {
+		t.Fatalf("unexpected pdf response metadata: %+v", response)
+	}
+	output, err := base64.StdEncoding.DecodeString(response.ContentBase64)
+	if err != nil || !bytes.HasPrefix(output, []byte("%PDF-")) {
+		t.Fatalf("expected pdf content, got %q", output)
+	}
+}
+
+func TestCombineEndpointWritesOnePDF(t *testing.T) {
+	router := newTestRouter()
+
+	input := base64.StdEncoding.EncodeToString(mustEncodePNG(t))
+	payload := map[string]any{
+		"to":       "pdf",
+		"fileName": "scans",
+		"inputs": []map[string]any{
+			{"fileName": "page-1.png", "contentBase64": input},
+			{"fileName": "page-2.png", "contentBase64": input},
+		},
+		"pageSize": "A4",
+		"marginMm": 10,
+		"fit":      "contain",
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/combine", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response CombineResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.FileName != "scans.pdf" || response.MimeType != "application/pdf" || response.Pages != 2 {
+		t.Fatalf("unexpected combine response metadata: %+v", response)
+	}
+	if len(response.Sources) != 2 || response.Sources[1].FileName != "page-2.png" || response.Sources[1].DetectedFormat != "png" {
+		t.Fatalf("unexpected combine sources: %+v", response.Sources)
+	}
+	output, err := base64.StdEncoding.DecodeString(response.ContentBase64)
+	if err != nil || !bytes.HasPrefix(output, []byte("%PDF-")) {
+		t.Fatalf("expected pdf content, got %q", output)
+	}
+}
+
+func TestCombineEndpointRejectsInvalidRequests(t *testing.T) {
+	router := newTestRouter()
+
+	input := base64.StdEncoding.EncodeToString(mustEncodePNG(t))
+	tests := []struct {
+		name    string
+		payload string
+		status  int
+		code    string
+	}{
+		{name: "no inputs", payload: `{"to":"pdf","inputs":[]}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "target", payload: `{"to":"png","inputs":[{"contentBase64":"` + input + `"}]}`, status: http.StatusUnsupportedMediaType, code: "unsupported_target_format"},
+		{name: "options", payload: `{"to":"tiff","jpegQuality":80,"inputs":[{"contentBase64":"` + input + `"}]}`, status: http.StatusBadRequest, code: "invalid_options"},
+		{name: "base64", payload: `{"to":"pdf","inputs":[{"contentBase64":"%%%"}]}`, status: http.StatusBadRequest, code: "invalid_base64"},
+		{name: "missing content", payload: `{"to":"pdf","inputs":[{"fileName":"a.png"}]}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "json", payload: `{"to":"pdf","inputs":[{"contentBase64":"` + input + `"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "undetected", payload: `{"to":"pdf","inputs":[{"contentBase64":"` + base64.StdEncoding.EncodeToString([]byte("not an image")) + `"}]}`, status: http.StatusUnsupportedMediaType, code: "unsupported_source_format"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/v1/combine", strings.NewReader(tt.payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		var response ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: failed to decode response JSON: %v", tt.name, err)
+		}
+		if w.Code != tt.status || response.Error.Code != tt.code {
+			t.Fatalf("%s: expected %d %s, got %d %s", tt.name, tt.status, tt.code, w.Code, response.Error.Code)
+		}
+	}
+}
+
+func TestConvertEndpointEchoesTransformGeometry(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]any{
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		"transform": map[string]any{
+			"width":  4,
+			"height": 2,
+			"fit":    "cover",
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Geometry struct {
+			SourceWidth  int    `json:"sourceWidth"`
+			SourceHeight int    `json:"sourceHeight"`
+			Width        int    `json:"width"`
+			Height       int    `json:"height"`
+			Fit          string `json:"fit"`
+			Gravity      string `json:"gravity"`
+		} `json:"geometry"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+
+	if response.Geometry.SourceWidth != 2 || response.Geometry.SourceHeight != 2 {
+		t.Fatalf("expected source geometry 2x2, got %+v", response.Geometry)
+	}
+	if response.Geometry.Width != 4 || response.Geometry.Height != 2 {
+		t.Fatalf("expected output geometry 4x2, got %+v", response.Geometry)
+	}
+	if response.Geometry.Fit != "cover" || response.Geometry.Gravity != "centre" {
+		t.Fatalf("expected fit cover with centre gravity, got %+v", response.Geometry)
+	}
+}
+
+func TestConvertEndpointRejectsInvalidOptions(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]any{
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		"options": map[string]any{
+			"lossless": true,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+	if response.Error.Code != "invalid_options" {
+		t.Fatalf("expected error code invalid_options, got %q", response.Error.Code)
+	}
+	if !strings.Contains(response.Error.Message, "lossless") {
+		t.Fatalf("expected error message to name the rejected option, got %q", response.Error.Message)
+	}
+}
+
+func TestConvertEndpointRejectsRasterizeForRasterSource(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]any{
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		"rasterize": map[string]any{
+			"dpi": 300,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	var response ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || response.Error.Code != "invalid_options" {
+		t.Fatalf("expected invalid_options, got %d %+v", w.Code, response.Error)
+	}
+	if !strings.Contains(response.Error.Message, "rasterize is not supported for png input") {
+		t.Fatalf("expected error message to name the rejected option, got %q", response.Error.Message)
+	}
+}
+
+func TestConvertEndpointRejectsPagesForSinglePageSource(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]any{
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		"pages":         "1-2",
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+	if response.Error.Code != "invalid_options" {
+		t.Fatalf("expected error code invalid_options, got %q", response.Error.Code)
+	}
+	if !strings.Contains(response.Error.Message, "pages") {
+		t.Fatalf("expected error message to name the pages option, got %q", response.Error.Message)
+	}
+}
+
+func TestConvertEndpointRejectsFrameForStillSource(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]any{
+		"from":          "png",
+		"to":            "webp",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		"frame":         2,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var response struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+	if response.Error.Code != "invalid_options" {
+		t.Fatalf("expected error code invalid_options, got %q", response.Error.Code)
+	}
+}
+
+func TestConvertEndpointDetectsSourceFormat(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]string{
+		"from":          "auto",
 		"to":            "jpeg",
-		"fileName":      "input.png",
+		"fileName":      "upload",
 		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
 	}
 	body, err := json.Marshal(payload)
@@ -118,57 +746,114 @@ func TestConvertEndpoint(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
 
 	var response struct {
-		From          string `json:"from"`
-		To            string `json:"to"`
-		FileName      string `json:"fileName"`
-		MimeType      string `json:"mimeType"`
-		ContentBase64 string `json:"contentBase64"`
+		From           string `json:"from"`
+		DetectedFormat string `json:"detectedFormat"`
 	}
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to decode response JSON: %v", err)
 	}
+	if response.From != "png" || response.DetectedFormat != "png" {
+		t.Fatalf("expected detected source png, got from %q and detectedFormat %q", response.From, response.DetectedFormat)
+	}
+}
 
-	if response.From != "png" || response.To != "jpeg" {
-		t.Fatalf("expected from/to png->jpeg, got %s->%s", response.From, response.To)
+func TestConvertEndpointReportsBackends(t *testing.T) {
+	router := newTestRouter()
+
+	if _, ok := converter.FindConverter("png", "jpeg"); !ok {
+		t.Skip("png to jpeg conversion is not supported by this build")
 	}
-	if response.FileName != "input.jpeg" {
-		t.Fatalf("expected output fileName input.jpeg, got %q", response.FileName)
+
+	body, err := json.Marshal(map[string]string{
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
 	}
-	if response.MimeType != "image/jpeg" {
-		t.Fatalf("expected mime image/jpeg, got %q", response.MimeType)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
-	if response.ContentBase64 == "" {
-		t.Fatalf("expected non-empty contentBase64")
+
+	var response struct {
+		Backends []string `json:"backends"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
 	}
+	if len(response.Backends) != 1 || !slices.Contains(converter.ConversionBackends()["png"]["jpeg"], response.Backends[0]) {
+		t.Fatalf("expected one of the registered png to jpeg backends, got %v", response.Backends)
+	}
+}
 
-	decoded, err := base64.StdEncoding.DecodeString(response.ContentBase64)
+func TestConvertEndpointExplainsRouteWithoutConverting(t *testing.T) {
+	router := newTestRouter()
+
+	plan, ok := converter.PlanConversion("png", "jpeg")
+	if !ok {
+		t.Skip("png to jpeg conversion is not supported by this libvips build")
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"from":    "png",
+		"to":      "jpg",
+		"explain": true,
+	})
 	if err != nil {
-		t.Fatalf("failed to decode output base64: %v", err)
+		t.Fatalf("failed to marshal payload: %v", err)
 	}
-	if len(decoded) == 0 {
-		t.Fatalf("expected non-empty converted bytes")
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
 
-	if w.Header().Get("X-Request-Id") == "" {
-		t.Fatalf("expected X-Request-Id response header")
+	var response struct {
+		From          string   `json:"from"`
+		To            string   `json:"to"`
+		Route         []string `json:"route"`
+		Cost          int      `json:"cost"`
+		ContentBase64 string   `json:"contentBase64"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+	if response.From != "png" || response.To != "jpeg" {
+		t.Fatalf("expected png to jpeg, got %q to %q", response.From, response.To)
+	}
+	if !reflect.DeepEqual(response.Route, plan.Route()) || response.Cost != plan.Cost() {
+		t.Fatalf("expected route %v with cost %d, got %v with cost %d", plan.Route(), plan.Cost(), response.Route, response.Cost)
+	}
+	if response.ContentBase64 != "" {
+		t.Fatal("expected explain mode not to return converted content")
 	}
 }
 
-func TestConvertEndpointSupportsAVIF(t *testing.T) {
+func TestConvertEndpointRejectsMislabeledSource(t *testing.T) {
 	router := newTestRouter()
 
-	if _, ok := converter.FindConverter("png", "avif"); !ok {
-		t.Skip("png -> avif conversion is not registered in current runtime")
-	}
-
 	payload := map[string]string{
-		"from":          "png",
-		"to":            "avif",
-		"fileName":      "input.png",
+		"from":          "jpeg",
+		"to":            "webp",
+		"fileName":      "input.jpg",
 		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
 	}
 	body, err := json.Marshal(payload)
@@ -182,36 +867,64 @@ func TestConvertEndpointSupportsAVIF(t *testing.T) {
 
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
 	}
 
 	var response struct {
-		To       string `json:"to"`
-		FileName string `json:"fileName"`
-		MimeType string `json:"mimeType"`
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
 	}
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatalf("failed to decode response JSON: %v", err)
 	}
+	if response.Error.Code != "source_format_mismatch" {
+		t.Fatalf("expected error code source_format_mismatch, got %q", response.Error.Code)
+	}
+	if !strings.Contains(response.Error.Message, "png") {
+		t.Fatalf("expected error message to name the detected format, got %q", response.Error.Message)
+	}
+}
 
-	if response.To != "avif" {
-		t.Fatalf("expected response to=avif, got %q", response.To)
+func TestConvertEndpointRejectsInputAboveThePixelLimit(t *testing.T) {
+	router := newTestRouter()
+	converter.SetInputLimits(converter.InputLimits{MaxPixels: 3})
+	t.Cleanup(func() { converter.SetInputLimits(converter.DefaultInputLimits) })
+
+	payload := map[string]any{
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
 	}
-	if response.FileName != "input.avif" {
-		t.Fatalf("expected output fileName input.avif, got %q", response.FileName)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
 	}
-	if response.MimeType != "image/avif" {
-		t.Fatalf("expected mime image/avif, got %q", response.MimeType)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	var response ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+	if w.Code != http.StatusUnprocessableEntity || response.Error.Code != "input_too_complex" {
+		t.Fatalf("expected input_too_complex, got %d %+v", w.Code, response.Error)
 	}
 }
 
-func TestConvertEndpointNormalizesJPGAlias(t *testing.T) {
+func TestConvertEndpointStopsAtTheRequestDeadline(t *testing.T) {
 	router := newTestRouter()
 
-	payload := map[string]string{
+	payload := map[string]any{
 		"from":          "png",
-		"to":            "jpg",
+		"to":            "jpeg",
 		"fileName":      "input.png",
 		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
 	}
@@ -220,33 +933,186 @@ func TestConvertEndpointNormalizesJPGAlias(t *testing.T) {
 		t.Fatalf("failed to marshal payload: %v", err)
 	}
 
+	tests := []struct {
+		name   string
+		ctx    func() (context.Context, context.CancelFunc)
+		status int
+		code   string
+	}{
+		{
+			name: "deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+			},
+			status: http.StatusGatewayTimeout,
+			code:   "conversion_timeout",
+		},
+		{
+			name: "client disconnect",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			status: statusClientClosedRequest,
+			code:   "request_cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		ctx, cancel := tt.ctx()
+		defer cancel()
+
+		req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/v1/convert", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		var response ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: failed to decode response JSON: %v", tt.name, err)
+		}
+		if w.Code != tt.status || response.Error.Code != tt.code {
+			t.Fatalf("%s: expected %d %s, got %d %+v", tt.name, tt.status, tt.code, w.Code, response.Error)
+		}
+	}
+}
+
+func TestConvertEndpointRejectsInvalidTimeouts(t *testing.T) {
+	router := newTestRouter()
+
+	tests := []struct {
+		name      string
+		header    string
+		timeoutMs any
+	}{
+		{name: "header", header: "30s"},
+		{name: "zero header", header: "0"},
+		{name: "field", timeoutMs: -1},
+	}
+
+	for _, tt := range tests {
+		payload := map[string]any{
+			"from":          "png",
+			"to":            "jpeg",
+			"fileName":      "input.png",
+			"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		}
+		if tt.timeoutMs != nil {
+			payload["timeoutMs"] = tt.timeoutMs
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("failed to marshal payload: %v", err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if tt.header != "" {
+			req.Header.Set("X-Request-Timeout", tt.header)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		var response ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: failed to decode response JSON: %v", tt.name, err)
+		}
+		if w.Code != http.StatusBadRequest || response.Error.Code != "invalid_request" {
+			t.Fatalf("%s: expected invalid_request, got %d %+v", tt.name, w.Code, response.Error)
+		}
+	}
+}
+
+func TestConvertEndpointCapsLargeTimeoutsAtTheLimit(t *testing.T) {
+	router := newTestRouter()
+
+	body, err := json.Marshal(map[string]any{
+		"from":          "png",
+		"to":            "jpeg",
+		"fileName":      "input.png",
+		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
+		"timeoutMs":     math.MaxInt64,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
 	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Timeout", "9223372036854775")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Fatalf("expected status %d, got %d, body: %s", http.StatusOK, w.Code, w.Body.String())
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
+}
 
-	var response struct {
-		To       string `json:"to"`
-		FileName string `json:"fileName"`
-		MimeType string `json:"mimeType"`
+func TestConvertEndpointAcceptsTheDeprecatedMagickSource(t *testing.T) {
+	router := newTestRouter()
+
+	var input bytes.Buffer
+	if err := bmp.Encode(&input, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("failed to encode bmp fixture: %v", err)
 	}
-	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
-		t.Fatalf("failed to decode response JSON: %v", err)
+	body, err := json.Marshal(map[string]string{
+		"from":          "magick",
+		"to":            "png",
+		"fileName":      "input.bmp",
+		"contentBase64": base64.StdEncoding.EncodeToString(input.Bytes()),
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
 	}
 
-	if response.To != "jpeg" {
-		t.Fatalf("expected response to=jpeg after alias normalization, got %q", response.To)
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
-	if response.FileName != "input.jpeg" {
-		t.Fatalf("expected output fileName input.jpeg, got %q", response.FileName)
+}
+
+func TestConvertEndpointRejectsUndetectableSource(t *testing.T) {
+	router := newTestRouter()
+
+	payload := map[string]string{
+		"to":            "png",
+		"fileName":      "notes.txt",
+		"contentBase64": base64.StdEncoding.EncodeToString([]byte("plain text, not an image")),
 	}
-	if response.MimeType != "image/jpeg" {
-		t.Fatalf("expected mime image/jpeg, got %q", response.MimeType)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected status %d, got %d", http.StatusUnsupportedMediaType, w.Code)
+	}
+
+	var response struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response JSON: %v", err)
+	}
+	if response.Error.Code != "unsupported_source_format" {
+		t.Fatalf("expected error code unsupported_source_format, got %q", response.Error.Code)
 	}
 }
 
@@ -311,7 +1177,7 @@ func TestConvertEndpointRejectsUnsupportedPair(t *testing.T) {
 
 	payload := map[string]string{
 		"from":          "png",
-		"to":            "pdf",
+		"to":            "svg",
 		"fileName":      "input.png",
 		"contentBase64": base64.StdEncoding.EncodeToString(mustEncodePNG(t)),
 	}
@@ -487,6 +1353,134 @@ func TestConvertEndpointReturnsBusyWhenAllSlotsAreUsed(t *testing.T) {
 	}
 }
 
+func TestConvertUploadEndpointConvertsRawBody(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert/png/jpg", bytes.NewReader(mustEncodePNG(t)))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-File-Name", "photo.png")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "image/jpeg" {
+		t.Fatalf("expected image/jpeg, got %q", contentType)
+	}
+	if disposition := w.Header().Get("Content-Disposition"); disposition != `attachment; filename=photo.jpeg` {
+		t.Fatalf("expected the converted file name, got %q", disposition)
+	}
+	if route := w.Header().Get("X-Conversion-Route"); route != "png,jpeg" {
+		t.Fatalf("expected the route header, got %q", route)
+	}
+	if _, err := jpeg.Decode(w.Body); err != nil {
+		t.Fatalf("expected a jpeg body, got error: %v", err)
+	}
+}
+
+func TestConvertUploadEndpointConvertsMultipartFile(t *testing.T) {
+	router := newTestRouter()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("options", `{"metadata":"strip"}`); err != nil {
+		t.Fatalf("failed to write options field: %v", err)
+	}
+	part, err := form.CreateFormFile("file", "scan.png")
+	if err != nil {
+		t.Fatalf("failed to create file part: %v", err)
+	}
+	part.Write(mustEncodePNG(t))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/convert/auto/jpeg", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if detected := w.Header().Get("X-Detected-Format"); detected != "png" {
+		t.Fatalf("expected the detected format header, got %q", detected)
+	}
+	if disposition := w.Header().Get("Content-Disposition"); disposition != `attachment; filename=scan.jpeg` {
+		t.Fatalf("expected the converted file name, got %q", disposition)
+	}
+	if _, err := jpeg.Decode(w.Body); err != nil {
+		t.Fatalf("expected a jpeg body, got error: %v", err)
+	}
+}
+
+func TestConvertUploadEndpointRejectsInvalidUploads(t *testing.T) {
+	router := newTestRouter()
+
+	oldMax := maxDecodedFileSizeBytes
+	t.Cleanup(func() {
+		maxDecodedFileSizeBytes = oldMax
+	})
+
+	var emptyForm bytes.Buffer
+	form := multipart.NewWriter(&emptyForm)
+	form.WriteField("options", "{}")
+	form.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		options     string
+		body        []byte
+		maxFileSize int
+		status      int
+		code        string
+		message     string
+	}{
+		{name: "empty body", path: "/v1/convert/png/jpeg", body: nil, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "missing file", path: "/v1/convert/png/jpeg", contentType: form.FormDataContentType(), body: emptyForm.Bytes(), status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "options", path: "/v1/convert/png/jpeg", options: "{", body: mustEncodePNG(t), status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "explain option", path: "/v1/convert/png/jpeg", options: `{"explain":true}`, body: mustEncodePNG(t), status: http.StatusBadRequest, code: "invalid_request", message: `options field "explain" is not a conversion option`},
+		{name: "target option", path: "/v1/convert/png/jpeg", options: `{"to":"png"}`, body: mustEncodePNG(t), status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "trailing options", path: "/v1/convert/png/jpeg", options: `{} {}`, body: mustEncodePNG(t), status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "file size", path: "/v1/convert/png/jpeg", body: mustEncodePNG(t), maxFileSize: 4, status: http.StatusRequestEntityTooLarge, code: "payload_too_large", message: "uploaded file exceeds 4 bytes limit"},
+		{name: "pair", path: "/v1/convert/png/svg", body: mustEncodePNG(t), status: http.StatusUnsupportedMediaType, code: "unsupported_conversion_pair"},
+		{name: "undetected", path: "/v1/convert/auto/png", body: []byte("plain text"), status: http.StatusUnsupportedMediaType, code: "unsupported_source_format"},
+	}
+
+	for _, tt := range tests {
+		maxDecodedFileSizeBytes = oldMax
+		if tt.maxFileSize > 0 {
+			maxDecodedFileSizeBytes = tt.maxFileSize
+		}
+
+		req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/octet-stream")
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		if tt.options != "" {
+			req.Header.Set("X-Convert-Options", tt.options)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		var response ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: failed to decode response JSON: %v", tt.name, err)
+		}
+		if w.Code != tt.status || response.Error.Code != tt.code {
+			t.Fatalf("%s: expected %d %s, got %d %+v", tt.name, tt.status, tt.code, w.Code, response.Error)
+		}
+		if tt.message != "" && response.Error.Message != tt.message {
+			t.Fatalf("%s: expected message %q, got %q", tt.name, tt.message, response.Error.Message)
+		}
+	}
+}
+
 func TestRequestIDHeaderIsPropagatedToResponse(t *testing.T) {
 	router := newTestRouter()
 
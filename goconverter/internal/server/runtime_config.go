@@ -1,5 +1,11 @@
 package server
 
+import (
+	"time"
+
+	"goconverter/internal/worker"
+)
+
 func ConfigureRuntimeLimits(decodedFileSizeBytes int, requestBodyBytes int64, concurrentConversions int) {
 	if decodedFileSizeBytes > 0 {
 		maxDecodedFileSizeBytes = decodedFileSizeBytes
@@ -13,3 +19,34 @@ func ConfigureRuntimeLimits(decodedFileSizeBytes int, requestBodyBytes int64, co
 		maxConcurrentConversions = concurrentConversions
 	}
 }
+
+func ConfigureConversionTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		maxConversionTimeout = timeout
+	}
+}
+
+// ConfigureJobs sets the deadline and retention of jobs, how many may be
+// queued or running, and how many bytes of results are kept.
+func ConfigureJobs(timeout time.Duration, retention time.Duration, activeJobs int, resultBytes int64) {
+	if timeout > 0 {
+		maxJobTimeout = timeout
+	}
+
+	if retention > 0 {
+		jobRetention = retention
+	}
+
+	if activeJobs > 0 {
+		maxActiveJobs = activeJobs
+	}
+
+	if resultBytes > 0 {
+		maxJobResultBytes = resultBytes
+	}
+}
+
+// ConfigureWorkers makes conversions run in the pool's worker processes.
+func ConfigureWorkers(pool *worker.Pool) {
+	workerPool = pool
+}
@@ -0,0 +1,146 @@
+// Package worker runs conversions in child processes, so a native library
+// crashing on a malformed file takes down one worker instead of the server.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"goconverter/internal/converter"
+)
+
+// ErrCrashed is returned for jobs whose worker died before answering, for
+// example from a segfault in libvips or from hitting its limits.
+var ErrCrashed = errors.New("worker crashed")
+
+// Config sizes a pool and limits its workers.
+type Config struct {
+	Workers int
+	// MaxJobs recycles a worker after that many jobs; 0 keeps workers until
+	// they crash.
+	MaxJobs int
+	Limits  Limits
+}
+
+// Limits are applied by each worker to itself before it takes jobs.
+type Limits struct {
+	// MemoryBytes caps the address space of a worker; 0 leaves it unlimited.
+	MemoryBytes int64
+	// CPUSeconds caps the CPU time of each job; 0 leaves it unlimited.
+	CPUSeconds int
+	// Seccomp denies workers and the tools they run network, tracing, mount
+	// and kernel module syscalls.
+	Seccomp bool
+}
+
+// Pool hands jobs to re-executed copies of the running binary, one job per
+// worker at a time. Workers that crash, or whose request is cancelled, are
+// killed and replaced on the next job.
+type Pool struct {
+	config  Config
+	command string
+	// slots holds one entry per worker, nil until the worker is started.
+	slots chan *process
+}
+
+// NewPool starts the workers. The binary must call Main when it is run with
+// Command as its first argument.
+func NewPool(config Config) (*Pool, error) {
+	if config.Workers <= 0 {
+		return nil, fmt.Errorf("worker pool needs at least one worker, got %d", config.Workers)
+	}
+	command, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("find executable: %w", err)
+	}
+
+	pool := &Pool{config: config, command: command, slots: make(chan *process, config.Workers)}
+	for range config.Workers {
+		worker, err := pool.start(context.Background())
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.slots <- worker
+	}
+
+	return pool, nil
+}
+
+// Convert plans and runs the conversion in a worker.
+func (p *Pool) Convert(ctx context.Context, from string, to string, input []byte, options converter.Options) (converter.Result, error) {
+	reply, err := p.run(ctx, request{From: from, To: to, Input: input, Options: options})
+	if err != nil {
+		return converter.Result{}, err
+	}
+
+	return reply.Result, nil
+}
+
+// Combine runs converter.Combine in a worker.
+func (p *Pool) Combine(ctx context.Context, inputs [][]byte, target string, options converter.CombineOptions) (converter.CombineResult, error) {
+	reply, err := p.run(ctx, request{To: target, Inputs: inputs, CombineOptions: options})
+	if err != nil {
+		return converter.CombineResult{}, err
+	}
+
+	return reply.CombineResult, nil
+}
+
+// Close stops the idle workers. Workers busy with a job stop when it ends.
+func (p *Pool) Close() {
+	for {
+		select {
+		case worker := <-p.slots:
+			if worker != nil {
+				worker.stop()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *Pool) run(ctx context.Context, job request) (response, error) {
+	var worker *process
+	select {
+	case worker = <-p.slots:
+	case <-ctx.Done():
+		return response{}, ctx.Err()
+	}
+	if worker == nil {
+		var err error
+		if worker, err = p.start(ctx); err != nil {
+			p.slots <- nil
+			return response{}, err
+		}
+	}
+
+	reply, err := worker.call(ctx, &job)
+	if err != nil || (p.config.MaxJobs > 0 && worker.jobs >= p.config.MaxJobs) {
+		worker.stop()
+		worker = nil
+	}
+	p.slots <- worker
+	if err != nil {
+		return response{}, err
+	}
+
+	return reply, reply.err()
+}
+
+func (p *Pool) start(ctx context.Context) (*process, error) {
+	worker, err := startProcess(p.command, p.config.Limits.args())
+	if err != nil {
+		return nil, fmt.Errorf("start worker: %w", err)
+	}
+	// Workers announce themselves once their limits are in place.
+	if _, err := worker.call(ctx, nil); err != nil {
+		worker.stop()
+		return nil, fmt.Errorf("start worker: %w", err)
+	}
+
+	return worker, nil
+}
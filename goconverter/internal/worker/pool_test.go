@@ -0,0 +1,231 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"goconverter/internal/converter"
+)
+
+// TestMain lets the test binary serve as its own worker. Workers get a
+// backend that misbehaves on request, ahead of the real png to jpeg route.
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == Command {
+		converter.RegisterBackend(converter.Backend{
+			Name:       "scripted",
+			Priority:   1000,
+			Converters: func() []converter.Converter { return []converter.Converter{scriptedConverter{}} },
+		})
+		if err := Main(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// scriptedConverter acts on inputs naming an action and leaves real images
+// to the next backend.
+type scriptedConverter struct{}
+
+func (scriptedConverter) SourceFormat() string { return "png" }
+
+func (scriptedConverter) TargetFormat() string { return "jpeg" }
+
+func (c scriptedConverter) Convert(ctx context.Context, input []byte) ([]byte, error) {
+	result, err := c.ConvertWithOptions(ctx, input, converter.Options{})
+	return result.Output, err
+}
+
+func (scriptedConverter) ConvertWithOptions(ctx context.Context, input []byte, _ converter.Options) (converter.Result, error) {
+	switch string(input) {
+	case "progress":
+		converter.ReportProgress(ctx, 40)
+		return converter.Result{Output: []byte("done")}, nil
+	case "pid":
+		return converter.Result{Output: []byte(strconv.Itoa(os.Getpid()))}, nil
+	case "crash":
+		syscall.Kill(os.Getpid(), syscall.SIGKILL)
+	case "hang":
+		time.Sleep(time.Minute)
+	case "spin":
+		for {
+		}
+	case "socket":
+		fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM, 0)
+		if err == nil {
+			syscall.Close(fd)
+			return converter.Result{Output: []byte("allowed")}, nil
+		}
+		return converter.Result{Output: []byte(err.Error())}, nil
+	case "invalid":
+		return converter.Result{}, fmt.Errorf("%w: quality must be between 1 and 100", converter.ErrInvalidOptions)
+	}
+
+	return converter.Result{}, converter.RetryableError(errors.New("not scripted"))
+}
+
+func TestPoolConvertsInWorkers(t *testing.T) {
+	pool := newTestPool(t, Config{Workers: 1})
+
+	result, err := pool.Convert(context.Background(), "png", "jpeg", mustEncodePNG(t), converter.Options{})
+	if err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(result.Output)); err != nil {
+		t.Fatalf("expected jpeg output, got error: %v", err)
+	}
+	if len(result.Backends) != 1 {
+		t.Fatalf("expected the backend to be reported, got %v", result.Backends)
+	}
+	if pid := mustConvert(t, pool, "pid"); pid == strconv.Itoa(os.Getpid()) {
+		t.Fatal("expected the conversion to run in another process")
+	}
+}
+
+func TestPoolCombinesInWorkers(t *testing.T) {
+	pool := newTestPool(t, Config{Workers: 1})
+
+	result, err := pool.Combine(context.Background(), [][]byte{mustEncodePNG(t), mustEncodePNG(t)}, "pdf", converter.CombineOptions{})
+	if err != nil {
+		t.Fatalf("expected inputs to combine, got error: %v", err)
+	}
+	if result.Pages != 2 || !bytes.HasPrefix(result.Output, []byte("%PDF-")) {
+		t.Fatalf("expected a two page pdf, got %d pages", result.Pages)
+	}
+}
+
+func TestPoolForwardsProgress(t *testing.T) {
+	pool := newTestPool(t, Config{Workers: 1})
+
+	var reported []int
+	ctx := converter.WithProgress(context.Background(), func(percent int) { reported = append(reported, percent) })
+	if _, err := pool.Convert(ctx, "png", "jpeg", []byte("progress"), converter.Options{}); err != nil {
+		t.Fatalf("expected conversion to succeed, got error: %v", err)
+	}
+	if !slices.Equal(reported, []int{40}) {
+		t.Fatalf("expected the worker's progress to be reported, got %v", reported)
+	}
+}
+
+func TestPoolKeepsConverterErrors(t *testing.T) {
+	pool := newTestPool(t, Config{Workers: 1})
+
+	_, err := pool.Convert(context.Background(), "png", "jpeg", []byte("invalid"), converter.Options{})
+	if !errors.Is(err, converter.ErrInvalidOptions) {
+		t.Fatalf("expected invalid options error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "quality must be between 1 and 100") {
+		t.Fatalf("expected the worker's message, got %q", err)
+	}
+}
+
+func TestPoolReplacesCrashedWorkers(t *testing.T) {
+	pool := newTestPool(t, Config{Workers: 1})
+	before := mustConvert(t, pool, "pid")
+
+	_, err := pool.Convert(context.Background(), "png", "jpeg", []byte("crash"), converter.Options{})
+	if !errors.Is(err, ErrCrashed) {
+		t.Fatalf("expected worker crashed error, got %v", err)
+	}
+	if after := mustConvert(t, pool, "pid"); after == before {
+		t.Fatal("expected a new worker after the crash")
+	}
+}
+
+func TestPoolRecyclesWorkersAfterMaxJobs(t *testing.T) {
+	pool := newTestPool(t, Config{Workers: 1, MaxJobs: 2})
+
+	first, second, third := mustConvert(t, pool, "pid"), mustConvert(t, pool, "pid"), mustConvert(t, pool, "pid")
+	if first != second || second == third {
+		t.Fatalf("expected the worker to be replaced after two jobs, got %s, %s, %s", first, second, third)
+	}
+}
+
+func TestPoolKillsWorkersOfCancelledRequests(t *testing.T) {
+	pool := newTestPool(t, Config{Workers: 1})
+	before := mustConvert(t, pool, "pid")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	startedAt := time.Now()
+	_, err := pool.Convert(ctx, "png", "jpeg", []byte("hang"), converter.Options{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request deadline error, got %v", err)
+	}
+	if time.Since(startedAt) > 3*time.Second {
+		t.Fatalf("expected the worker to be stopped promptly, took %s", time.Since(startedAt))
+	}
+	if after := mustConvert(t, pool, "pid"); after == before {
+		t.Fatal("expected a new worker after the cancellation")
+	}
+}
+
+func TestPoolEnforcesCPULimit(t *testing.T) {
+	pool := newTestPool(t, Config{Workers: 1, Limits: Limits{CPUSeconds: 1}})
+
+	_, err := pool.Convert(context.Background(), "png", "jpeg", []byte("spin"), converter.Options{})
+	if !errors.Is(err, ErrCrashed) || !strings.Contains(err.Error(), "CPU limit") {
+		t.Fatalf("expected the CPU limit to stop the worker, got %v", err)
+	}
+}
+
+func TestPoolAppliesSeccompFilter(t *testing.T) {
+	pool, err := NewPool(Config{Workers: 1, Limits: Limits{Seccomp: true}})
+	if err != nil {
+		t.Skipf("seccomp is unavailable here: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if output := mustConvert(t, pool, "socket"); output != syscall.EPERM.Error() {
+		t.Fatalf("expected socket to be denied, got %q", output)
+	}
+}
+
+func newTestPool(t *testing.T, config Config) *Pool {
+	t.Helper()
+
+	pool, err := NewPool(config)
+	if err != nil {
+		t.Fatalf("failed to start pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func mustConvert(t *testing.T, pool *Pool, action string) string {
+	t.Helper()
+
+	result, err := pool.Convert(context.Background(), "png", "jpeg", []byte(action), converter.Options{})
+	if err != nil {
+		t.Fatalf("%s: expected conversion to succeed, got error: %v", action, err)
+	}
+
+	return string(result.Output)
+}
+
+func mustEncodePNG(t *testing.T) []byte {
+	t.Helper()
+
+	var output bytes.Buffer
+	if err := png.Encode(&output, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+
+	return output.Bytes()
+}
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+
+	"goconverter/internal/converter"
+)
+
+// process is a running worker and its ends of the pipes.
+type process struct {
+	cmd       *exec.Cmd
+	requests  *os.File
+	responses *os.File
+	encoder   *gob.Encoder
+	decoder   *gob.Decoder
+	exited    chan struct{}
+	stopped   sync.Once
+	jobs      int
+}
+
+func startProcess(command string, args []string) (*process, error) {
+	requestsReader, requestsWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	responsesReader, responsesWriter, err := os.Pipe()
+	if err != nil {
+		requestsReader.Close()
+		requestsWriter.Close()
+		return nil, err
+	}
+
+	cmd := exec.Command(command, args...)
+	cmd.ExtraFiles = []*os.File{requestsReader, responsesWriter}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	err = cmd.Start()
+	// The worker holds its own copies of these ends.
+	requestsReader.Close()
+	responsesWriter.Close()
+	if err != nil {
+		requestsWriter.Close()
+		responsesReader.Close()
+		return nil, err
+	}
+
+	p := &process{
+		cmd:       cmd,
+		requests:  requestsWriter,
+		responses: responsesReader,
+		encoder:   gob.NewEncoder(requestsWriter),
+		decoder:   gob.NewDecoder(responsesReader),
+		exited:    make(chan struct{}),
+	}
+	go func() {
+		cmd.Wait()
+		close(p.exited)
+	}()
+
+	return p, nil
+}
+
+// call sends job, or nothing when job is nil, and reads its response,
+// passing the progress the worker sends on to ctx. The worker is killed when
+// ctx is done first; a worker that dies before answering is reported as
+// crashed.
+func (p *process) call(ctx context.Context, job *request) (response, error) {
+	if job != nil {
+		p.jobs++
+	}
+
+	type reply struct {
+		response response
+		err      error
+	}
+	replies := make(chan reply, 1)
+	go func() {
+		if job != nil {
+			if err := p.encoder.Encode(job); err != nil {
+				replies <- reply{err: err}
+				return
+			}
+		}
+		for {
+			var r response
+			err := p.decoder.Decode(&r)
+			if err == nil && r.Progress != nil {
+				converter.ReportProgress(ctx, *r.Progress)
+				continue
+			}
+			replies <- reply{response: r, err: err}
+			return
+		}
+	}()
+
+	select {
+	case r := <-replies:
+		if r.err != nil {
+			p.stop()
+			return response{}, fmt.Errorf("%w: %s", ErrCrashed, p.exitReason())
+		}
+		return r.response, nil
+	case <-ctx.Done():
+		p.stop()
+		<-replies
+		return response{}, ctx.Err()
+	}
+}
+
+// stop kills the worker and waits for it to exit.
+func (p *process) stop() {
+	p.stopped.Do(func() {
+		p.cmd.Process.Kill()
+		<-p.exited
+		p.requests.Close()
+		p.responses.Close()
+	})
+}
+
+func (p *process) exitReason() string {
+	state := p.cmd.ProcessState
+	if state.ExitCode() == exitCPULimit {
+		return "exceeded its CPU limit"
+	}
+
+	return state.String()
+}
@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"errors"
+
+	"goconverter/internal/converter"
+)
+
+// request is one job sent to a worker. Inputs is set instead of Input for
+// combine jobs.
+type request struct {
+	From           string
+	To             string
+	Input          []byte
+	Options        converter.Options
+	Inputs         [][]byte
+	CombineOptions converter.CombineOptions
+}
+
+// response answers a request. Workers also send an empty response once they
+// are ready to take jobs, and responses with only Progress set while a job
+// runs.
+type response struct {
+	Result        converter.Result
+	CombineResult converter.CombineResult
+	Error         string
+	ErrorKind     string
+	Progress      *int
+}
+
+// errorKinds are the converter errors that keep their identity across the
+// pipe, so callers can still match them with errors.Is. Retryable errors may
+// wrap one of the others, so it comes last.
+var errorKinds = []struct {
+	kind string
+	err  error
+}{
+	{kind: "invalid_options", err: converter.ErrInvalidOptions},
+	{kind: "input_too_complex", err: converter.ErrInputTooComplex},
+	{kind: "unsupported_input", err: converter.ErrUnsupportedInput},
+	{kind: "retryable", err: converter.ErrRetryable},
+}
+
+func errorResponse(err error) response {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.err) {
+			return response{Error: err.Error(), ErrorKind: kind.kind}
+		}
+	}
+
+	return response{Error: err.Error()}
+}
+
+// err rebuilds the error a worker reported, or returns nil.
+func (r response) err() error {
+	if r.Error == "" {
+		return nil
+	}
+
+	remote := remoteError{message: r.Error}
+	for _, kind := range errorKinds {
+		if kind.kind == r.ErrorKind {
+			remote.kind = kind.err
+			break
+		}
+	}
+
+	return remote
+}
+
+type remoteError struct {
+	message string
+	kind    error
+}
+
+func (e remoteError) Error() string {
+	return e.message
+}
+
+func (e remoteError) Unwrap() error {
+	return e.kind
+}
@@ -0,0 +1,22 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"goconverter/internal/converter"
+)
+
+func TestErrorResponseKeepsTheSpecificKindOfRetryableErrors(t *testing.T) {
+	for _, target := range []error{converter.ErrInvalidOptions, converter.ErrInputTooComplex, converter.ErrUnsupportedInput} {
+		err := errorResponse(converter.RetryableError(fmt.Errorf("convert: %w", target))).err()
+		if !errors.Is(err, target) {
+			t.Fatalf("expected %v to cross the pipe, got %v", target, err)
+		}
+	}
+
+	if err := errorResponse(converter.RetryableError(errors.New("no loader"))).err(); !errors.Is(err, converter.ErrRetryable) {
+		t.Fatalf("expected a retryable error, got %v", err)
+	}
+}
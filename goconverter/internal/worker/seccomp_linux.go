@@ -0,0 +1,84 @@
+//go:build linux && (amd64 || arm64)
+
+package worker
+
+import (
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// deniedSyscalls fail with EPERM in workers. Conversions never need them;
+// execve stays allowed so external tools keep working, and they inherit the
+// filter.
+var deniedSyscalls = []uintptr{
+	unix.SYS_SOCKET, unix.SYS_SOCKETPAIR, unix.SYS_CONNECT, unix.SYS_BIND, unix.SYS_LISTEN, unix.SYS_ACCEPT, unix.SYS_ACCEPT4,
+	unix.SYS_PTRACE, unix.SYS_PROCESS_VM_READV, unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_MOUNT, unix.SYS_UMOUNT2, unix.SYS_PIVOT_ROOT, unix.SYS_CHROOT, unix.SYS_SETNS, unix.SYS_UNSHARE,
+	unix.SYS_INIT_MODULE, unix.SYS_FINIT_MODULE, unix.SYS_DELETE_MODULE, unix.SYS_KEXEC_LOAD,
+	unix.SYS_BPF, unix.SYS_PERF_EVENT_OPEN, unix.SYS_KEYCTL, unix.SYS_ADD_KEY, unix.SYS_REQUEST_KEY,
+}
+
+var auditArchitectures = map[string]uint32{
+	"amd64": unix.AUDIT_ARCH_X86_64,
+	"arm64": unix.AUDIT_ARCH_AARCH64,
+}
+
+// x32SyscallBit marks x32 system calls, which share the x86-64 audit
+// architecture and would otherwise get around the filter.
+const x32SyscallBit = 0x40000000
+
+// installSeccomp loads the filter on every thread of the worker, including
+// those libvips starts later.
+func installSeccomp() error {
+	filter := seccompFilter(auditArchitectures[runtime.GOARCH], runtime.GOARCH == "amd64")
+	program := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return err
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER, unix.SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(&program))); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func seccompFilter(arch uint32, x32 bool) []unix.SockFilter {
+	const (
+		archOffset = 4
+		nrOffset   = 0
+	)
+	load := func(offset uint32) unix.SockFilter {
+		return unix.SockFilter{Code: unix.BPF_LD | unix.BPF_W | unix.BPF_ABS, K: offset}
+	}
+	ret := func(value uint32) unix.SockFilter {
+		return unix.SockFilter{Code: unix.BPF_RET | unix.BPF_K, K: value}
+	}
+
+	// Calls made under another architecture kill the worker; listed calls
+	// return EPERM and the rest are allowed.
+	filter := []unix.SockFilter{
+		load(archOffset),
+		{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, Jt: 1, K: arch},
+		ret(unix.SECCOMP_RET_KILL_PROCESS),
+		load(nrOffset),
+	}
+	if x32 {
+		filter = append(filter,
+			unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JGE | unix.BPF_K, Jf: 1, K: x32SyscallBit},
+			ret(unix.SECCOMP_RET_KILL_PROCESS),
+		)
+	}
+	for _, nr := range deniedSyscalls {
+		filter = append(filter,
+			unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, Jf: 1, K: uint32(nr)},
+			ret(unix.SECCOMP_RET_ERRNO|uint32(unix.EPERM)),
+		)
+	}
+
+	return append(filter, ret(unix.SECCOMP_RET_ALLOW))
+}
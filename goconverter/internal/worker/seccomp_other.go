@@ -0,0 +1,12 @@
+//go:build !linux || !(amd64 || arm64)
+
+package worker
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func installSeccomp() error {
+	return fmt.Errorf("seccomp is not supported on %s/%s", runtime.GOOS, runtime.GOARCH)
+}
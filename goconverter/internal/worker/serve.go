@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"goconverter/internal/converter"
+
+	"golang.org/x/sys/unix"
+)
+
+// Command is the first argument that makes the goconverter binary run as a
+// worker instead of serving HTTP.
+const Command = "worker"
+
+// Workers talk to the pool over two inherited pipes rather than stdin and
+// stdout, so nothing a native library prints can corrupt the protocol.
+const (
+	requestsFD  = 3
+	responsesFD = 4
+)
+
+// exitCPULimit is the exit status of a worker stopped by its CPU limit.
+const exitCPULimit = 3
+
+// Main runs a worker: it applies the limits passed in args, then converts
+// requests until the pool closes the pipe. The converter registry must be
+// configured as in the parent before Main is called.
+func Main(args []string) error {
+	limits, err := parseLimits(args)
+	if err != nil {
+		return err
+	}
+
+	if limits.MemoryBytes > 0 {
+		limit := unix.Rlimit{Cur: uint64(limits.MemoryBytes), Max: uint64(limits.MemoryBytes)}
+		if err := unix.Setrlimit(unix.RLIMIT_AS, &limit); err != nil {
+			return fmt.Errorf("set memory limit: %w", err)
+		}
+	}
+	if limits.CPUSeconds > 0 {
+		// The Go runtime ignores SIGXCPU, so the soft limit would only warn.
+		exceeded := make(chan os.Signal, 1)
+		signal.Notify(exceeded, syscall.SIGXCPU)
+		go func() {
+			<-exceeded
+			log.Printf("worker %d exceeded its CPU limit of %ds", os.Getpid(), limits.CPUSeconds)
+			os.Exit(exitCPULimit)
+		}()
+	}
+	if limits.Seccomp {
+		if err := installSeccomp(); err != nil {
+			return fmt.Errorf("install seccomp filter: %w", err)
+		}
+	}
+
+	return serve(os.NewFile(requestsFD, "requests"), os.NewFile(responsesFD, "responses"), limits)
+}
+
+func parseLimits(args []string) (Limits, error) {
+	var limits Limits
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	flags.Int64Var(&limits.MemoryBytes, "memory-bytes", 0, "address space limit in bytes")
+	flags.IntVar(&limits.CPUSeconds, "cpu-seconds", 0, "CPU time limit per job in seconds")
+	flags.BoolVar(&limits.Seccomp, "seccomp", false, "deny network, tracing, mount and module syscalls")
+	if err := flags.Parse(args); err != nil {
+		return Limits{}, err
+	}
+
+	return limits, nil
+}
+
+func (l Limits) args() []string {
+	args := []string{Command, fmt.Sprintf("-memory-bytes=%d", l.MemoryBytes), fmt.Sprintf("-cpu-seconds=%d", l.CPUSeconds)}
+	if l.Seccomp {
+		args = append(args, "-seccomp")
+	}
+
+	return args
+}
+
+func serve(requests io.Reader, responses io.Writer, limits Limits) error {
+	decoder := gob.NewDecoder(requests)
+	encoder := gob.NewEncoder(responses)
+	if err := encoder.Encode(response{}); err != nil {
+		return fmt.Errorf("announce worker: %w", err)
+	}
+	// Progress is sent from libvips threads while the job runs.
+	var encoding sync.Mutex
+	report := func(percent int) {
+		encoding.Lock()
+		defer encoding.Unlock()
+		encoder.Encode(response{Progress: &percent})
+	}
+
+	for {
+		var job request
+		if err := decoder.Decode(&job); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read request: %w", err)
+		}
+		if limits.CPUSeconds > 0 {
+			if err := limitCPU(limits.CPUSeconds); err != nil {
+				return err
+			}
+		}
+		reply := handle(converter.WithProgress(context.Background(), report), job)
+		encoding.Lock()
+		err := encoder.Encode(reply)
+		encoding.Unlock()
+		if err != nil {
+			return fmt.Errorf("write response: %w", err)
+		}
+	}
+}
+
+// limitCPU moves the soft CPU limit to seconds past the time used so far, so
+// the limit applies to each job rather than to the life of the worker.
+func limitCPU(seconds int) error {
+	var usage unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_SELF, &usage); err != nil {
+		return fmt.Errorf("read CPU usage: %w", err)
+	}
+	var limit unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_CPU, &limit); err != nil {
+		return fmt.Errorf("read CPU limit: %w", err)
+	}
+
+	used := uint64(usage.Utime.Sec + usage.Stime.Sec + 1)
+	limit.Cur = min(used+uint64(seconds), limit.Max)
+	if err := unix.Setrlimit(unix.RLIMIT_CPU, &limit); err != nil {
+		return fmt.Errorf("set CPU limit: %w", err)
+	}
+
+	return nil
+}
+
+// handle runs one job. ctx only carries progress reporting; the pool kills
+// the worker instead of cancelling it when a request is cancelled.
+func handle(ctx context.Context, job request) response {
+	if job.Inputs != nil {
+		result, err := converter.Combine(ctx, job.Inputs, job.To, job.CombineOptions)
+		if err != nil {
+			return errorResponse(err)
+		}
+		return response{CombineResult: result}
+	}
+
+	plan, ok := converter.PlanConversion(job.From, job.To)
+	if !ok {
+		return errorResponse(fmt.Errorf("conversion from %s to %s is not supported", job.From, job.To))
+	}
+	result, err := plan.ConvertWithOptions(ctx, job.Input, job.Options)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return response{Result: result}
+}